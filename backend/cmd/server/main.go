@@ -6,12 +6,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/acme"
 	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/dns"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/makeitexist/backend/internal/export"
 	"github.com/makeitexist/backend/internal/handler"
+	"github.com/makeitexist/backend/internal/invoice"
+	"github.com/makeitexist/backend/internal/payment"
 	"github.com/makeitexist/backend/internal/repository"
 	"github.com/makeitexist/backend/internal/router"
 	"github.com/makeitexist/backend/internal/service"
@@ -28,6 +37,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatal().Err(err).Msg("Invalid configuration")
+	}
 	log.Info().
 		Str("port", cfg.Server.Port).
 		Str("env", cfg.Server.Env).
@@ -59,23 +71,105 @@ func main() {
 	runMigrations(ctx, db)
 
 	// Initialize repositories
+	transactor := repository.NewTransactor(db)
 	userRepo := repository.NewUserRepository(db)
 	requestRepo := repository.NewBuildRequestRepository(db)
 	scheduleRepo := repository.NewScheduleRepository(db)
+	pricingRepo := repository.NewPricingRepository(db)
+	quoteRepo := repository.NewQuoteRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	quotaOverrideRepo := repository.NewQuotaOverrideRepository(db)
+	deliverableRepo := repository.NewDeliverableRepository(db)
+	acceptanceRepo := repository.NewAcceptanceRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	importRepo := repository.NewImportRepository(db)
+	automationRepo := repository.NewAutomationRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	domainVerificationRepo := repository.NewDomainVerificationRepository(db)
+	certificateRepo := repository.NewCertificateRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	queueRepo := repository.NewQueueRepository(db)
+	standbyRepo := repository.NewStandbyRepository(db)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+
+	// Payment gateway
+	paymentProvider, err := payment.NewProvider(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure payment provider")
+	}
+
+	// Invoice documents
+	invoiceRenderer, err := invoice.NewPDFRenderer(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load invoice templates")
+	}
+
+	// TLS certificates for whitelabel domains (nil when ACME is off)
+	certificateIssuer, err := acme.NewIssuer(cfg, certificateRepo)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure ACME")
+	}
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, cfg)
-	requestService := service.NewRequestService(requestRepo, userRepo)
-	scheduleService := service.NewScheduleService(scheduleRepo, requestRepo)
+	pricingService := service.NewPricingService(pricingRepo, requestRepo, promotionRepo)
+	quoteService := service.NewQuoteService(transactor, quoteRepo, requestRepo, pricingService)
+	paymentService := service.NewPaymentService(transactor, paymentRepo, quoteRepo, requestRepo, promotionRepo, paymentProvider, cfg.Payment.RequirePaidBeforeDeploy)
+	promotionService := service.NewPromotionService(transactor, promotionRepo, requestRepo, quoteRepo, pricingService)
+	invoiceService := service.NewInvoiceService(invoiceRepo, requestRepo, userRepo, quoteRepo, paymentRepo, invoiceRenderer, cfg)
+	intakeService := service.NewIntakeService(quotaOverrideRepo, requestRepo, userRepo, pricingService, cfg.Intake)
+	domainVerificationService := service.NewDomainVerificationService(domainVerificationRepo, dns.NewResolver(cfg))
+	lifecycle := &domain.Lifecycle{
+		Guards:    []domain.TransitionGuard{domainVerificationService, quoteService, paymentService},
+		Observers: []domain.TransitionObserver{invoiceService},
+	}
+	// Deliverables and student sign-off drive the request status through the
+	// same lifecycle, and guard it against hand edits that contradict them.
+	// Services hold the pointer, so they see guards and observers added here.
+	deliverableService := service.NewDeliverableService(transactor, deliverableRepo, requestRepo, lifecycle)
+	lifecycle.Guards = append(lifecycle.Guards, deliverableService)
+	acceptanceService := service.NewAcceptanceService(transactor, acceptanceRepo, requestRepo, deliverableService, lifecycle, cfg.Delivery)
+	lifecycle.Guards = append(lifecycle.Guards, acceptanceService)
+	// Requests that leave the schedule give their slot to the next standby
+	scheduleService := service.NewScheduleService(transactor, scheduleRepo, requestRepo, standbyRepo, availabilityRepo, notificationRepo, lifecycle)
+	lifecycle.Observers = append(lifecycle.Observers, scheduleService)
+	exportService := service.NewExportService(exportRepo, export.NewTableWriter)
+	importService := service.NewImportService(importRepo)
+	requestService := service.NewRequestService(transactor, requestRepo, userRepo, pricingService, intakeService, scheduleService, lifecycle)
+	automationService := service.NewAutomationService(automationRepo, notificationRepo, requestService)
+	notificationService := service.NewNotificationService(notificationRepo)
+	certificateService := service.NewCertificateService(certificateRepo, domainVerificationRepo, certificateIssuer)
+	queueService := service.NewQueueService(queueRepo, scheduleRepo, cfg.Queue)
+	availabilityService := service.NewAvailabilityService(availabilityRepo, scheduleService)
+	plannerService := service.NewPlannerService(transactor, queueService, scheduleService, scheduleRepo, requestRepo, cfg.Queue, lifecycle)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	requestHandler := handler.NewRequestHandler(requestService)
 	scheduleHandler := handler.NewScheduleHandler(scheduleService)
-	adminHandler := handler.NewAdminHandler(requestService, scheduleService, authService)
+	adminHandler := handler.NewAdminHandler(requestService, scheduleService, authService, acceptanceService)
+	pricingHandler := handler.NewPricingHandler(pricingService, requestService)
+	quoteHandler := handler.NewQuoteHandler(quoteService, requestService)
+	paymentHandler := handler.NewPaymentHandler(paymentService, requestService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService, requestService)
+	promotionHandler := handler.NewPromotionHandler(promotionService, requestService)
+	intakeHandler := handler.NewIntakeHandler(intakeService)
+	deliverableHandler := handler.NewDeliverableHandler(deliverableService, requestService)
+	acceptanceHandler := handler.NewAcceptanceHandler(acceptanceService, requestService)
+	exportHandler := handler.NewExportHandler(exportService)
+	importHandler := handler.NewImportHandler(importService)
+	automationHandler := handler.NewAutomationHandler(automationService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	domainHandler := handler.NewDomainVerificationHandler(domainVerificationService, requestService)
+	certificateHandler := handler.NewCertificateHandler(certificateService, requestService)
+	queueHandler := handler.NewQueueHandler(queueService, requestService)
+	plannerHandler := handler.NewPlannerHandler(plannerService)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilityService)
 
 	// Setup router
-	r := router.Setup(cfg, authHandler, requestHandler, scheduleHandler, adminHandler)
+	r := router.Setup(cfg, idempotencyRepo, authHandler, requestHandler, scheduleHandler, adminHandler, pricingHandler, quoteHandler, paymentHandler, invoiceHandler, promotionHandler, intakeHandler, deliverableHandler, acceptanceHandler, exportHandler, importHandler, automationHandler, notificationHandler, domainHandler, certificateHandler, queueHandler, plannerHandler, availabilityHandler)
 
 	// Auto-generate weekend slots for next 8 weeks
 	go func() {
@@ -86,6 +180,54 @@ func main() {
 		}
 	}()
 
+	// Run automation rules periodically
+	if cfg.Automation.Interval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Automation.Interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				runs, err := automationService.RunAll(ctx)
+				if err != nil {
+					log.Warn().Err(err).Msg("Failed to run automation rules")
+					continue
+				}
+				for _, run := range runs {
+					if run.Matched > 0 {
+						log.Info().Str("rule", run.Name).Int("succeeded", run.Succeeded).
+							Int("failed", run.Failed).Msg("🤖 Automation rule applied")
+					}
+				}
+			}
+		}()
+	}
+
+	// Issue new certificates and renew expiring ones
+	if certificateIssuer != nil && cfg.ACME.RenewInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.ACME.RenewInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				issued, err := certificateService.RenewDue(ctx)
+				if err != nil {
+					log.Warn().Err(err).Msg("Failed to renew certificates")
+				} else if issued > 0 {
+					log.Info().Int("issued", issued).Msg("🔒 Certificates issued")
+				}
+			}
+		}()
+	}
+
+	// Purge expired idempotency keys
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := idempotencyRepo.DeleteExpired(ctx, time.Now()); err != nil {
+				log.Warn().Err(err).Msg("Failed to purge idempotency keys")
+			}
+		}
+	}()
+
 	// Create HTTP server with timeouts
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
@@ -119,40 +261,91 @@ func main() {
 	log.Info().Msg("👋 Server stopped gracefully")
 }
 
-// runMigrations executes the SQL migration file if the users table doesn't exist yet.
+// runMigrations applies every migrations/*.up.sql file that has not been
+// recorded in schema_migrations yet, in filename order.
 func runMigrations(ctx context.Context, db *pgxpool.Pool) {
-	// Check if migrations already applied (users table exists)
-	var exists bool
-	err := db.QueryRow(ctx,
-		`SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'users')`).Scan(&exists)
-	if err != nil {
-		log.Warn().Err(err).Msg("Could not check migration status")
+	// Try to locate the migrations directory
+	migrationDirs := []string{
+		"migrations",    // Docker / production
+		"../migrations", // local dev from cmd/server
+	}
+	var files []string
+	for _, dir := range migrationDirs {
+		files, _ = filepath.Glob(filepath.Join(dir, "*.up.sql"))
+		if len(files) > 0 {
+			break
+		}
+	}
+	if len(files) == 0 {
+		log.Warn().Msg("Migration files not found — tables must be created manually")
 		return
 	}
-	if exists {
-		log.Info().Msg("📦 Database tables already exist, skipping migration")
+	sort.Strings(files)
+
+	if _, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		log.Warn().Err(err).Msg("Could not check migration status")
 		return
 	}
 
-	// Try to read and execute migration file
-	migrationPaths := []string{
-		"migrations/001_initial_schema.up.sql",    // Docker / production
-		"../migrations/001_initial_schema.up.sql",  // local dev from cmd/server
+	// Databases created before migrations were tracked already carry the
+	// initial schema (e.g. via docker-entrypoint-initdb.d).
+	var tracked int
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&tracked); err != nil {
+		log.Warn().Err(err).Msg("Could not check migration status")
+		return
 	}
-	var sqlBytes []byte
-	for _, p := range migrationPaths {
-		sqlBytes, err = os.ReadFile(p)
-		if err == nil {
-			break
+	if tracked == 0 {
+		var exists bool
+		err := db.QueryRow(ctx,
+			`SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'users')`).Scan(&exists)
+		if err == nil && exists {
+			_, _ = db.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ('001_initial_schema')`)
+			log.Info().Msg("📦 Existing database detected, marked initial schema as applied")
 		}
 	}
-	if err != nil {
-		log.Warn().Msg("Migration file not found — tables must be created manually")
-		return
+
+	applied := 0
+	for _, file := range files {
+		version := strings.TrimSuffix(filepath.Base(file), ".up.sql")
+
+		var done bool
+		if err := db.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version).Scan(&done); err != nil {
+			log.Fatal().Err(err).Str("version", version).Msg("Failed to check migration")
+		}
+		if done {
+			continue
+		}
+
+		sqlBytes, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatal().Err(err).Str("file", file).Msg("Failed to read migration file")
+		}
+
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to begin migration transaction")
+		}
+		if _, err := tx.Exec(ctx, string(sqlBytes)); err != nil {
+			_ = tx.Rollback(ctx)
+			log.Fatal().Err(err).Str("version", version).Msg("Failed to run database migration")
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			_ = tx.Rollback(ctx)
+			log.Fatal().Err(err).Str("version", version).Msg("Failed to record database migration")
+		}
+		if err := tx.Commit(ctx); err != nil {
+			log.Fatal().Err(err).Str("version", version).Msg("Failed to commit database migration")
+		}
+		log.Info().Str("version", version).Msg("✅ Database migration applied")
+		applied++
 	}
 
-	if _, err := db.Exec(ctx, string(sqlBytes)); err != nil {
-		log.Fatal().Err(err).Msg("Failed to run database migration")
+	if applied == 0 {
+		log.Info().Msg("📦 Database schema is up to date")
 	}
-	log.Info().Msg("✅ Database migration applied successfully")
 }
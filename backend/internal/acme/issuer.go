@@ -0,0 +1,246 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+	"golang.org/x/crypto/acme"
+)
+
+// Issuer obtains certificates from an ACME CA using HTTP-01 challenges.
+// Challenges are published through the store and answered by the router at
+// /.well-known/acme-challenge/:token, so any instance can serve them.
+type Issuer struct {
+	directoryURL string
+	email        string
+	httpClient   *http.Client
+	store        domain.ACMEStore
+
+	mu     sync.Mutex
+	client *acme.Client // registered lazily on first use
+}
+
+// NewIssuer returns the ACME issuer configured by ACME_DIRECTORY_URL, or nil
+// when it is unset and certificates are not provisioned. ACME_CA_BUNDLE adds
+// a trusted root for talking to the directory, e.g. Pebble's test CA.
+func NewIssuer(cfg *config.Config, store domain.ACMEStore) (domain.CertificateIssuer, error) {
+	if cfg.ACME.DirectoryURL == "" {
+		return nil, nil
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if cfg.ACME.CABundle != "" {
+		pemBytes, err := os.ReadFile(cfg.ACME.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ACME CA bundle: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pemBytes) {
+			return nil, fmt.Errorf("no certificates found in ACME CA bundle %s", cfg.ACME.CABundle)
+		}
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: roots},
+		}
+	}
+
+	return &Issuer{
+		directoryURL: cfg.ACME.DirectoryURL,
+		email:        cfg.ACME.Email,
+		httpClient:   httpClient,
+		store:        store,
+	}, nil
+}
+
+// Issue runs a full ACME order for one domain: authorize with HTTP-01,
+// finalize with a fresh P-256 key and download the chain
+func (i *Issuer) Issue(ctx context.Context, name string) (*domain.IssuedCertificate, error) {
+	client, err := i.account(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		if err := i.authorize(ctx, client, authzURL, name); err != nil {
+			return nil, err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, fmt.Errorf("order did not become ready: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: name},
+		DNSNames: []string{name},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize order: %w", err)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("the CA returned an empty certificate chain")
+	}
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, fmt.Errorf("the CA returned an invalid certificate: %w", err)
+	}
+
+	var certPEM strings.Builder
+	for _, der := range chain {
+		_ = pem.Encode(&certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	keyPEM, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	issuer := leaf.Issuer.CommonName
+	if issuer == "" && len(leaf.Issuer.Organization) > 0 {
+		issuer = leaf.Issuer.Organization[0]
+	}
+	return &domain.IssuedCertificate{
+		CertPEM:   certPEM.String(),
+		KeyPEM:    keyPEM,
+		Issuer:    issuer,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+	}, nil
+}
+
+// authorize completes one authorization with an HTTP-01 challenge
+func (i *Issuer) authorize(ctx context.Context, client *acme.Client, authzURL, name string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch authorization: %w", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("the CA offered no http-01 challenge for %s", name)
+	}
+
+	keyAuth, err := client.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return err
+	}
+	if err := i.store.PutChallenge(ctx, chal.Token, keyAuth, name); err != nil {
+		return fmt.Errorf("failed to publish challenge: %w", err)
+	}
+	defer func() {
+		// The challenge is useless once the authorization settles
+		_ = i.store.DeleteChallenge(context.WithoutCancel(ctx), chal.Token)
+	}()
+
+	if _, err := client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("failed to accept challenge: %w", err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("http-01 validation of %s failed: %w", name, err)
+	}
+	return nil
+}
+
+// account returns a client registered with the directory, creating and
+// storing the account key on first use
+func (i *Issuer) account(ctx context.Context) (*acme.Client, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.client != nil {
+		return i.client, nil
+	}
+
+	keyPEM, err := i.store.AccountKey(ctx, i.directoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ACME account key: %w", err)
+	}
+	var key crypto.Signer
+	if keyPEM == "" {
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		if keyPEM, err = encodeKey(ecKey); err != nil {
+			return nil, err
+		}
+		if err := i.store.SaveAccountKey(ctx, i.directoryURL, keyPEM); err != nil {
+			return nil, fmt.Errorf("failed to save ACME account key: %w", err)
+		}
+		// Another instance may have saved first; use whichever key was kept
+		if keyPEM, err = i.store.AccountKey(ctx, i.directoryURL); err != nil {
+			return nil, fmt.Errorf("failed to load ACME account key: %w", err)
+		}
+	}
+	if key, err = decodeKey(keyPEM); err != nil {
+		return nil, err
+	}
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: i.directoryURL,
+		HTTPClient:   i.httpClient,
+		UserAgent:    "make-it-exist",
+	}
+	account := &acme.Account{}
+	if i.email != "" {
+		account.Contact = []string{"mailto:" + i.email}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("failed to register ACME account: %w", err)
+	}
+	i.client = client
+	return client, nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+}
+
+func decodeKey(keyPEM string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("stored ACME account key is not PEM")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -12,15 +14,24 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	OTP      OTPConfig
-	SMTP     SMTPConfig
-	AIM      AIMConfig
-	Rate     RateConfig
-	CORS     CORSConfig
-	Google   GoogleConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	OTP         OTPConfig
+	SMTP        SMTPConfig
+	AIM         AIMConfig
+	Rate        RateConfig
+	CORS        CORSConfig
+	Google      GoogleConfig
+	Payment     PaymentConfig
+	Invoice     InvoiceConfig
+	Intake      IntakeConfig
+	Delivery    DeliveryConfig
+	Automation  AutomationConfig
+	Domain      DomainConfig
+	ACME        ACMEConfig
+	Idempotency IdempotencyConfig
+	Queue       QueueConfig
 }
 
 type ServerConfig struct {
@@ -78,6 +89,83 @@ type GoogleConfig struct {
 	AllowedDomains []string
 }
 
+type PaymentConfig struct {
+	Provider                string // razorpay or fake
+	RazorpayKeyID           string
+	RazorpayKeySecret       string
+	WebhookSecret           string
+	RequirePaidBeforeDeploy bool
+}
+
+// devWebhookSecret is the published secret in .env.example; events signed
+// with it can be forged by anyone
+const devWebhookSecret = "dev-webhook-secret"
+
+// InvoiceConfig holds the supplier details and tax settings printed on
+// GST invoices and receipts
+type InvoiceConfig struct {
+	SellerName    string
+	SellerAddress string
+	SellerGSTIN   string
+	SellerState   string
+	PlaceOfSupply string // buyer state; defaults to the seller's (intra-state supply)
+	GSTRate       int    // percent
+	SACCode       string
+	NumberPrefix  string // at most MaxInvoicePrefixLen characters
+}
+
+// MaxInvoicePrefixLen keeps document numbers within the 16 characters GST
+// allows: receipts such as MIER/25-26/00007 add an R to the prefix and 12
+// characters of year and sequence.
+const MaxInvoicePrefixLen = 3
+
+// IntakeConfig limits how much a student can submit. A zero limit is
+// unlimited; staff are never limited.
+type IntakeConfig struct {
+	MaxOpenFreeRequests    int           // free requests not yet completed, cancelled or rejected
+	MaxRequestsPerSemester int           // requests created in the current half-year
+	DuplicateThreshold     int           // similarity percent at which a request counts as a near-duplicate
+	DuplicateLookback      time.Duration // how far back to compare against
+	BlockDuplicates        bool          // refuse near-duplicates instead of warning
+}
+
+// DeliveryConfig controls student sign-off of finished work
+type DeliveryConfig struct {
+	IncludedRevisions int  // revision rounds a student can ask for at no charge
+	RequireAcceptance bool // requests complete only once the student accepts
+}
+
+// AutomationConfig controls the background job that runs automation rules
+type AutomationConfig struct {
+	Interval time.Duration // time between runs; zero disables the job
+}
+
+// DomainConfig controls how whitelabel domains are checked in DNS
+type DomainConfig struct {
+	DNSServer     string // host[:port] to query instead of the system resolver
+	LookupTimeout time.Duration
+}
+
+// ACMEConfig controls TLS certificates for whitelabel domains. An empty
+// DirectoryURL turns provisioning off.
+type ACMEConfig struct {
+	DirectoryURL  string        // e.g. Let's Encrypt, or https://localhost:14000/dir for Pebble
+	Email         string        // account contact for expiry notices
+	CABundle      string        // extra PEM root trusted for the directory (Pebble's minica)
+	RenewInterval time.Duration // how often due certificates are issued or renewed
+}
+
+// IdempotencyConfig controls how long Idempotency-Key responses are replayed
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// QueueConfig sets the build queue policy. Both off means first in, first out.
+type QueueConfig struct {
+	OnePerStudent  bool          // students get one queued request built at a time
+	FinalYearBoost time.Duration // head start for students in their final year
+}
+
 // Load reads configuration from environment variables
 func Load() *Config {
 	// Load .env file if it exists (development)
@@ -132,7 +220,75 @@ func Load() *Config {
 			ClientID:       getEnv("GOOGLE_AUTH_CLIENT_ID", ""),
 			AllowedDomains: strings.Split(getEnv("GOOGLE_ALLOWED_DOMAINS", "gmail.com,aim.edu"), ","),
 		},
+		Payment: PaymentConfig{
+			Provider:                getEnv("PAYMENT_PROVIDER", "fake"),
+			RazorpayKeyID:           getEnv("RAZORPAY_KEY_ID", ""),
+			RazorpayKeySecret:       getEnv("RAZORPAY_KEY_SECRET", ""),
+			WebhookSecret:           getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+			RequirePaidBeforeDeploy: getBoolEnv("PAYMENT_REQUIRE_PAID_BEFORE_DEPLOY", true),
+		},
+		Invoice: InvoiceConfig{
+			SellerName:    getEnv("INVOICE_SELLER_NAME", "Make It Exist"),
+			SellerAddress: getEnv("INVOICE_SELLER_ADDRESS", ""),
+			SellerGSTIN:   getEnv("INVOICE_SELLER_GSTIN", ""),
+			SellerState:   getEnv("INVOICE_SELLER_STATE", ""),
+			PlaceOfSupply: getEnv("INVOICE_PLACE_OF_SUPPLY", getEnv("INVOICE_SELLER_STATE", "")),
+			GSTRate:       getIntEnv("INVOICE_GST_RATE", 18),
+			SACCode:       getEnv("INVOICE_SAC_CODE", "998314"),
+			NumberPrefix:  getEnv("INVOICE_NUMBER_PREFIX", "MIE"),
+		},
+		Intake: IntakeConfig{
+			MaxOpenFreeRequests:    getIntEnv("INTAKE_MAX_OPEN_FREE_REQUESTS", 2),
+			MaxRequestsPerSemester: getIntEnv("INTAKE_MAX_REQUESTS_PER_SEMESTER", 6),
+			DuplicateThreshold:     getIntEnv("INTAKE_DUPLICATE_THRESHOLD", 80),
+			DuplicateLookback:      getDurationEnv("INTAKE_DUPLICATE_LOOKBACK", 90*24*time.Hour),
+			BlockDuplicates:        getBoolEnv("INTAKE_BLOCK_DUPLICATES", false),
+		},
+		Delivery: DeliveryConfig{
+			IncludedRevisions: getIntEnv("DELIVERY_INCLUDED_REVISIONS", 2),
+			RequireAcceptance: getBoolEnv("DELIVERY_REQUIRE_ACCEPTANCE", true),
+		},
+		Automation: AutomationConfig{
+			Interval: getDurationEnv("AUTOMATION_INTERVAL", time.Hour),
+		},
+		Domain: DomainConfig{
+			DNSServer:     getEnv("DOMAIN_DNS_SERVER", ""),
+			LookupTimeout: getDurationEnv("DOMAIN_LOOKUP_TIMEOUT", 5*time.Second),
+		},
+		ACME: ACMEConfig{
+			DirectoryURL:  getEnv("ACME_DIRECTORY_URL", ""),
+			Email:         getEnv("ACME_EMAIL", ""),
+			CABundle:      getEnv("ACME_CA_BUNDLE", ""),
+			RenewInterval: getDurationEnv("ACME_RENEW_INTERVAL", time.Hour),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		},
+		Queue: QueueConfig{
+			OnePerStudent:  getBoolEnv("QUEUE_ONE_PER_STUDENT", true),
+			FinalYearBoost: getDurationEnv("QUEUE_FINAL_YEAR_BOOST", 14*24*time.Hour),
+		},
+	}
+}
+
+// Validate refuses an invoice prefix too long for GST document numbers, and
+// settings that are unsafe in production. The payment webhook is
+// unauthenticated apart from its signature, so a fake provider or a known
+// secret would let anyone mark requests paid.
+func (c *Config) Validate() error {
+	if len(c.Invoice.NumberPrefix) > MaxInvoicePrefixLen {
+		return fmt.Errorf("INVOICE_NUMBER_PREFIX must be at most %d characters, as GST document numbers are limited to 16", MaxInvoicePrefixLen)
+	}
+	if c.Server.Env != "production" {
+		return nil
 	}
+	switch {
+	case c.Payment.Provider == "fake":
+		return errors.New("PAYMENT_PROVIDER=fake cannot be used in production")
+	case c.Payment.WebhookSecret == "" || c.Payment.WebhookSecret == devWebhookSecret:
+		return errors.New("PAYMENT_WEBHOOK_SECRET must be set to the provider's webhook secret in production")
+	}
+	return nil
 }
 
 // DSN returns the PostgreSQL connection string.
@@ -168,6 +324,15 @@ func getIntEnv(key string, fallback int) int {
 	return fallback
 }
 
+func getBoolEnv(key string, fallback bool) bool {
+	if val, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
 func getDurationEnv(key string, fallback time.Duration) time.Duration {
 	if val, ok := os.LookupEnv(key); ok {
 		if d, err := time.ParseDuration(val); err == nil {
@@ -0,0 +1,51 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// NewResolver returns the TXT resolver used to verify custom domains. When
+// DOMAIN_DNS_SERVER is set every lookup goes to that server, which lets tests
+// point at a local DNS server and production sidestep stale resolver caches;
+// otherwise the system resolver is used.
+func NewResolver(cfg *config.Config) domain.TXTResolver {
+	r := &netResolver{resolver: net.DefaultResolver, timeout: cfg.Domain.LookupTimeout}
+	if server := cfg.Domain.DNSServer; server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		r.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+	return r
+}
+
+type netResolver struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+func (r *netResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	records, err := r.resolver.LookupTXT(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return []string{}, nil
+	}
+	return records, err
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewOutcome is the student's verdict on a delivery
+type ReviewOutcome string
+
+const (
+	ReviewAccepted          ReviewOutcome = "accepted"
+	ReviewRevisionRequested ReviewOutcome = "revision_requested"
+)
+
+// DeliveryReview records the student's sign-off or revision request on a
+// request in review. Round counts deliveries: 1 is the first, each revision
+// adds one.
+type DeliveryReview struct {
+	ID         uuid.UUID     `json:"id"`
+	RequestID  uuid.UUID     `json:"request_id"`
+	Round      int           `json:"round"`
+	Outcome    ReviewOutcome `json:"outcome"`
+	Notes      string        `json:"notes,omitempty"`
+	ReviewedBy uuid.UUID     `json:"reviewed_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// Rating is the student's feedback on an accepted request and its builder
+type Rating struct {
+	ID           uuid.UUID  `json:"id"`
+	RequestID    uuid.UUID  `json:"request_id"`
+	UserID       uuid.UUID  `json:"user_id"`
+	BuilderID    *uuid.UUID `json:"builder_id,omitempty"`
+	BuilderScore int        `json:"builder_score"` // 1–5
+	RequestScore int        `json:"request_score"` // 1–5, the result itself
+	Feedback     string     `json:"feedback,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// AcceptanceStatus is where a request stands on sign-off
+type AcceptanceStatus struct {
+	RequestID         uuid.UUID        `json:"request_id"`
+	Status            RequestStatus    `json:"status"`
+	Accepted          bool             `json:"accepted"`
+	AcceptedAt        *time.Time       `json:"accepted_at,omitempty"`
+	IncludedRevisions int              `json:"included_revisions"`
+	RevisionsUsed     int              `json:"revisions_used"`
+	RevisionsLeft     int              `json:"revisions_left"`
+	Reviews           []DeliveryReview `json:"reviews"`
+	Rating            *Rating          `json:"rating,omitempty"`
+}
+
+// Summarize fills in acceptance and revision counts from the reviews, which
+// must be in round order
+func (a *AcceptanceStatus) Summarize() {
+	a.Accepted, a.AcceptedAt, a.RevisionsUsed = false, nil, 0
+	for i := range a.Reviews {
+		switch a.Reviews[i].Outcome {
+		case ReviewRevisionRequested:
+			a.RevisionsUsed++
+			a.Accepted, a.AcceptedAt = false, nil
+		case ReviewAccepted:
+			a.Accepted, a.AcceptedAt = true, &a.Reviews[i].CreatedAt
+		}
+	}
+	a.RevisionsLeft = a.IncludedRevisions - a.RevisionsUsed
+	if a.RevisionsLeft < 0 {
+		a.RevisionsLeft = 0
+	}
+}
+
+// AcceptDeliveryRequest is the student's sign-off
+type AcceptDeliveryRequest struct {
+	Notes string `json:"notes" binding:"max=2000"`
+}
+
+// RequestRevisionRequest asks the builder for another round of changes
+type RequestRevisionRequest struct {
+	Notes string `json:"notes" binding:"required,max=2000"`
+}
+
+// RateRequest is the student's rating of an accepted request
+type RateRequest struct {
+	BuilderScore int    `json:"builder_score" binding:"required,min=1,max=5"`
+	RequestScore int    `json:"request_score" binding:"required,min=1,max=5"`
+	Feedback     string `json:"feedback" binding:"max=2000"`
+}
+
+// BuilderQuality rolls up delivery reviews and ratings for one builder
+type BuilderQuality struct {
+	BuilderID           uuid.UUID `json:"builder_id"`
+	Name                string    `json:"name"`
+	Completed           int       `json:"completed"`
+	Rated               int       `json:"rated"`
+	AvgBuilderScore     float64   `json:"avg_builder_score"`
+	AvgRequestScore     float64   `json:"avg_request_score"`
+	AvgRevisions        float64   `json:"avg_revisions"`         // per accepted request
+	FirstTimeAcceptance float64   `json:"first_time_acceptance"` // share of accepted requests needing no revision
+}
+
+// AcceptanceRepository defines the interface for review and rating data access
+type AcceptanceRepository interface {
+	AddReview(ctx context.Context, r *DeliveryReview) error
+	ListReviews(ctx context.Context, requestID uuid.UUID) ([]DeliveryReview, error)
+	UpsertRating(ctx context.Context, r *Rating) error
+	FindRating(ctx context.Context, requestID uuid.UUID) (*Rating, error)
+	BuilderQuality(ctx context.Context) ([]BuilderQuality, error)
+}
+
+// AcceptanceService defines the interface for delivery sign-off, revisions
+// and ratings. As a guard it keeps requests from completing without sign-off.
+type AcceptanceService interface {
+	TransitionGuard
+	Status(ctx context.Context, requestID uuid.UUID) (*AcceptanceStatus, error)
+	Accept(ctx context.Context, userID, requestID uuid.UUID, req *AcceptDeliveryRequest) (*AcceptanceStatus, error)
+	RequestRevision(ctx context.Context, userID, requestID uuid.UUID, req *RequestRevisionRequest) (*AcceptanceStatus, error)
+	Rate(ctx context.Context, userID, requestID uuid.UUID, req *RateRequest) (*Rating, error)
+	BuilderQuality(ctx context.Context) ([]BuilderQuality, error)
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxAutomationMatches caps how many requests one rule acts on per run; the
+// rest are picked up by the next run
+const MaxAutomationMatches = 200
+
+// AutomationAction is what a rule does to each matching request
+type AutomationAction string
+
+const (
+	AutomationTransition AutomationAction = "transition" // move to TargetStatus
+	AutomationNotify     AutomationAction = "notify"     // send Message to Recipient
+	AutomationAddLabel   AutomationAction = "add_label"  // tag with Label
+)
+
+// NotifyRecipient is who a notify rule writes to
+type NotifyRecipient string
+
+const (
+	NotifyStudent NotifyRecipient = "student"
+	NotifyBuilder NotifyRecipient = "builder"
+	NotifyAdmins  NotifyRecipient = "admins"
+)
+
+// AutomationConditions narrow a rule beyond status and idle time. Unset
+// conditions match every request.
+type AutomationConditions struct {
+	RequestType *RequestType     `json:"request_type,omitempty"`
+	HostingType *HostingType     `json:"hosting_type,omitempty"`
+	Complexity  *ComplexityLevel `json:"complexity,omitempty"`
+	IsFree      *bool            `json:"is_free,omitempty"`
+	Label       string           `json:"label,omitempty"` // request must carry the label
+}
+
+// AutomationRule is "when status = Status for Days days [and Conditions],
+// then Action". A rule acts on a request at most once per stay in a status.
+type AutomationRule struct {
+	ID           uuid.UUID            `json:"id"`
+	Name         string               `json:"name"`
+	Description  string               `json:"description,omitempty"`
+	Enabled      bool                 `json:"enabled"`
+	Status       RequestStatus        `json:"status"`
+	Days         int                  `json:"days"`
+	Conditions   AutomationConditions `json:"conditions"`
+	Action       AutomationAction     `json:"action"`
+	TargetStatus *RequestStatus       `json:"target_status,omitempty"`
+	Label        string               `json:"label,omitempty"`
+	Recipient    NotifyRecipient      `json:"recipient,omitempty"`
+	Message      string               `json:"message,omitempty"` // may use {title}, {status} and {days}
+	LastRunAt    *time.Time           `json:"last_run_at,omitempty"`
+	CreatedBy    *uuid.UUID           `json:"created_by,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+// Validate checks that the action has the arguments it needs
+func (r *AutomationRule) Validate() error {
+	switch r.Action {
+	case AutomationTransition:
+		if r.TargetStatus == nil {
+			return fmt.Errorf("%w: transition rules need a target_status", ErrInvalidAutomationRule)
+		}
+		if *r.TargetStatus == r.Status {
+			return fmt.Errorf("%w: target_status must differ from status", ErrInvalidAutomationRule)
+		}
+		if *r.TargetStatus == StatusScheduled {
+			return fmt.Errorf("%w: requests are scheduled by booking a slot, not by a rule", ErrInvalidAutomationRule)
+		}
+	case AutomationAddLabel:
+		if r.Label == "" {
+			return fmt.Errorf("%w: add_label rules need a label", ErrInvalidAutomationRule)
+		}
+	case AutomationNotify:
+		if r.Recipient == "" || strings.TrimSpace(r.Message) == "" {
+			return fmt.Errorf("%w: notify rules need a recipient and a message", ErrInvalidAutomationRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidAutomationRule, r.Action)
+	}
+	return nil
+}
+
+// RequestFilter selects the requests the rule applies to at time now
+func (r *AutomationRule) RequestFilter(now time.Time) RequestFilter {
+	status := r.Status
+	before := now.AddDate(0, 0, -r.Days)
+	return RequestFilter{
+		Status:              &status,
+		RequestType:         r.Conditions.RequestType,
+		HostingType:         r.Conditions.HostingType,
+		Complexity:          r.Conditions.Complexity,
+		IsFree:              r.Conditions.IsFree,
+		Label:               NormalizeLabel(r.Conditions.Label),
+		StatusChangedBefore: &before,
+	}
+}
+
+// RenderMessage fills the message placeholders for one match
+func (r *AutomationRule) RenderMessage(m *AutomationMatch) string {
+	return strings.NewReplacer(
+		"{title}", m.Title,
+		"{status}", string(m.Status),
+		"{days}", strconv.Itoa(m.IdleDays),
+	).Replace(r.Message)
+}
+
+// SaveAutomationRuleRequest creates a rule or replaces one (admin)
+type SaveAutomationRuleRequest struct {
+	Name         string               `json:"name" binding:"required,max=120"`
+	Description  string               `json:"description"`
+	Enabled      *bool                `json:"enabled"` // new rules default to disabled
+	Status       RequestStatus        `json:"status" binding:"required,oneof=pending queued scheduled building review deploying completed cancelled rejected"`
+	Days         int                  `json:"days" binding:"required,min=1,max=365"`
+	Conditions   AutomationConditions `json:"conditions"`
+	Action       AutomationAction     `json:"action" binding:"required,oneof=transition notify add_label"`
+	TargetStatus *RequestStatus       `json:"target_status" binding:"omitempty,oneof=pending queued scheduled building review deploying completed cancelled rejected"`
+	Label        string               `json:"label" binding:"max=40"`
+	Recipient    NotifyRecipient      `json:"recipient" binding:"omitempty,oneof=student builder admins"`
+	Message      string               `json:"message" binding:"max=1000"`
+}
+
+// AutomationMatch is a request a rule applies to
+type AutomationMatch struct {
+	RequestID       uuid.UUID     `json:"request_id"`
+	Title           string        `json:"title"`
+	Status          RequestStatus `json:"status"`
+	UserID          uuid.UUID     `json:"user_id"`
+	BuilderID       *uuid.UUID    `json:"builder_id,omitempty"`
+	StatusChangedAt time.Time     `json:"status_changed_at"`
+	IdleDays        int           `json:"idle_days"`
+}
+
+// AutomationHit records a rule acting on a request
+type AutomationHit struct {
+	ID              uuid.UUID `json:"id"`
+	RuleID          uuid.UUID `json:"rule_id"`
+	RequestID       uuid.UUID `json:"request_id"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+	OK              bool      `json:"ok"`
+	Error           string    `json:"error,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AutomationRunItem is the outcome for one request. OK and Error are unset
+// in a dry run.
+type AutomationRunItem struct {
+	AutomationMatch
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// AutomationRun reports one rule run, or its preview when DryRun is set
+type AutomationRun struct {
+	RuleID    uuid.UUID           `json:"rule_id"`
+	Name      string              `json:"name"`
+	Action    AutomationAction    `json:"action"`
+	DryRun    bool                `json:"dry_run"`
+	Matched   int                 `json:"matched"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Items     []AutomationRunItem `json:"items"`
+}
+
+// AutomationRepository defines the interface for automation data access
+type AutomationRepository interface {
+	CreateRule(ctx context.Context, rule *AutomationRule) error
+	FindRule(ctx context.Context, id uuid.UUID) (*AutomationRule, error)
+	ListRules(ctx context.Context, enabledOnly bool) ([]AutomationRule, error)
+	UpdateRule(ctx context.Context, rule *AutomationRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	MarkRun(ctx context.Context, id uuid.UUID, at time.Time) error
+	// FindMatches returns up to limit requests the rule applies to at time
+	// now, oldest first, leaving out those it has already acted on
+	FindMatches(ctx context.Context, rule *AutomationRule, now time.Time, limit int) ([]AutomationMatch, error)
+	// ClaimHit records that the rule is acting on a request. It returns false
+	// when the hit already exists, e.g. another instance claimed it first.
+	ClaimHit(ctx context.Context, hit *AutomationHit) (bool, error)
+	FinishHit(ctx context.Context, hit *AutomationHit) error
+	ListHits(ctx context.Context, ruleID uuid.UUID, limit int) ([]AutomationHit, error)
+}
+
+// AutomationService defines the interface for automation rules
+type AutomationService interface {
+	CreateRule(ctx context.Context, createdBy uuid.UUID, req *SaveAutomationRuleRequest) (*AutomationRule, error)
+	GetRule(ctx context.Context, id uuid.UUID) (*AutomationRule, error)
+	ListRules(ctx context.Context) ([]AutomationRule, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, req *SaveAutomationRuleRequest) (*AutomationRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	ListHits(ctx context.Context, id uuid.UUID) ([]AutomationHit, error)
+	// Preview lists what the rule would act on now, changing nothing
+	Preview(ctx context.Context, id uuid.UUID) (*AutomationRun, error)
+	Run(ctx context.Context, id uuid.UUID) (*AutomationRun, error)
+	// RunAll runs every enabled rule; the background job calls it
+	RunAll(ctx context.Context) ([]AutomationRun, error)
+}
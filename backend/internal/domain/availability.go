@@ -0,0 +1,273 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Slot capacity when no builder has declared availability for the day
+const (
+	DefaultSlotHours    = 8
+	DefaultSlotProjects = 5
+)
+
+// AvailabilityPattern is a builder's weekly availability: every Saturday or
+// Sunday from ValidFrom, and until ValidUntil when set. From and Until record
+// a partial day.
+type AvailabilityPattern struct {
+	ID         uuid.UUID  `json:"id"`
+	BuilderID  uuid.UUID  `json:"builder_id"`
+	DayOfWeek  string     `json:"day_of_week"` // "saturday" or "sunday"
+	Hours      int        `json:"hours"`
+	From       string     `json:"from,omitempty"` // "HH:MM"
+	Until      string     `json:"until,omitempty"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Covers reports whether the pattern applies on the date
+func (p *AvailabilityPattern) Covers(date time.Time) bool {
+	day := dateOnly(date)
+	if p.DayOfWeek != dayName(day) || day.Before(dateOnly(p.ValidFrom)) {
+		return false
+	}
+	return p.ValidUntil == nil || !day.After(dateOnly(*p.ValidUntil))
+}
+
+// AvailabilityDay is a builder's availability on one date. It overrides their
+// weekly patterns, so zero hours marks them away on a day they usually work.
+type AvailabilityDay struct {
+	BuilderID uuid.UUID `json:"builder_id"`
+	Date      time.Time `json:"date"`
+	Hours     int       `json:"hours"`
+	From      string    `json:"from,omitempty"`
+	Until     string    `json:"until,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AvailabilityHours is a day's availability as either a number of hours or a
+// partial day from one time to another
+type AvailabilityHours struct {
+	Hours *int   `json:"hours" binding:"omitempty,min=0,max=24"`
+	From  string `json:"from"`  // "HH:MM"
+	Until string `json:"until"` // "HH:MM"
+}
+
+// resolve returns the hours, working them out from From and Until for a
+// partial day
+func (a *AvailabilityHours) resolve() (int, error) {
+	partial := a.From != "" || a.Until != ""
+	switch {
+	case a.Hours != nil && partial:
+		return 0, fmt.Errorf("%w: give either hours or from and until, not both", ErrInvalidAvailability)
+	case a.Hours != nil:
+		return *a.Hours, nil
+	case !partial:
+		return 0, fmt.Errorf("%w: give hours, or from and until", ErrInvalidAvailability)
+	}
+
+	from, err := time.Parse("15:04", a.From)
+	if err != nil {
+		return 0, fmt.Errorf("%w: from must be in HH:MM format", ErrInvalidAvailability)
+	}
+	until, err := time.Parse("15:04", a.Until)
+	if err != nil {
+		return 0, fmt.Errorf("%w: until must be in HH:MM format", ErrInvalidAvailability)
+	}
+	hours := int(until.Sub(from) / time.Hour)
+	if hours < 1 {
+		return 0, fmt.Errorf("%w: until must be at least an hour after from", ErrInvalidAvailability)
+	}
+	return hours, nil
+}
+
+// CreateAvailabilityPatternRequest declares weekly availability
+type CreateAvailabilityPatternRequest struct {
+	DayOfWeek string `json:"day_of_week" binding:"required,oneof=saturday sunday"`
+	AvailabilityHours
+	ValidFrom  string `json:"valid_from"`  // YYYY-MM-DD, defaults to today
+	ValidUntil string `json:"valid_until"` // YYYY-MM-DD, open-ended when empty
+}
+
+// Pattern builds the pattern the request declares
+func (r *CreateAvailabilityPatternRequest) Pattern(builderID uuid.UUID, today time.Time) (*AvailabilityPattern, error) {
+	hours, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if hours == 0 {
+		return nil, fmt.Errorf("%w: a weekly pattern needs at least an hour; mark single days away instead", ErrInvalidAvailability)
+	}
+
+	p := &AvailabilityPattern{
+		ID:        uuid.New(),
+		BuilderID: builderID,
+		DayOfWeek: r.DayOfWeek,
+		Hours:     hours,
+		From:      r.From,
+		Until:     r.Until,
+		ValidFrom: dateOnly(today),
+		CreatedAt: time.Now(),
+	}
+	if r.ValidFrom != "" {
+		if p.ValidFrom, err = time.Parse("2006-01-02", r.ValidFrom); err != nil {
+			return nil, fmt.Errorf("%w: valid_from must be in YYYY-MM-DD format", ErrInvalidAvailability)
+		}
+	}
+	if r.ValidUntil != "" {
+		until, err := time.Parse("2006-01-02", r.ValidUntil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: valid_until must be in YYYY-MM-DD format", ErrInvalidAvailability)
+		}
+		if until.Before(p.ValidFrom) {
+			return nil, fmt.Errorf("%w: valid_until is before valid_from", ErrInvalidAvailability)
+		}
+		p.ValidUntil = &until
+	}
+	return p, nil
+}
+
+// SetAvailabilityDayRequest declares availability for one date; hours of 0
+// marks the builder away
+type SetAvailabilityDayRequest struct {
+	AvailabilityHours
+	Note string `json:"note" binding:"max=500"`
+}
+
+// Day builds the day entry the request declares
+func (r *SetAvailabilityDayRequest) Day(builderID uuid.UUID, date time.Time) (*AvailabilityDay, error) {
+	if name := dayName(date); name != "saturday" && name != "sunday" {
+		return nil, fmt.Errorf("%w: builds only happen at weekends", ErrInvalidAvailability)
+	}
+	hours, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return &AvailabilityDay{
+		BuilderID: builderID,
+		Date:      dateOnly(date),
+		Hours:     hours,
+		From:      r.From,
+		Until:     r.Until,
+		Note:      strings.TrimSpace(r.Note),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// BuilderHours maps builders to a number of hours
+type BuilderHours map[uuid.UUID]int
+
+// Total adds up every builder's hours
+func (b BuilderHours) Total() int {
+	total := 0
+	for _, h := range b {
+		total += max(h, 0)
+	}
+	return total
+}
+
+// ResolveAvailability works out each builder's hours on the date. A day entry
+// overrides the builder's patterns; of several patterns covering the date,
+// the one that started latest wins. Builders who declared nothing for the
+// date are left out, so an empty result means nobody has.
+func ResolveAvailability(date time.Time, patterns []AvailabilityPattern, days []AvailabilityDay) BuilderHours {
+	hours := BuilderHours{}
+	started := map[uuid.UUID]time.Time{}
+	for _, p := range patterns {
+		if !p.Covers(date) {
+			continue
+		}
+		if from, ok := started[p.BuilderID]; ok && p.ValidFrom.Before(from) {
+			continue
+		}
+		started[p.BuilderID] = p.ValidFrom
+		hours[p.BuilderID] = p.Hours
+	}
+	for _, d := range days {
+		if dateOnly(d.Date).Equal(dateOnly(date)) {
+			hours[d.BuilderID] = d.Hours
+		}
+	}
+	return hours
+}
+
+// SlotHours is the capacity of a slot on a day the builders have the given
+// hours. Days nobody has declared availability for keep DefaultSlotHours.
+func SlotHours(available BuilderHours) int {
+	if len(available) == 0 {
+		return DefaultSlotHours
+	}
+	return available.Total()
+}
+
+// AvailabilityDate is a builder's resolved availability on one weekend day
+type AvailabilityDate struct {
+	Date      time.Time `json:"date"`
+	DayOfWeek string    `json:"day_of_week"`
+	Hours     int       `json:"hours"`
+	Declared  bool      `json:"declared"` // false when no pattern or day entry covers it
+}
+
+// AvailabilityCalendar is a builder's availability over a range of dates
+type AvailabilityCalendar struct {
+	Patterns []AvailabilityPattern `json:"patterns"`
+	Days     []AvailabilityDay     `json:"days"`
+	Dates    []AvailabilityDate    `json:"dates"`
+}
+
+// NewAvailabilityCalendar resolves a builder's patterns and day entries for
+// every weekend day from one date to another
+func NewAvailabilityCalendar(builderID uuid.UUID, from, to time.Time, patterns []AvailabilityPattern, days []AvailabilityDay) *AvailabilityCalendar {
+	cal := &AvailabilityCalendar{Patterns: patterns, Days: days, Dates: []AvailabilityDate{}}
+	for day := dateOnly(from); !day.After(dateOnly(to)); day = day.AddDate(0, 0, 1) {
+		name := dayName(day)
+		if name != "saturday" && name != "sunday" {
+			continue
+		}
+		hours, declared := ResolveAvailability(day, patterns, days)[builderID]
+		cal.Dates = append(cal.Dates, AvailabilityDate{Date: day, DayOfWeek: name, Hours: hours, Declared: declared})
+	}
+	return cal
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func dayName(t time.Time) string {
+	return strings.ToLower(t.Weekday().String())
+}
+
+// AvailabilityRepository defines the interface for builder availability data
+// access
+type AvailabilityRepository interface {
+	CreatePattern(ctx context.Context, p *AvailabilityPattern) error
+	FindPatternByID(ctx context.Context, id uuid.UUID) (*AvailabilityPattern, error)
+	DeletePattern(ctx context.Context, id uuid.UUID) error
+	// ListPatterns returns the builder's patterns, oldest first
+	ListPatterns(ctx context.Context, builderID uuid.UUID) ([]AvailabilityPattern, error)
+	// ListPatternsOn returns every builder's patterns covering the date
+	ListPatternsOn(ctx context.Context, date time.Time) ([]AvailabilityPattern, error)
+
+	UpsertDay(ctx context.Context, d *AvailabilityDay) error
+	DeleteDay(ctx context.Context, builderID uuid.UUID, date time.Time) error
+	// ListDays returns day entries from one date to another, inclusive; for
+	// every builder when builderID is nil
+	ListDays(ctx context.Context, builderID *uuid.UUID, from, to time.Time) ([]AvailabilityDay, error)
+}
+
+// AvailabilityService lets builders declare when they can build. Every
+// change updates the capacity of the slots it touches.
+type AvailabilityService interface {
+	Calendar(ctx context.Context, builderID uuid.UUID, from, to time.Time) (*AvailabilityCalendar, error)
+	CreatePattern(ctx context.Context, builderID uuid.UUID, req *CreateAvailabilityPatternRequest) (*AvailabilityPattern, error)
+	DeletePattern(ctx context.Context, builderID, id uuid.UUID) error
+	SetDay(ctx context.Context, builderID uuid.UUID, date time.Time, req *SetAvailabilityDayRequest) (*AvailabilityDay, error)
+	ClearDay(ctx context.Context, builderID uuid.UUID, date time.Time) error
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkItems caps how many requests one bulk operation may touch
+const MaxBulkItems = 500
+
+// BulkOperation is the change applied to every selected request
+type BulkOperation string
+
+const (
+	BulkSetStatus     BulkOperation = "set_status"
+	BulkAssignBuilder BulkOperation = "assign_builder"
+	BulkSetComplexity BulkOperation = "set_complexity"
+	BulkAddLabel      BulkOperation = "add_label"
+	BulkCancel        BulkOperation = "cancel"
+)
+
+// BulkMode decides what happens when some items fail validation
+type BulkMode string
+
+const (
+	BulkAtomic     BulkMode = "atomic"      // apply everything or nothing
+	BulkBestEffort BulkMode = "best_effort" // apply what can be applied
+)
+
+// BulkFilter selects requests by the same criteria as the admin listing.
+// Dates are YYYY-MM-DD and inclusive at both ends.
+type BulkFilter struct {
+	Query       string           `json:"q"`
+	Statuses    []RequestStatus  `json:"statuses"`
+	RequestType *RequestType     `json:"request_type"`
+	HostingType *HostingType     `json:"hosting_type"`
+	Complexity  *ComplexityLevel `json:"complexity"`
+	BuilderID   *uuid.UUID       `json:"builder_id"`
+	IsFree      *bool            `json:"is_free"`
+	Label       string           `json:"label"`
+	CreatedFrom string           `json:"created_from"`
+	CreatedTo   string           `json:"created_to"`
+}
+
+// RequestFilter converts the bulk filter to a listing filter, oldest first
+func (f *BulkFilter) RequestFilter() (RequestFilter, error) {
+	filter := RequestFilter{
+		Query:       strings.TrimSpace(f.Query),
+		Statuses:    f.Statuses,
+		RequestType: f.RequestType,
+		HostingType: f.HostingType,
+		Complexity:  f.Complexity,
+		BuilderID:   f.BuilderID,
+		IsFree:      f.IsFree,
+		Label:       NormalizeLabel(f.Label),
+		SortBy:      SortByCreatedAt,
+	}
+	if f.CreatedFrom != "" {
+		from, err := time.Parse("2006-01-02", f.CreatedFrom)
+		if err != nil {
+			return filter, fmt.Errorf("%w: created_from must be in YYYY-MM-DD format", ErrInvalidBulkOperation)
+		}
+		filter.CreatedFrom = &from
+	}
+	if f.CreatedTo != "" {
+		to, err := time.Parse("2006-01-02", f.CreatedTo)
+		if err != nil {
+			return filter, fmt.Errorf("%w: created_to must be in YYYY-MM-DD format", ErrInvalidBulkOperation)
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+	return filter, nil
+}
+
+// BulkUpdateRequest applies one operation to a list of requests, or to every
+// request matching a filter (admin). Exactly one of IDs and Filter is set.
+type BulkUpdateRequest struct {
+	IDs       []uuid.UUID   `json:"ids" binding:"omitempty,max=500"`
+	Filter    *BulkFilter   `json:"filter"`
+	Operation BulkOperation `json:"operation" binding:"required,oneof=set_status assign_builder set_complexity add_label cancel"`
+	Mode      BulkMode      `json:"mode" binding:"omitempty,oneof=atomic best_effort"` // defaults to atomic
+
+	// Operation arguments
+	Status     *RequestStatus   `json:"status" binding:"omitempty,oneof=pending queued scheduled building review deploying completed cancelled rejected"`
+	BuilderID  *uuid.UUID       `json:"builder_id"`
+	Complexity *ComplexityLevel `json:"complexity" binding:"omitempty,oneof=basic standard advanced"`
+	Label      string           `json:"label" binding:"max=40"`
+}
+
+// BulkItemResult is the outcome for one request
+type BulkItemResult struct {
+	RequestID uuid.UUID     `json:"request_id"`
+	OK        bool          `json:"ok"`
+	Status    RequestStatus `json:"status,omitempty"` // after the operation, when it succeeded
+	Error     string        `json:"error,omitempty"`
+}
+
+// BulkResult reports a bulk operation. Applied is false when an atomic run
+// was rejected, in which case no request was changed.
+type BulkResult struct {
+	Operation BulkOperation    `json:"operation"`
+	Mode      BulkMode         `json:"mode"`
+	Matched   int              `json:"matched"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Applied   bool             `json:"applied"`
+	Items     []BulkItemResult `json:"items"`
+}
+
+// NormalizeLabel trims and lower-cases a label so "Hackathon " and
+// "hackathon" are the same tag
+func NormalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
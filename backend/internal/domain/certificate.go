@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CertificateStatus tracks a whitelabel request's TLS certificate
+type CertificateStatus string
+
+const (
+	CertificatePending CertificateStatus = "pending" // waiting for the ACME server
+	CertificateIssued  CertificateStatus = "issued"
+	CertificateFailed  CertificateStatus = "failed" // never issued; retried with backoff
+)
+
+// Certificate is the TLS certificate of a whitelabel request's domain. A
+// failed renewal keeps the previous certificate, with the error in LastError.
+type Certificate struct {
+	ID         uuid.UUID         `json:"id"`
+	RequestID  uuid.UUID         `json:"request_id"`
+	Domain     string            `json:"domain"`
+	Status     CertificateStatus `json:"status"`
+	Issuer     string            `json:"issuer,omitempty"`
+	NotBefore  *time.Time        `json:"not_before,omitempty"`
+	NotAfter   *time.Time        `json:"not_after,omitempty"`
+	RenewAfter time.Time         `json:"renew_after"` // next issuance or retry
+	Attempts   int               `json:"attempts"`    // failures since the last success
+	LastError  string            `json:"last_error,omitempty"`
+	CertPEM    string            `json:"-"` // leaf first, then intermediates
+	KeyPEM     string            `json:"-"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// IssuedCertificate is what a CertificateIssuer hands back
+type IssuedCertificate struct {
+	CertPEM   string
+	KeyPEM    string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// RenewAt returns when a certificate should be renewed: once two thirds of
+// its lifetime has passed, i.e. 30 days before expiry for 90-day certificates
+func (c *IssuedCertificate) RenewAt() time.Time {
+	return c.NotAfter.Add(-c.NotAfter.Sub(c.NotBefore) / 3)
+}
+
+// CertificateIssuer obtains certificates from a CA. The ACME implementation
+// lives in internal/acme.
+type CertificateIssuer interface {
+	Issue(ctx context.Context, domain string) (*IssuedCertificate, error)
+}
+
+// ACMEStore keeps the ACME account key and the HTTP-01 challenges being
+// answered, shared by every server instance
+type ACMEStore interface {
+	// AccountKey returns the PEM account key for a directory, "" if none yet
+	AccountKey(ctx context.Context, directoryURL string) (string, error)
+	SaveAccountKey(ctx context.Context, directoryURL, keyPEM string) error
+	PutChallenge(ctx context.Context, token, keyAuth, domain string) error
+	// Challenge returns the key authorization for a token, "" if unknown
+	Challenge(ctx context.Context, token string) (string, error)
+	DeleteChallenge(ctx context.Context, token string) error
+}
+
+// CertificateRepository defines the interface for certificate data access
+type CertificateRepository interface {
+	ACMEStore
+	FindByRequest(ctx context.Context, requestID uuid.UUID) (*Certificate, error)
+	// Upsert creates the request's certificate record or resets it for a
+	// new domain
+	Upsert(ctx context.Context, cert *Certificate) error
+	Update(ctx context.Context, cert *Certificate) error
+	// ClaimDue returns up to limit certificates whose RenewAfter has passed,
+	// pushing it to leaseUntil so another instance does not pick them up too
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Certificate, error)
+}
+
+// CertificateService defines the interface for whitelabel TLS certificates
+type CertificateService interface {
+	// Provision requests a certificate for the request's verified domain.
+	// Issuance runs in the background; the returned record is pending.
+	Provision(ctx context.Context, requestID uuid.UUID) (*Certificate, error)
+	Get(ctx context.Context, requestID uuid.UUID) (*Certificate, error)
+	// ChallengeResponse answers an HTTP-01 challenge
+	ChallengeResponse(ctx context.Context, token string) (string, error)
+	// RenewDue issues or renews every certificate that is due; the
+	// background job calls it
+	RenewDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliverableKind is what a deliverable ships as
+type DeliverableKind string
+
+const (
+	DeliverableWeb     DeliverableKind = "web"
+	DeliverableAndroid DeliverableKind = "android"
+	DeliverableIOS     DeliverableKind = "ios"
+	DeliverableAPI     DeliverableKind = "api"
+)
+
+// DeliverableStatus tracks one deliverable through the build
+type DeliverableStatus string
+
+const (
+	DeliverablePlanned   DeliverableStatus = "planned"
+	DeliverableBuilding  DeliverableStatus = "building"
+	DeliverableReview    DeliverableStatus = "review"
+	DeliverableDeploying DeliverableStatus = "deploying"
+	DeliverableDelivered DeliverableStatus = "delivered"
+	DeliverableDropped   DeliverableStatus = "dropped" // descoped; ignored when deriving the request status
+)
+
+// deliverableStage orders the working statuses
+var deliverableStage = map[DeliverableStatus]int{
+	DeliverablePlanned:   0,
+	DeliverableBuilding:  1,
+	DeliverableReview:    2,
+	DeliverableDeploying: 3,
+	DeliverableDelivered: 4,
+}
+
+// ChecklistItem is one step of a deliverable's handover
+type ChecklistItem struct {
+	Item   string     `json:"item"`
+	Done   bool       `json:"done"`
+	DoneAt *time.Time `json:"done_at,omitempty"`
+	DoneBy *uuid.UUID `json:"done_by,omitempty"`
+}
+
+// BuildArtifact points at a build output, e.g. an APK or a web bundle
+type BuildArtifact struct {
+	Name    string    `json:"name"`
+	URL     string    `json:"url"`
+	Version string    `json:"version,omitempty"`
+	SHA256  string    `json:"sha256,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// Deliverable is one separately shipped part of a request, such as the
+// website or the Android app of a RequestTypeBoth request
+type Deliverable struct {
+	ID          uuid.UUID         `json:"id"`
+	RequestID   uuid.UUID         `json:"request_id"`
+	Kind        DeliverableKind   `json:"kind"`
+	Label       string            `json:"label"`
+	Status      DeliverableStatus `json:"status"`
+	DeliveryURL string            `json:"delivery_url,omitempty"`
+	RepoURL     string            `json:"repo_url,omitempty"`
+	StoreURL    string            `json:"store_url,omitempty"` // Play Store / App Store listing
+	Artifacts   []BuildArtifact   `json:"artifacts"`
+	Checklist   []ChecklistItem   `json:"checklist"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeliveredAt *time.Time        `json:"delivered_at,omitempty"`
+}
+
+// ChecklistComplete reports whether every handover step is done
+func (d *Deliverable) ChecklistComplete() bool {
+	for _, item := range d.Checklist {
+		if !item.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultDeliverableKinds is what a request of the given type usually ships
+func DefaultDeliverableKinds(reqType RequestType) []DeliverableKind {
+	switch reqType {
+	case RequestTypeMobileApp:
+		return []DeliverableKind{DeliverableAndroid, DeliverableIOS}
+	case RequestTypeBoth:
+		return []DeliverableKind{DeliverableWeb, DeliverableAndroid, DeliverableIOS}
+	}
+	return []DeliverableKind{DeliverableWeb}
+}
+
+// DefaultDeliverableLabel names a deliverable of the given kind
+func DefaultDeliverableLabel(kind DeliverableKind) string {
+	switch kind {
+	case DeliverableAndroid:
+		return "Android app"
+	case DeliverableIOS:
+		return "iOS app"
+	case DeliverableAPI:
+		return "API"
+	}
+	return "Website"
+}
+
+// DefaultHandoverChecklist lists the handover steps for a deliverable kind
+func DefaultHandoverChecklist(kind DeliverableKind) []ChecklistItem {
+	var items []string
+	switch kind {
+	case DeliverableAndroid:
+		items = []string{
+			"Signed release build uploaded",
+			"Play Store listing submitted",
+			"Signing key handed over",
+			"Source repository transferred",
+		}
+	case DeliverableIOS:
+		items = []string{
+			"Build uploaded to TestFlight",
+			"App Store listing submitted",
+			"Certificates and provisioning profiles handed over",
+			"Source repository transferred",
+		}
+	case DeliverableAPI:
+		items = []string{
+			"API deployed",
+			"API documentation shared",
+			"Environment variables documented",
+			"Source repository transferred",
+		}
+	default:
+		items = []string{
+			"Production URL live",
+			"Domain and DNS documented",
+			"Admin credentials handed over",
+			"Source repository transferred",
+		}
+	}
+	checklist := make([]ChecklistItem, len(items))
+	for i, item := range items {
+		checklist[i] = ChecklistItem{Item: item}
+	}
+	return checklist
+}
+
+// derivedStatuses are the request statuses that follow from its
+// deliverables once work has been scheduled. Completion is not among them:
+// it takes the student's sign-off.
+var derivedStatuses = map[RequestStatus]bool{
+	StatusBuilding:  true,
+	StatusReview:    true,
+	StatusDeploying: true,
+}
+
+// IsDerivedStatus reports whether a request status is derived from the
+// request's deliverables when it has any
+func IsDerivedStatus(s RequestStatus) bool {
+	return derivedStatuses[s]
+}
+
+// DeliverablesStarted reports whether a request is far enough along for
+// work on its deliverables to begin
+func DeliverablesStarted(s RequestStatus) bool {
+	return s == StatusScheduled || s == StatusCompleted || derivedStatuses[s]
+}
+
+// DeriveRequestStatus works out a request's status from its deliverables.
+// The request goes to review once everything is delivered, for the student to
+// accept, and otherwise sits at the earliest stage any deliverable is in;
+// planned deliverables count as building once any other work has started. ok
+// is false when the deliverables say nothing yet: none are active or started,
+// or the request is not scheduled.
+func DeriveRequestStatus(current RequestStatus, deliverables []Deliverable) (status RequestStatus, ok bool) {
+	if !DeliverablesStarted(current) {
+		return current, false
+	}
+
+	earliest, started, active := deliverableStage[DeliverableDelivered], false, 0
+	for _, d := range deliverables {
+		stage, working := deliverableStage[d.Status]
+		if !working {
+			continue // dropped
+		}
+		active++
+		if stage > deliverableStage[DeliverablePlanned] {
+			started = true
+		}
+		if stage < earliest {
+			earliest = stage
+		}
+	}
+	if active == 0 {
+		return current, false
+	}
+
+	switch {
+	case !started:
+		return current, false // nothing begun; the status is still set by hand
+	case earliest == deliverableStage[DeliverableDelivered]:
+		if current == StatusCompleted {
+			return current, true // already signed off
+		}
+		return StatusReview, true
+	case earliest <= deliverableStage[DeliverableBuilding]:
+		return StatusBuilding, true
+	case earliest == deliverableStage[DeliverableReview]:
+		return StatusReview, true
+	}
+	return StatusDeploying, true
+}
+
+// CreateDeliverableRequest adds a deliverable to a request (staff). With no
+// kind, the usual deliverables for the request type are added.
+type CreateDeliverableRequest struct {
+	Kind  DeliverableKind `json:"kind" binding:"omitempty,oneof=web android ios api"`
+	Label string          `json:"label"`
+}
+
+// ChecklistUpdate ticks or unticks a handover step; unknown steps are added
+type ChecklistUpdate struct {
+	Item string `json:"item" binding:"required"`
+	Done bool   `json:"done"`
+}
+
+// UpdateDeliverableRequest changes a deliverable (staff)
+type UpdateDeliverableRequest struct {
+	Label       *string            `json:"label"`
+	Status      *DeliverableStatus `json:"status" binding:"omitempty,oneof=planned building review deploying delivered dropped"`
+	DeliveryURL *string            `json:"delivery_url"`
+	RepoURL     *string            `json:"repo_url"`
+	StoreURL    *string            `json:"store_url"`
+	Checklist   []ChecklistUpdate  `json:"checklist" binding:"omitempty,dive"`
+}
+
+// AddBuildArtifactRequest records a build output against a deliverable (staff)
+type AddBuildArtifactRequest struct {
+	Name    string `json:"name" binding:"required"`
+	URL     string `json:"url" binding:"required,url"`
+	Version string `json:"version"`
+	SHA256  string `json:"sha256" binding:"omitempty,len=64,hexadecimal"`
+}
+
+// DeliverableRepository defines the interface for deliverable data access
+type DeliverableRepository interface {
+	Create(ctx context.Context, d *Deliverable) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Deliverable, error)
+	ListByRequest(ctx context.Context, requestID uuid.UUID) ([]Deliverable, error)
+	Update(ctx context.Context, d *Deliverable) error
+}
+
+// DeliverableService defines the interface for deliverable business logic.
+// It guards request transitions so derived statuses cannot be set by hand.
+type DeliverableService interface {
+	TransitionGuard
+	Create(ctx context.Context, requestID uuid.UUID, req *CreateDeliverableRequest) ([]Deliverable, error)
+	ListForRequest(ctx context.Context, requestID uuid.UUID) ([]Deliverable, error)
+	Update(ctx context.Context, actorID, requestID, deliverableID uuid.UUID, req *UpdateDeliverableRequest) (*Deliverable, error)
+	AddArtifact(ctx context.Context, requestID, deliverableID uuid.UUID, req *AddBuildArtifactRequest) (*Deliverable, error)
+	// Reopen puts delivered deliverables back into building for a revision
+	// round, moving the request with them. It reports false, doing nothing,
+	// when the request has no active deliverables.
+	Reopen(ctx context.Context, req *BuildRequest) (bool, error)
+}
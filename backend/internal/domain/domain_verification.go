@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainStatus tracks whether a whitelabel request's custom domain has been
+// proven to belong to the student
+type DomainStatus string
+
+const (
+	DomainPending  DomainStatus = "pending"  // token issued, not checked yet
+	DomainVerified DomainStatus = "verified" // token found in DNS
+	DomainFailed   DomainStatus = "failed"   // last check did not find the token
+)
+
+// The challenge is a TXT record named DomainChallengeLabel.<domain> whose
+// value is DomainTokenPrefix followed by the request's token
+const (
+	DomainChallengeLabel = "_makeitexist-challenge"
+	DomainTokenPrefix    = "makeitexist-verify="
+)
+
+// DomainVerification is the DNS TXT challenge for a request's custom domain
+type DomainVerification struct {
+	RequestID   uuid.UUID    `json:"request_id"`
+	HostingType HostingType  `json:"-"`
+	Domain      string       `json:"domain"`
+	Status      DomainStatus `json:"status"`
+	RecordType  string       `json:"record_type"`
+	RecordName  string       `json:"record_name"`
+	RecordValue string       `json:"record_value"`
+	Token       string       `json:"-"`
+	LastError   string       `json:"last_error,omitempty"`
+	CheckedAt   *time.Time   `json:"checked_at,omitempty"`
+	VerifiedAt  *time.Time   `json:"verified_at,omitempty"`
+}
+
+// SetRecord fills in the TXT record the student has to publish
+func (v *DomainVerification) SetRecord() {
+	v.RecordType = "TXT"
+	v.RecordName = DomainChallengeLabel + "." + v.Domain
+	v.RecordValue = DomainTokenPrefix + v.Token
+}
+
+// NormalizeDomain reduces what students type ("https://www.Shop.com/") to a
+// bare host name ("www.shop.com") and rejects anything that is not one
+func NormalizeDomain(s string) (string, error) {
+	host := strings.ToLower(strings.TrimSpace(s))
+	if strings.Contains(host, "://") {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Hostname()
+		}
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	labels := strings.Split(host, ".")
+	if len(host) > 253 || len(labels) < 2 {
+		return "", fmt.Errorf("%w: %q is not a domain name", ErrInvalidDomain, s)
+	}
+	for _, label := range labels {
+		if !validDomainLabel(label) {
+			return "", fmt.Errorf("%w: %q is not a domain name", ErrInvalidDomain, s)
+		}
+	}
+	return host, nil
+}
+
+func validDomainLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// TXTResolver looks up DNS TXT records. A name with no records returns an
+// empty slice, not an error.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DomainVerificationRepository defines the interface for domain verification data access
+type DomainVerificationRepository interface {
+	// Find returns the request's domain and challenge state, nil if the
+	// request does not exist
+	Find(ctx context.Context, requestID uuid.UUID) (*DomainVerification, error)
+	Save(ctx context.Context, v *DomainVerification) error
+}
+
+// DomainVerificationService defines the interface for custom-domain
+// verification. It guards the lifecycle: whitelabel requests cannot be
+// scheduled or built until their domain is verified.
+type DomainVerificationService interface {
+	TransitionGuard
+	// Get returns the challenge, issuing a token on first use
+	Get(ctx context.Context, requestID uuid.UUID) (*DomainVerification, error)
+	// Verify looks the token up in DNS and records the outcome
+	Verify(ctx context.Context, requestID uuid.UUID) (*DomainVerification, error)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import "errors"
+
+// Sentinel errors shared by services and handlers. Handlers map them to HTTP
+// status codes with errors.Is; the messages are safe to show to clients.
+var (
+	ErrUserNotFound           = errors.New("user not found")
+	ErrRequestNotFound        = errors.New("request not found")
+	ErrPricingRuleSetNotFound = errors.New("pricing rule set not found")
+	ErrUnknownAddOn           = errors.New("unknown add-on")
+	ErrForbidden              = errors.New("you do not have access to this resource")
+	ErrInvalidMoney           = errors.New("invalid money amount")
+	ErrCurrencyMismatch       = errors.New("currency mismatch")
+	ErrVersionConflict        = errors.New("the record was changed by someone else; reload it and try again")
+
+	ErrQuoteNotFound    = errors.New("quote not found")
+	ErrQuoteNotOpen     = errors.New("quote is no longer open")
+	ErrQuoteExpired     = errors.New("quote has expired")
+	ErrQuoteNotRequired = errors.New("free requests do not need a quote")
+	ErrInvalidQuote     = errors.New("invalid quote")
+	ErrQuoteNotAccepted = errors.New("an accepted quote is required before a paid request can be queued or scheduled")
+
+	ErrPaymentNotRequired     = errors.New("free requests do not need payment")
+	ErrPaymentRequired        = errors.New("request must be paid in full before deploying or completing")
+	ErrInvalidPaymentAmount   = errors.New("invalid payment amount")
+	ErrPaymentIntentNotFound  = errors.New("payment intent not found")
+	ErrUnknownPaymentProvider = errors.New("unknown payment provider")
+
+	ErrInvoiceNotFound    = errors.New("invoice not found")
+	ErrInvoiceNotRequired = errors.New("free requests are not invoiced")
+
+	ErrDiscountCodeNotFound  = errors.New("discount code not found")
+	ErrDiscountCodeExists    = errors.New("a discount code with this name already exists")
+	ErrDiscountNotFound      = errors.New("discount not found")
+	ErrDiscountNotApplicable = errors.New("discount cannot be applied")
+	ErrInvalidDiscount       = errors.New("invalid discount")
+	ErrPriceLocked           = errors.New("the price is locked once a quote has been accepted")
+
+	ErrQuotaExceeded         = errors.New("request quota exceeded")
+	ErrDuplicateRequest      = errors.New("this looks like a request you already submitted")
+	ErrQuotaOverrideNotFound = errors.New("quota override not found")
+	ErrInvalidQuotaOverride  = errors.New("invalid quota override")
+
+	ErrDeliverableNotFound    = errors.New("deliverable not found")
+	ErrDeliverablesNotStarted = errors.New("the request must be scheduled before work on its deliverables starts")
+	ErrChecklistIncomplete    = errors.New("the handover checklist must be complete before delivery")
+	ErrStatusDerived          = errors.New("this status follows from the request's deliverables; update them instead")
+
+	ErrNotInReview          = errors.New("the request is not awaiting review")
+	ErrAcceptanceRequired   = errors.New("the student must accept the delivery before the request is completed")
+	ErrRevisionLimitReached = errors.New("all included revision rounds have been used")
+	ErrNotAccepted          = errors.New("only accepted deliveries can be rated")
+
+	ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+	ErrBulkRejected         = errors.New("some requests could not be updated; nothing was changed")
+	ErrRequestClosed        = errors.New("the request is already closed")
+	ErrInvalidBuilder       = errors.New("builders must be staff accounts")
+
+	ErrInvalidExport = errors.New("invalid export")
+
+	ErrImportNotFound     = errors.New("import not found")
+	ErrInvalidImport      = errors.New("invalid import")
+	ErrImportNotValidated = errors.New("the import must pass a dry run before it is committed")
+	ErrImportCommitted    = errors.New("the import has already been committed")
+
+	ErrAutomationRuleNotFound = errors.New("automation rule not found")
+	ErrInvalidAutomationRule  = errors.New("invalid automation rule")
+	ErrNotificationNotFound   = errors.New("notification not found")
+
+	ErrInvalidDomain     = errors.New("invalid domain")
+	ErrNoCustomDomain    = errors.New("the request has no whitelabel domain to verify")
+	ErrDomainNotVerified = errors.New("the whitelabel domain has not been verified")
+
+	ErrCertificateNotFound  = errors.New("certificate not found")
+	ErrCertificatesDisabled = errors.New("certificate provisioning is not configured")
+	ErrChallengeNotFound    = errors.New("ACME challenge not found")
+
+	ErrNotQueued = errors.New("the request is not in the build queue")
+
+	ErrSlotNotFound          = errors.New("slot not found")
+	ErrSlotFull              = errors.New("the slot does not have enough capacity left")
+	ErrAlreadyOnStandby      = errors.New("the request is already on standby")
+	ErrStandbyNotFound       = errors.New("standby entry not found")
+	ErrStandbyNotWaiting     = errors.New("the standby entry is no longer waiting")
+	ErrRequestNotSchedulable = errors.New("only pending, queued or scheduled requests can be booked into or out of a slot")
+	ErrEntryNotFound         = errors.New("schedule entry not found")
+	ErrEntryReleased         = errors.New("the schedule entry has already been released")
+	ErrWeekendDerived        = errors.New("the scheduled weekend follows from the request's slot booking; use the schedule endpoints instead")
+	ErrScheduleDerived       = errors.New("requests are scheduled by booking them into a slot; use /admin/schedule/entries instead")
+
+	ErrNoWeekendSlots = errors.New("there are no slots for this weekend")
+	ErrEmptyPlan      = errors.New("the plan has no assignments")
+	ErrPlanStale      = errors.New("the schedule or queue changed since the plan was proposed; propose it again")
+
+	ErrAvailabilityNotFound = errors.New("availability not found")
+	ErrInvalidAvailability  = errors.New("invalid availability")
+	ErrBuilderUnavailable   = errors.New("the assigned builder is not available for enough hours that day")
+)
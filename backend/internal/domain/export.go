@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportFormat is the spreadsheet format of an export
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the MIME type of the format
+func (f ExportFormat) ContentType() string {
+	if f == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportDataset is what an export lists
+type ExportDataset string
+
+const (
+	ExportRequests  ExportDataset = "requests"
+	ExportUsers     ExportDataset = "users"
+	ExportSchedules ExportDataset = "schedules"
+)
+
+// ColumnType tells spreadsheet writers how to format a column's cells
+type ColumnType string
+
+const (
+	ColumnText     ColumnType = "text"
+	ColumnInteger  ColumnType = "integer"
+	ColumnMoney    ColumnType = "money" // decimal string, e.g. "3499.00"
+	ColumnBoolean  ColumnType = "boolean"
+	ColumnDate     ColumnType = "date"
+	ColumnDateTime ColumnType = "datetime"
+)
+
+// ExportColumn is one selectable column of a dataset
+type ExportColumn struct {
+	Key    string     `json:"key"`
+	Header string     `json:"header"`
+	Type   ColumnType `json:"type"`
+}
+
+// exportColumns lists every column of each dataset, in default order
+var exportColumns = map[ExportDataset][]ExportColumn{
+	ExportRequests: {
+		{"id", "Request ID", ColumnText},
+		{"title", "Title", ColumnText},
+		{"status", "Status", ColumnText},
+		{"request_type", "Type", ColumnText},
+		{"complexity", "Complexity", ColumnText},
+		{"hosting_type", "Hosting", ColumnText},
+		{"student_name", "Student", ColumnText},
+		{"student_email", "Student email", ColumnText},
+		{"student_id", "Student ID", ColumnText},
+		{"builder_name", "Builder", ColumnText},
+		{"is_free", "Free", ColumnBoolean},
+		{"estimated_cost", "Estimated cost", ColumnMoney},
+		{"currency", "Currency", ColumnText},
+		{"labels", "Labels", ColumnText},
+		{"scheduled_weekend", "Scheduled weekend", ColumnDate},
+		{"delivery_url", "Delivery URL", ColumnText},
+		{"created_at", "Created", ColumnDateTime},
+		{"completed_at", "Completed", ColumnDateTime},
+	},
+	ExportUsers: {
+		{"id", "User ID", ColumnText},
+		{"full_name", "Name", ColumnText},
+		{"email", "Email", ColumnText},
+		{"student_id", "Student ID", ColumnText},
+		{"role", "Role", ColumnText},
+		{"provider", "Sign-in provider", ColumnText},
+		{"is_verified", "Verified", ColumnBoolean},
+		{"requests", "Requests", ColumnInteger},
+		{"completed_requests", "Completed requests", ColumnInteger},
+		{"created_at", "Joined", ColumnDateTime},
+	},
+	ExportSchedules: {
+		{"slot_date", "Date", ColumnDate},
+		{"day_of_week", "Day", ColumnText},
+		{"request_id", "Request ID", ColumnText},
+		{"request_title", "Request", ColumnText},
+		{"student_name", "Student", ColumnText},
+		{"builder_name", "Builder", ColumnText},
+		{"estimated_hours", "Hours", ColumnInteger},
+		{"status", "Status", ColumnText},
+		{"start_time", "Start", ColumnDateTime},
+		{"end_time", "End", ColumnDateTime},
+		{"notes", "Notes", ColumnText},
+	},
+}
+
+// ExportColumnsFor returns every column of a dataset, or nil for an unknown
+// dataset
+func ExportColumnsFor(dataset ExportDataset) []ExportColumn {
+	return exportColumns[dataset]
+}
+
+// SelectExportColumns resolves column keys against a dataset, keeping the
+// caller's order. No keys selects every column.
+func SelectExportColumns(dataset ExportDataset, keys []string) ([]ExportColumn, error) {
+	all, ok := exportColumns[dataset]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown dataset %q", ErrInvalidExport, dataset)
+	}
+	if len(keys) == 0 {
+		return all, nil
+	}
+	selected := make([]ExportColumn, 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, col := range all {
+			if col.Key == key {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: unknown column %q for %s", ErrInvalidExport, key, dataset)
+		}
+	}
+	return selected, nil
+}
+
+// ExportQuery selects what to export. Requests are filtered like the admin
+// listing; users by role and join date; schedules by slot date.
+type ExportQuery struct {
+	Dataset  ExportDataset
+	Format   ExportFormat
+	Columns  []string
+	Requests RequestFilter
+	Role     *Role
+	From     *time.Time // inclusive
+	To       *time.Time // exclusive
+}
+
+// TableWriter streams rows to a spreadsheet. Values are nil, string, int64,
+// bool or time.Time, matching the column types given to WriteHeader.
+type TableWriter interface {
+	WriteHeader(columns []ExportColumn) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// TableWriterFactory creates a writer for a format
+type TableWriterFactory func(format ExportFormat, w io.Writer) (TableWriter, error)
+
+// ExportRepository streams dataset rows without loading them all. fn is
+// called once per row with values in column order; its error stops the scan.
+type ExportRepository interface {
+	Stream(ctx context.Context, q ExportQuery, columns []ExportColumn, fn func(values []interface{}) error) error
+}
+
+// ExportService defines the interface for spreadsheet exports
+type ExportService interface {
+	// Prepare validates a query and returns the columns it will export
+	Prepare(q *ExportQuery) ([]ExportColumn, error)
+	Export(ctx context.Context, q *ExportQuery, w io.Writer) error
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader lets clients retry a POST safely: a retry with the same
+// key gets the first response back instead of repeating the side effects
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength bounds the header; UUIDs are the expected format
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyLockTimeout is how long a key stays locked by an attempt that
+// never finished (e.g. the server restarted mid-request)
+const IdempotencyLockTimeout = 5 * time.Minute
+
+// IdempotencyRecord is a key and, once the first attempt finished, the
+// response to replay. Keys are scoped to the user who sent them.
+type IdempotencyRecord struct {
+	UserID       uuid.UUID
+	Key          string
+	Method       string
+	Path         string
+	Fingerprint  string // hex SHA-256 of method, path and body
+	StatusCode   int    // 0 while the first attempt is in progress
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// Completed reports whether the stored response can be replayed
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	// Reserve claims the key for a new attempt. If the key is already taken
+	// it returns the existing record instead; expired keys and attempts
+	// older than IdempotencyLockTimeout are taken over.
+	Reserve(ctx context.Context, rec *IdempotencyRecord) (existing *IdempotencyRecord, err error)
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, rec *IdempotencyRecord) error
+	// Release frees a reserved key so the request can be retried
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Import limits keep a whole batch comfortably in memory and in one transaction
+const (
+	MaxImportBytes = 5 << 20
+	MaxImportRows  = 5000
+)
+
+// ImportedLabel is added to every request created by an import
+const ImportedLabel = "imported"
+
+// ImportField is a request or student attribute a CSV column can map to
+type ImportField string
+
+const (
+	ImportExternalKey  ImportField = "external_key" // stable row ID from the source sheet
+	ImportTitle        ImportField = "title"
+	ImportDescription  ImportField = "description"
+	ImportRequestType  ImportField = "request_type"
+	ImportStatus       ImportField = "status"
+	ImportComplexity   ImportField = "complexity"
+	ImportHostingType  ImportField = "hosting_type"
+	ImportCost         ImportField = "estimated_cost"
+	ImportDeliveryURL  ImportField = "delivery_url"
+	ImportRepoURL      ImportField = "repo_url"
+	ImportCreatedAt    ImportField = "created_at"
+	ImportCompletedAt  ImportField = "completed_at"
+	ImportLabels       ImportField = "labels" // comma separated
+	ImportStudentEmail ImportField = "student_email"
+	ImportStudentName  ImportField = "student_name"
+	ImportStudentID    ImportField = "student_id"
+)
+
+// ImportFields lists every mappable field; the first four are required
+var ImportFields = []ImportField{
+	ImportExternalKey, ImportTitle, ImportRequestType, ImportStudentEmail,
+	ImportDescription, ImportStatus, ImportComplexity, ImportHostingType,
+	ImportCost, ImportDeliveryURL, ImportRepoURL, ImportCreatedAt,
+	ImportCompletedAt, ImportLabels, ImportStudentName, ImportStudentID,
+}
+
+// RequiredImportFields must be mapped before an import can be validated
+var RequiredImportFields = ImportFields[:4]
+
+// IsValid reports whether the field can be mapped
+func (f ImportField) IsValid() bool {
+	for _, known := range ImportFields {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportMapping maps fields to CSV column headers
+type ImportMapping map[ImportField]string
+
+// SuggestImportMapping maps every field whose name matches a header, ignoring
+// case, spaces and punctuation ("Student Email" matches student_email)
+func SuggestImportMapping(headers []string) ImportMapping {
+	mapping := ImportMapping{}
+	for _, field := range ImportFields {
+		for _, h := range headers {
+			if normalizeHeader(h) == normalizeHeader(string(field)) {
+				mapping[field] = h
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+func normalizeHeader(h string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(h) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ImportBatchStatus tracks an import batch through upload, validation and commit
+type ImportBatchStatus string
+
+const (
+	ImportUploaded  ImportBatchStatus = "uploaded"
+	ImportValidated ImportBatchStatus = "validated" // last dry run found no errors
+	ImportInvalid   ImportBatchStatus = "invalid"   // last dry run found errors
+	ImportCommitted ImportBatchStatus = "committed"
+)
+
+// ImportRowError is a problem with one CSV row. Row is the 1-based line in
+// the file, counting the header as line 1.
+type ImportRowError struct {
+	Row     int         `json:"row"`
+	Field   ImportField `json:"field,omitempty"`
+	Message string      `json:"message"`
+}
+
+// ImportReport is the result of a dry run, or of the commit once committed
+type ImportReport struct {
+	Rows        int              `json:"rows"`
+	Valid       int              `json:"valid"`
+	Create      int              `json:"create"`       // new requests
+	Skip        int              `json:"skip"`         // external key already imported
+	NewStudents int              `json:"new_students"` // placeholder accounts
+	Errors      []ImportRowError `json:"errors"`
+}
+
+// ImportBatch is an uploaded CSV awaiting mapping, validation and commit
+type ImportBatch struct {
+	ID          uuid.UUID         `json:"id"`
+	Filename    string            `json:"filename"`
+	Headers     []string          `json:"headers"`
+	Mapping     ImportMapping     `json:"mapping"`
+	Status      ImportBatchStatus `json:"status"`
+	RowCount    int               `json:"row_count"`
+	Sample      [][]string        `json:"sample,omitempty"` // first rows, for mapping
+	Report      *ImportReport     `json:"report,omitempty"`
+	Content     string            `json:"-"`
+	CreatedBy   uuid.UUID         `json:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CommittedAt *time.Time        `json:"committed_at,omitempty"`
+}
+
+// ImportRecord is a validated row, ready to be written
+type ImportRecord struct {
+	Row          int
+	ExternalKey  string
+	Request      BuildRequest // UserID is resolved at commit
+	StudentEmail string
+	StudentName  string
+	StudentID    string
+}
+
+// UpdateImportMappingRequest sets the column mapping of a batch
+type UpdateImportMappingRequest struct {
+	Mapping ImportMapping `json:"mapping" binding:"required"`
+}
+
+// ImportRepository defines the interface for import data access
+type ImportRepository interface {
+	CreateBatch(ctx context.Context, batch *ImportBatch) error
+	FindBatch(ctx context.Context, id uuid.UUID) (*ImportBatch, error)
+	UpdateBatch(ctx context.Context, batch *ImportBatch) error
+	// ExistingExternalKeys returns which of the keys are already imported
+	ExistingExternalKeys(ctx context.Context, keys []string) (map[string]bool, error)
+	// ExistingEmails returns which of the lower-cased emails have accounts
+	ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error)
+	// Commit writes the records in one transaction, matching students by
+	// email and creating placeholders for the rest. Records whose external
+	// key already exists are skipped. The batch is marked committed, with the
+	// counts in its report. It returns the requests and users created.
+	Commit(ctx context.Context, batch *ImportBatch, records []ImportRecord) (created, users int, err error)
+}
+
+// ImportService defines the interface for the legacy CSV import
+type ImportService interface {
+	Upload(ctx context.Context, createdBy uuid.UUID, filename string, content []byte) (*ImportBatch, error)
+	Get(ctx context.Context, id uuid.UUID) (*ImportBatch, error)
+	SetMapping(ctx context.Context, id uuid.UUID, mapping ImportMapping) (*ImportBatch, error)
+	DryRun(ctx context.Context, id uuid.UUID) (*ImportBatch, error)
+	Commit(ctx context.Context, id uuid.UUID) (*ImportBatch, error)
+}
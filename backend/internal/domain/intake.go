@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// QuotaUsage is where a student stands against the intake limits
+type QuotaUsage struct {
+	OpenFree       int       `json:"open_free"`
+	MaxOpenFree    int       `json:"max_open_free,omitempty"`
+	Semester       string    `json:"semester"`
+	SemesterStart  time.Time `json:"semester_start"`
+	SemesterCount  int       `json:"semester_count"`
+	MaxPerSemester int       `json:"max_per_semester,omitempty"`
+	Exempt         bool      `json:"exempt"` // staff, or a student with an override
+}
+
+// DuplicateMatch is an earlier request that looks like the one being submitted
+type DuplicateMatch struct {
+	RequestID  uuid.UUID     `json:"request_id"`
+	Title      string        `json:"title"`
+	Status     RequestStatus `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	Similarity float64       `json:"similarity"`
+}
+
+// IntakeReport is the outcome of checking a new request against the intake
+// policy. Err is non-nil when the request must be refused.
+type IntakeReport struct {
+	Quota      QuotaUsage       `json:"quota"`
+	Duplicates []DuplicateMatch `json:"possible_duplicates"`
+	Allowed    bool             `json:"allowed"`
+	Reason     string           `json:"reason,omitempty"`
+
+	err error
+}
+
+// Refuse marks the report as blocked by err
+func (r *IntakeReport) Refuse(err error) {
+	if r.err != nil {
+		return
+	}
+	r.err = err
+	r.Allowed = false
+	r.Reason = err.Error()
+}
+
+// Err returns why the request is refused, or nil
+func (r *IntakeReport) Err() error {
+	return r.err
+}
+
+// QuotaOverride exempts a student from the intake limits, optionally until a
+// given time
+type QuotaOverride struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Reason    string     `json:"reason"`
+	GrantedBy uuid.UUID  `json:"granted_by"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ActiveAt reports whether the override applies at t
+func (o *QuotaOverride) ActiveAt(t time.Time) bool {
+	return o.ExpiresAt == nil || t.Before(*o.ExpiresAt)
+}
+
+// GrantQuotaOverrideRequest is the admin input for exempting a student
+type GrantQuotaOverrideRequest struct {
+	Reason    string     `json:"reason" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Semester returns the academic half-year containing t: January–June is S1,
+// July–December is S2
+func Semester(t time.Time) (string, time.Time) {
+	half, month := 1, time.January
+	if t.Month() >= time.July {
+		half, month = 2, time.July
+	}
+	return fmt.Sprintf("%d-S%d", t.Year(), half), time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// RequestSimilarity scores how alike two requests read, from 0 to 1. Titles
+// and descriptions are compared separately and the description weighs more,
+// since short titles like "Portfolio" collide easily.
+func RequestSimilarity(titleA, descA, titleB, descB string) float64 {
+	return 0.4*TextSimilarity(titleA, titleB) + 0.6*TextSimilarity(descA, descB)
+}
+
+// TextSimilarity is the Dice coefficient of the character trigrams of two
+// texts after lowercasing and collapsing punctuation and whitespace
+func TextSimilarity(a, b string) float64 {
+	ga, gb := trigrams(a), trigrams(b)
+	if len(ga) == 0 && len(gb) == 0 {
+		return 1
+	}
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range ga {
+		if gb[g] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ga)+len(gb))
+}
+
+func trigrams(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	grams := map[string]bool{}
+	if len(words) == 0 {
+		return grams
+	}
+	text := []rune(" " + strings.Join(words, " ") + " ")
+	for i := 0; i+3 <= len(text); i++ {
+		grams[string(text[i:i+3])] = true
+	}
+	return grams
+}
+
+// QuotaOverrideRepository defines the interface for quota override data access
+type QuotaOverrideRepository interface {
+	Upsert(ctx context.Context, o *QuotaOverride) error
+	FindByUser(ctx context.Context, userID uuid.UUID) (*QuotaOverride, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// IntakeService checks new requests against quotas and earlier submissions
+type IntakeService interface {
+	Check(ctx context.Context, user *User, in *CreateBuildRequest) (*IntakeReport, error)
+	// Preview runs Check for a user without creating anything
+	Preview(ctx context.Context, userID uuid.UUID, in *CreateBuildRequest) (*IntakeReport, error)
+	GetOverride(ctx context.Context, userID uuid.UUID) (*QuotaOverride, error)
+	GrantOverride(ctx context.Context, grantedBy, userID uuid.UUID, req *GrantQuotaOverrideRequest) (*QuotaOverride, error)
+	RevokeOverride(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceKind distinguishes tax invoices from payment receipts. Each kind has
+// its own number series.
+type InvoiceKind string
+
+const (
+	InvoiceTax     InvoiceKind = "invoice"
+	InvoiceReceipt InvoiceKind = "receipt"
+)
+
+// InvoiceLine is a single taxable line. Amounts are GST-inclusive on quotes,
+// so the taxable value and tax are derived from the line total.
+type InvoiceLine struct {
+	Description string `json:"description"`
+	SACCode     string `json:"sac_code"`
+	Taxable     Money  `json:"taxable"`
+	CGST        Money  `json:"cgst"`
+	SGST        Money  `json:"sgst"`
+	IGST        Money  `json:"igst"`
+	Total       Money  `json:"total"`
+}
+
+// Invoice is an issued tax invoice or receipt. Once issued it is never
+// changed; corrections are made with a new document.
+type Invoice struct {
+	ID            uuid.UUID     `json:"id"`
+	RequestID     uuid.UUID     `json:"request_id"`
+	Kind          InvoiceKind   `json:"kind"`
+	Number        string        `json:"number"`
+	FinancialYear string        `json:"financial_year"`
+	Sequence      int           `json:"sequence"`
+	SourceRef     string        `json:"source_ref"` // quote ID for invoices, ledger entry ID for receipts
+	IssuedAt      time.Time     `json:"issued_at"`
+	Currency      string        `json:"currency"`
+	BuyerName     string        `json:"buyer_name"`
+	BuyerEmail    string        `json:"buyer_email"`
+	PlaceOfSupply string        `json:"place_of_supply"`
+	Lines         []InvoiceLine `json:"lines"`
+	TaxableAmount Money         `json:"taxable_amount"`
+	CGST          Money         `json:"cgst"`
+	SGST          Money         `json:"sgst"`
+	IGST          Money         `json:"igst"`
+	Total         Money         `json:"total"`
+	ArtifactID    uuid.UUID     `json:"artifact_id"`
+}
+
+// FinancialYear returns the Indian financial year (April to March) containing
+// t, formatted as "2025-26".
+func FinancialYear(t time.Time) string {
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// SplitGST derives the taxable value and tax from a GST-inclusive amount.
+// Intra-state supplies split the tax equally between CGST and SGST;
+// inter-state supplies charge IGST. The parts always add back up to total.
+func SplitGST(total Money, ratePercent int, interState bool) (taxable, cgst, sgst, igst Money) {
+	zero := NewMoney(0, total.Currency)
+	taxable = total.Ratio(100, int64(100+ratePercent))
+	tax := total.Sub(taxable)
+	if interState {
+		return taxable, zero, zero, tax
+	}
+	halves := tax.Allocate(1, 1)
+	return taxable, halves[0], halves[1], zero
+}
+
+// Artifact is a generated file attached to a request (invoices, receipts, ...)
+type Artifact struct {
+	ID          uuid.UUID `json:"id"`
+	RequestID   uuid.UUID `json:"request_id"`
+	Kind        string    `json:"kind"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	Content     []byte    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// InvoiceRenderer turns an issued invoice into a document
+type InvoiceRenderer interface {
+	Render(inv *Invoice) (*Artifact, error)
+}
+
+// InvoiceRepository defines the interface for invoice data access
+type InvoiceRepository interface {
+	// Issue allocates the next sequence in the kind's series for the invoice's
+	// financial year and hands the invoice to finalize, which assigns the
+	// number and renders the document. Both are stored atomically, so a failed
+	// render never leaves a gap. If a document already exists for the same
+	// kind and source it is returned instead and nothing is allocated.
+	Issue(ctx context.Context, inv *Invoice, finalize func(*Invoice) (*Artifact, error)) (*Invoice, error)
+	ListByRequest(ctx context.Context, requestID uuid.UUID) ([]Invoice, error)
+	FindArtifact(ctx context.Context, id uuid.UUID) (*Artifact, error)
+}
+
+// InvoiceService defines invoicing business logic. It observes the request
+// lifecycle and issues documents when a paid request is completed.
+type InvoiceService interface {
+	TransitionObserver
+	IssueForRequest(ctx context.Context, requestID uuid.UUID) ([]Invoice, error)
+	ListForRequest(ctx context.Context, requestID uuid.UUID) ([]Invoice, error)
+	GetDocument(ctx context.Context, requestID, invoiceID uuid.UUID) (*Artifact, error)
+}
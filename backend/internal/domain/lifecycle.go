@@ -0,0 +1,58 @@
+package domain
+
+import "context"
+
+// TransitionGuard vets a request status change before it is persisted.
+// Subsystems that gate the lifecycle (quotes, payments, ...) implement it and
+// are handed to the services that move requests between statuses.
+type TransitionGuard interface {
+	CheckTransition(ctx context.Context, req *BuildRequest, to RequestStatus) error
+}
+
+// TransitionObserver reacts to a status change after it has been persisted.
+// Observers cannot veto the change; they log their own failures.
+type TransitionObserver interface {
+	AfterTransition(ctx context.Context, req *BuildRequest, from RequestStatus)
+}
+
+// Lifecycle bundles the guards consulted before a status change and the
+// observers notified after it. Services share one *Lifecycle, since some of
+// them are guards or observers themselves and are added once constructed.
+type Lifecycle struct {
+	Guards    []TransitionGuard
+	Observers []TransitionObserver
+}
+
+// Check runs every guard and returns the first refusal
+func (l *Lifecycle) Check(ctx context.Context, req *BuildRequest, to RequestStatus) error {
+	return CheckTransitionGuards(ctx, l.Guards, req, to)
+}
+
+// CheckExcept runs every guard but self. A guard that makes transitions of
+// its own uses it, as it vets hand edits against state it is about to write.
+func (l *Lifecycle) CheckExcept(ctx context.Context, req *BuildRequest, to RequestStatus, self TransitionGuard) error {
+	guards := make([]TransitionGuard, 0, len(l.Guards))
+	for _, g := range l.Guards {
+		if g != self {
+			guards = append(guards, g)
+		}
+	}
+	return CheckTransitionGuards(ctx, guards, req, to)
+}
+
+// Notify tells every observer that req moved from the given status
+func (l *Lifecycle) Notify(ctx context.Context, req *BuildRequest, from RequestStatus) {
+	for _, o := range l.Observers {
+		o.AfterTransition(ctx, req, from)
+	}
+}
+
+// CheckTransitionGuards runs every guard and returns the first refusal
+func CheckTransitionGuards(ctx context.Context, guards []TransitionGuard, req *BuildRequest, to RequestStatus) error {
+	for _, g := range guards {
+		if err := g.CheckTransition(ctx, req, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
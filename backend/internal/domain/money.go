@@ -0,0 +1,289 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DefaultCurrency is assumed for amounts that arrive without one (JSON
+// numbers, DECIMAL columns)
+const DefaultCurrency = "INR"
+
+// Money is an exact amount in minor units (paise) of a currency.
+//
+// Arithmetic never goes through float64. Results that fall between two minor
+// units are rounded half away from zero. Amounts of different currencies
+// cannot be mixed; an empty currency takes on the other operand's. Add, Sub
+// and Cmp panic on a mismatch, so amounts that arrive from outside (provider
+// events, stored ledgers) go through CheckedAdd, CheckedSub and CheckedCmp.
+//
+// In JSON a Money is a plain decimal number with two places (1499.50); the
+// currency travels in the surrounding object. In PostgreSQL it maps to
+// NUMERIC/DECIMAL columns.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// NewMoney creates an amount from minor units
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// Rupees creates an INR amount from whole rupees
+func Rupees(whole int64) Money {
+	return Money{Minor: whole * 100, Currency: DefaultCurrency}
+}
+
+// ParseMoney parses a decimal string such as "1499.5" exactly. More than two
+// decimal places is an error rather than a silent rounding.
+func ParseMoney(s, currency string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q has more than two decimal places", ErrInvalidMoney, s)
+	}
+	return Money{Minor: r.Num().Int64(), Currency: currency}, nil
+}
+
+// In returns the same number of minor units labelled with another currency.
+// It does not convert.
+func (m Money) In(currency string) Money {
+	return Money{Minor: m.Minor, Currency: currency}
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) Money {
+	return must(m.CheckedAdd(o))
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) Money {
+	return must(m.CheckedSub(o))
+}
+
+// CheckedAdd returns m + o, or ErrCurrencyMismatch
+func (m Money) CheckedAdd(o Money) (Money, error) {
+	currency, err := m.common(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: m.Minor + o.Minor, Currency: currency}, nil
+}
+
+// CheckedSub returns m - o, or ErrCurrencyMismatch
+func (m Money) CheckedSub(o Money) (Money, error) {
+	currency, err := m.common(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Minor: m.Minor - o.Minor, Currency: currency}, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Minor: -m.Minor, Currency: m.Currency}
+}
+
+// Mul multiplies by a factor such as a pricing multiplier. The factor is
+// taken at its shortest decimal representation (1.15, not 1.1499999...), so
+// the only rounding is the final one to minor units.
+func (m Money) Mul(factor float64) Money {
+	f, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+	if !ok {
+		panic(fmt.Sprintf("money: invalid factor %v", factor))
+	}
+	return Money{Minor: roundRat(f.Mul(f, new(big.Rat).SetInt64(m.Minor))), Currency: m.Currency}
+}
+
+// Ratio returns m * num / den exactly, rounded once to minor units
+func (m Money) Ratio(num, den int64) Money {
+	r := big.NewRat(num, den)
+	return Money{Minor: roundRat(r.Mul(r, new(big.Rat).SetInt64(m.Minor))), Currency: m.Currency}
+}
+
+// Percent returns pct percent of m, e.g. a discount or tax component
+func (m Money) Percent(pct float64) Money {
+	return m.Mul(pct / 100)
+}
+
+// Allocate splits m into parts proportional to weights without losing a
+// paisa; the remainder goes to the earliest parts
+func (m Money) Allocate(weights ...int64) []Money {
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	parts := make([]Money, len(weights))
+	if total == 0 {
+		for i := range parts {
+			parts[i] = Money{Currency: m.Currency}
+		}
+		return parts
+	}
+	remaining := m.Minor
+	for i, w := range weights {
+		parts[i] = Money{Minor: m.Minor * w / total, Currency: m.Currency}
+		remaining -= parts[i].Minor
+	}
+	for i := 0; remaining != 0 && i < len(parts); i++ {
+		step := int64(1)
+		if remaining < 0 {
+			step = -1
+		}
+		parts[i].Minor += step
+		remaining -= step
+	}
+	return parts
+}
+
+// Cmp compares two amounts of the same currency: -1, 0 or +1
+func (m Money) Cmp(o Money) int {
+	return must(m.CheckedCmp(o))
+}
+
+// CheckedCmp compares two amounts, or returns ErrCurrencyMismatch
+func (m Money) CheckedCmp(o Money) (int, error) {
+	if _, err := m.common(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool { return m.Minor == 0 }
+
+// IsPositive reports whether the amount is above zero
+func (m Money) IsPositive() bool { return m.Minor > 0 }
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+
+// Decimal formats the amount with two decimal places, e.g. "1499.50"
+func (m Money) Decimal() string {
+	sign := ""
+	minor := m.Minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/100, minor%100)
+}
+
+// String formats the amount with its currency, e.g. "INR 1499.50"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Currency + " " + m.Decimal()
+}
+
+func (m Money) common(o Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return o.Currency, nil
+	case o.Currency == "" || o.Currency == m.Currency:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+// must unwraps the result of arithmetic the caller knows is in one currency
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic("money: " + err.Error())
+	}
+	return v
+}
+
+// MarshalJSON encodes the amount as a decimal number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or numeric string. The currency is left
+// as DefaultCurrency; callers relabel it from the surrounding object.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*m = Money{}
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	} else {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMoney, s)
+		}
+	}
+	parsed, err := ParseMoney(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner so Money can be scanned from
+// NUMERIC/DECIMAL columns without going through float64. NULL scans as zero.
+func (m *Money) ScanNumeric(v pgtype.Numeric) error {
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if !v.Valid {
+		*m = Money{Currency: currency}
+		return nil
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("%w: non-finite numeric", ErrInvalidMoney)
+	}
+
+	// value = Int * 10^Exp, so minor units = Int * 10^(Exp+2)
+	r := new(big.Rat).SetInt(v.Int)
+	exp := int64(v.Exp) + 2
+	if exp >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(-exp), nil)))
+	}
+	*m = Money{Minor: roundRat(r), Currency: currency}
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer for query parameters
+func (m Money) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(m.Minor), Exp: -2, Valid: true}, nil
+}
+
+// roundRat rounds to the nearest integer, halves away from zero
+func roundRat(r *big.Rat) int64 {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an in-app message for one user
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	RequestID *uuid.UUID `json:"request_id,omitempty"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationRepository defines the interface for notification data access
+type NotificationRepository interface {
+	Create(ctx context.Context, n *Notification) error
+	// CreateForRole sends a copy of n to every user with the role
+	CreateForRole(ctx context.Context, role Role, n *Notification) (int, error)
+	ListForUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]Notification, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// NotificationService defines the interface for reading notifications
+type NotificationService interface {
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]Notification, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentIntentStatus tracks how much of an intent has been collected
+type PaymentIntentStatus string
+
+const (
+	IntentCreated       PaymentIntentStatus = "created"
+	IntentPartiallyPaid PaymentIntentStatus = "partially_paid"
+	IntentPaid          PaymentIntentStatus = "paid"
+	IntentRefunded      PaymentIntentStatus = "refunded"
+	IntentCancelled     PaymentIntentStatus = "cancelled"
+)
+
+// LedgerEntryKind is the direction of money movement
+type LedgerEntryKind string
+
+const (
+	LedgerPayment LedgerEntryKind = "payment"
+	LedgerRefund  LedgerEntryKind = "refund"
+)
+
+// PaymentIntent is a request to collect money against an accepted quote
+type PaymentIntent struct {
+	ID              uuid.UUID           `json:"id"`
+	RequestID       uuid.UUID           `json:"request_id"`
+	QuoteID         uuid.UUID           `json:"quote_id"`
+	Provider        string              `json:"provider"`
+	ProviderOrderID string              `json:"provider_order_id"`
+	Amount          Money               `json:"amount"`
+	Currency        string              `json:"currency"`
+	AmountPaid      Money               `json:"amount_paid"`
+	AmountRefunded  Money               `json:"amount_refunded"`
+	Status          PaymentIntentStatus `json:"status"`
+	CreatedBy       uuid.UUID           `json:"created_by"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// Apply folds a ledger entry into the intent's running totals and status.
+// An entry in another currency is refused with ErrCurrencyMismatch.
+func (p *PaymentIntent) Apply(entry *LedgerEntry) error {
+	if entry.Currency != p.Currency {
+		return fmt.Errorf("%w: %s entry for a %s payment", ErrCurrencyMismatch, entry.Currency, p.Currency)
+	}
+	var err error
+	switch entry.Kind {
+	case LedgerPayment:
+		p.AmountPaid, err = p.AmountPaid.CheckedAdd(entry.Amount)
+	case LedgerRefund:
+		p.AmountRefunded, err = p.AmountRefunded.CheckedAdd(entry.Amount)
+	}
+	if err != nil {
+		return err
+	}
+
+	net := p.AmountPaid.Sub(p.AmountRefunded)
+	switch {
+	case p.AmountRefunded.IsPositive() && !net.IsPositive():
+		p.Status = IntentRefunded
+	case net.Cmp(p.Amount) >= 0:
+		p.Status = IntentPaid
+	case net.IsPositive():
+		p.Status = IntentPartiallyPaid
+	default:
+		p.Status = IntentCreated
+	}
+	return nil
+}
+
+// LedgerEntry is an immutable record of money received or returned.
+// ProviderRef (the provider's payment or refund ID) makes ingestion idempotent.
+type LedgerEntry struct {
+	ID          uuid.UUID       `json:"id"`
+	RequestID   uuid.UUID       `json:"request_id"`
+	IntentID    uuid.UUID       `json:"intent_id"`
+	Kind        LedgerEntryKind `json:"kind"`
+	Amount      Money           `json:"amount"`
+	Currency    string          `json:"currency"`
+	Provider    string          `json:"provider"`
+	ProviderRef string          `json:"provider_ref"`
+	PaymentRef  string          `json:"payment_ref,omitempty"` // refunds: the payment being refunded
+	Note        string          `json:"note,omitempty"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Refundable is what is left to refund of the captured payment paymentRef:
+// its amount less the refunds already recorded against it. It reports false
+// when the ledger has no such payment.
+func Refundable(ledger []LedgerEntry, provider, paymentRef string) (Money, bool, error) {
+	var captured *LedgerEntry
+	for i := range ledger {
+		e := &ledger[i]
+		if e.Kind == LedgerPayment && e.Provider == provider && e.ProviderRef == paymentRef {
+			captured = e
+			break
+		}
+	}
+	if captured == nil {
+		return Money{}, false, nil
+	}
+
+	left := captured.Amount
+	for _, e := range ledger {
+		if e.Kind != LedgerRefund || e.Provider != provider || e.PaymentRef != paymentRef {
+			continue
+		}
+		var err error
+		if left, err = left.CheckedSub(e.Amount); err != nil {
+			return Money{}, true, err
+		}
+	}
+	return left, true, nil
+}
+
+// PaymentEvent is a provider-neutral view of a verified webhook event
+type PaymentEvent struct {
+	Kind       LedgerEntryKind
+	OrderID    string // set for payments
+	PaymentID  string // the captured payment, or the payment a refund belongs to
+	RefundID   string // set for refunds
+	Amount     Money
+	Currency   string
+	OccurredAt time.Time
+}
+
+// PaymentStanding summarises whether a request has been paid for
+type PaymentStanding string
+
+const (
+	PaymentNotRequired PaymentStanding = "not_required"
+	PaymentUnpaid      PaymentStanding = "unpaid"
+	PaymentPartial     PaymentStanding = "partially_paid"
+	PaymentPaidInFull  PaymentStanding = "paid_in_full"
+)
+
+// PaymentSummary is the payment position of a single request
+type PaymentSummary struct {
+	RequestID      uuid.UUID       `json:"request_id"`
+	Standing       PaymentStanding `json:"standing"`
+	Currency       string          `json:"currency"`
+	AmountDue      Money           `json:"amount_due"`
+	AmountPaid     Money           `json:"amount_paid"`
+	AmountRefunded Money           `json:"amount_refunded"`
+	Balance        Money           `json:"balance"`
+	Intents        []PaymentIntent `json:"intents"`
+	Ledger         []LedgerEntry   `json:"ledger"`
+}
+
+// NetPaid is the amount kept after refunds
+func (s *PaymentSummary) NetPaid() Money {
+	return s.AmountPaid.Sub(s.AmountRefunded)
+}
+
+// CreatePaymentIntentRequest starts a payment; Amount defaults to the balance
+type CreatePaymentIntentRequest struct {
+	Amount *Money `json:"amount"`
+}
+
+// RefundRequest is the admin input for refunding part or all of a payment
+type RefundRequest struct {
+	PaymentRef string `json:"payment_ref" binding:"required"`
+	Amount     Money  `json:"amount"`
+	Reason     string `json:"reason" binding:"required"`
+}
+
+// PaymentProvider is a payment gateway integration
+type PaymentProvider interface {
+	Name() string
+	// CreateOrder registers the intent with the gateway and returns its order ID
+	CreateOrder(ctx context.Context, intent *PaymentIntent) (string, error)
+	// Refund returns money for a captured payment and returns the refund ID
+	Refund(ctx context.Context, paymentID string, amount Money) (string, error)
+	// ParseWebhook verifies the signature and decodes the events in a webhook body
+	ParseWebhook(signature string, body []byte) ([]PaymentEvent, error)
+}
+
+// PaymentRepository defines the interface for payment data access
+type PaymentRepository interface {
+	CreateIntent(ctx context.Context, intent *PaymentIntent) error
+	FindIntentByID(ctx context.Context, id uuid.UUID) (*PaymentIntent, error)
+	// LockIntentByID reads the intent with a row lock held until the
+	// transaction ends; call it inside Transactor.WithinTx
+	LockIntentByID(ctx context.Context, id uuid.UUID) (*PaymentIntent, error)
+	FindIntentByOrderID(ctx context.Context, provider, orderID string) (*PaymentIntent, error)
+	FindIntentByPaymentRef(ctx context.Context, provider, paymentRef string) (*PaymentIntent, error)
+	ListIntentsByRequest(ctx context.Context, requestID uuid.UUID) ([]PaymentIntent, error)
+	ListLedgerByRequest(ctx context.Context, requestID uuid.UUID) ([]LedgerEntry, error)
+	// ApplyLedgerEntry records the entry and updates its intent atomically.
+	// It reports false when the entry was already recorded.
+	ApplyLedgerEntry(ctx context.Context, entry *LedgerEntry) (bool, error)
+}
+
+// PaymentService defines the interface for payment business logic
+type PaymentService interface {
+	TransitionGuard
+
+	CreateIntent(ctx context.Context, userID, requestID uuid.UUID, req *CreatePaymentIntentRequest) (*PaymentIntent, error)
+	Summary(ctx context.Context, requestID uuid.UUID) (*PaymentSummary, error)
+	HandleWebhook(ctx context.Context, provider, signature string, body []byte) (int, error)
+	Refund(ctx context.Context, intentID uuid.UUID, req *RefundRequest) (*LedgerEntry, error)
+}
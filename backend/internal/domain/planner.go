@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a queued request is left out of a weekend plan
+const (
+	PlanSkipInProgress = "the student already has a build in progress"
+	PlanSkipOnePerWeek = "the student already has a request in this weekend's plan"
+	PlanSkipTooLong    = "needs more hours than any slot has"
+	PlanSkipNoRoom     = "no slot has enough hours or places left"
+	PlanSkipBuilder    = "the assigned builder does not have enough hours left this weekend"
+)
+
+// PlanAssignment books one queued request into a slot
+type PlanAssignment struct {
+	RequestID uuid.UUID `json:"request_id" binding:"required"`
+	Hours     int       `json:"estimated_hours" binding:"required,min=1,max=8"`
+	Title     string    `json:"title,omitempty"`
+	Position  int       `json:"position,omitempty"` // in the build queue
+}
+
+// PlanSkip is a queued request the plan leaves out, and why
+type PlanSkip struct {
+	RequestID uuid.UUID `json:"request_id"`
+	Title     string    `json:"title"`
+	Position  int       `json:"position"`
+	Hours     int       `json:"estimated_hours"`
+	Reason    string    `json:"reason"`
+}
+
+// SlotUsage is how much of a slot is booked
+type SlotUsage struct {
+	Hours    int `json:"booked_hours"`
+	Projects int `json:"booked_projects"`
+}
+
+// SlotPlan is the change the plan makes to one slot: what it adds, and the
+// slot's bookings before and after. Version is the slot version the plan was
+// made against.
+type SlotPlan struct {
+	SlotID      uuid.UUID        `json:"slot_id" binding:"required"`
+	Version     int              `json:"version" binding:"required,min=1"`
+	Date        time.Time        `json:"date"`
+	DayOfWeek   string           `json:"day_of_week"`
+	TotalHours  int              `json:"total_hours"`
+	MaxProjects int              `json:"max_projects"`
+	Before      SlotUsage        `json:"before"`
+	After       SlotUsage        `json:"after"`
+	Add         []PlanAssignment `json:"add" binding:"dive"`
+}
+
+// WeekendPlan proposes bookings for one weekend. Admins review it, may drop
+// assignments, and post it back to apply it.
+type WeekendPlan struct {
+	Weekend time.Time  `json:"weekend"` // the Saturday
+	Slots   []SlotPlan `json:"slots" binding:"required,min=1,dive"`
+	Skipped []PlanSkip `json:"skipped"`
+}
+
+// Assignments counts the bookings the plan adds
+func (p *WeekendPlan) Assignments() int {
+	n := 0
+	for _, s := range p.Slots {
+		n += len(s.Add)
+	}
+	return n
+}
+
+// PlanWeekend packs ranked queued requests into the weekend's slots. Requests
+// are taken in queue order, so nobody is passed over for a later request
+// that needs the same room; a request that does not fit is skipped and
+// smaller ones behind it fill the gap. Each goes to the slot it fills most
+// tightly, keeping large gaps for large requests. Under OnePerStudent a
+// student gets at most one request per weekend, and none while another of
+// theirs is being built. Builders maps slots to each builder's hours left in
+// them; a request with a builder only goes where that builder has the time.
+// Slots missing from it are not limited by builder.
+func PlanWeekend(ranked []QueueEntry, slots []WeekendSlot, builders map[uuid.UUID]BuilderHours, policy QueuePolicy) *WeekendPlan {
+	plan := &WeekendPlan{Slots: make([]SlotPlan, 0, len(slots)), Skipped: []PlanSkip{}}
+	for _, s := range slots {
+		used := SlotUsage{Hours: s.BookedHours, Projects: s.BookedProjects}
+		plan.Slots = append(plan.Slots, SlotPlan{
+			SlotID:      s.ID,
+			Version:     s.Version,
+			Date:        s.Date,
+			DayOfWeek:   s.DayOfWeek,
+			TotalHours:  s.TotalHours,
+			MaxProjects: s.MaxProjects,
+			Before:      used,
+			After:       used,
+			Add:         []PlanAssignment{},
+		})
+	}
+	sort.SliceStable(plan.Slots, func(i, j int) bool { return plan.Slots[i].Date.Before(plan.Slots[j].Date) })
+	if len(slots) > 0 {
+		plan.Weekend = weekendSaturday(plan.Slots[0].Date)
+	}
+
+	longest := 0
+	left := make(map[uuid.UUID]BuilderHours, len(builders))
+	for _, s := range slots {
+		longest = max(longest, s.TotalHours)
+		if b, ok := builders[s.ID]; ok {
+			left[s.ID] = maps.Clone(b)
+		}
+	}
+
+	planned := map[uuid.UUID]bool{}
+	for _, e := range ranked {
+		hours := EstimatedHours(e.Complexity)
+		skip := func(reason string) {
+			plan.Skipped = append(plan.Skipped, PlanSkip{
+				RequestID: e.RequestID,
+				Title:     e.Title,
+				Position:  e.Position,
+				Hours:     hours,
+				Reason:    reason,
+			})
+		}
+		switch {
+		case policy.OnePerStudent && e.ActiveBuilds > 0:
+			skip(PlanSkipInProgress)
+			continue
+		case policy.OnePerStudent && planned[e.UserID]:
+			skip(PlanSkipOnePerWeek)
+			continue
+		case hours > longest:
+			skip(PlanSkipTooLong)
+			continue
+		}
+
+		best, busy := -1, false
+		for i := range plan.Slots {
+			sp := &plan.Slots[i]
+			room := sp.TotalHours - sp.After.Hours
+			if sp.After.Projects >= sp.MaxProjects || room < hours {
+				continue
+			}
+			if b, ok := left[sp.SlotID]; ok && e.BuilderID != nil && b[*e.BuilderID] < hours {
+				busy = true
+				continue
+			}
+			if best < 0 || room < plan.Slots[best].TotalHours-plan.Slots[best].After.Hours {
+				best = i
+			}
+		}
+		if best < 0 {
+			if busy {
+				skip(PlanSkipBuilder)
+			} else {
+				skip(PlanSkipNoRoom)
+			}
+			continue
+		}
+
+		sp := &plan.Slots[best]
+		sp.Add = append(sp.Add, PlanAssignment{
+			RequestID: e.RequestID,
+			Hours:     hours,
+			Title:     e.Title,
+			Position:  e.Position,
+		})
+		sp.After.Hours += hours
+		sp.After.Projects++
+		if b, ok := left[sp.SlotID]; ok && e.BuilderID != nil {
+			b[*e.BuilderID] -= hours
+		}
+		planned[e.UserID] = true
+	}
+	return plan
+}
+
+// PlannerService proposes and applies weekend plans
+type PlannerService interface {
+	// Propose plans the weekend starting on the given Saturday without
+	// booking anything
+	Propose(ctx context.Context, weekend time.Time) (*WeekendPlan, error)
+	// Apply books every assignment in the plan in one transaction. It fails
+	// with ErrPlanStale, booking nothing, if a slot or request changed since
+	// the plan was proposed.
+	Apply(ctx context.Context, plan *WeekendPlan) ([]ScheduleEntry, error)
+}
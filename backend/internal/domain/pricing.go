@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricingRuleStatus tracks the lifecycle of a pricing rule set
+type PricingRuleStatus string
+
+const (
+	PricingDraft   PricingRuleStatus = "draft"
+	PricingActive  PricingRuleStatus = "active"
+	PricingRetired PricingRuleStatus = "retired"
+)
+
+// PricingAddOn is an optional extra a request can opt into
+type PricingAddOn struct {
+	Code      string        `json:"code"`
+	Label     string        `json:"label"`
+	Amount    Money         `json:"amount"`
+	AppliesTo []RequestType `json:"applies_to,omitempty"` // empty means every type
+}
+
+// PricingRules is the configurable price list evaluated for each request
+type PricingRules struct {
+	Currency          string                    `json:"currency"`
+	FreeWebsites      bool                      `json:"free_websites"` // non-whitelabel websites cost nothing
+	BasePrices        map[ComplexityLevel]Money `json:"base_prices"`
+	TypeMultipliers   map[RequestType]float64   `json:"type_multipliers"`
+	HostingSurcharges map[HostingType]Money     `json:"hosting_surcharges"`
+	AddOns            []PricingAddOn            `json:"add_ons"`
+}
+
+// PricingRuleSet is a versioned, immutable snapshot of pricing rules
+type PricingRuleSet struct {
+	ID          uuid.UUID         `json:"id"`
+	Version     int               `json:"version"`
+	Name        string            `json:"name"`
+	Status      PricingRuleStatus `json:"status"`
+	Rules       PricingRules      `json:"rules"`
+	CreatedBy   *uuid.UUID        `json:"created_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ActivatedAt *time.Time        `json:"activated_at,omitempty"`
+}
+
+// PricingInput describes what is being priced
+type PricingInput struct {
+	RequestType RequestType     `json:"request_type" binding:"required,oneof=website mobile_app both"`
+	Complexity  ComplexityLevel `json:"complexity" binding:"required,oneof=basic standard advanced"`
+	HostingType HostingType     `json:"hosting_type" binding:"required"`
+	AddOns      []string        `json:"add_ons"`
+}
+
+// QuoteLineItem is a single line of a price breakdown
+type QuoteLineItem struct {
+	Code   string `json:"code"`
+	Label  string `json:"label"`
+	Amount Money  `json:"amount"`
+}
+
+// PriceBreakdown is the itemized result of evaluating pricing rules
+type PriceBreakdown struct {
+	RuleVersion int             `json:"rule_version,omitempty"`
+	Currency    string          `json:"currency"`
+	Lines       []QuoteLineItem `json:"lines"`
+	Subtotal    Money           `json:"subtotal"` // rule lines only, before discounts and adjustments
+	Total       Money           `json:"total"`
+	Free        bool            `json:"free"` // covered by the free-website policy, with nothing else to pay
+}
+
+// CreatePricingRuleSetRequest is the input for drafting a new rule set (admin)
+type CreatePricingRuleSetRequest struct {
+	Name  string       `json:"name" binding:"required"`
+	Rules PricingRules `json:"rules" binding:"required"`
+}
+
+// PricingImpactItem compares one open request under the active and candidate rules
+type PricingImpactItem struct {
+	RequestID    uuid.UUID `json:"request_id"`
+	Title        string    `json:"title"`
+	CurrentTotal Money     `json:"current_total"`
+	NewTotal     Money     `json:"new_total"`
+	Delta        Money     `json:"delta"`
+}
+
+// PricingImpact summarises how a candidate rule set would reprice open requests
+type PricingImpact struct {
+	CurrentVersion int                 `json:"current_version,omitempty"`
+	NewVersion     int                 `json:"new_version"`
+	Requests       int                 `json:"requests"`
+	Increased      int                 `json:"increased"`
+	Decreased      int                 `json:"decreased"`
+	Unchanged      int                 `json:"unchanged"`
+	CurrentTotal   Money               `json:"current_total"`
+	NewTotal       Money               `json:"new_total"`
+	Items          []PricingImpactItem `json:"items"`
+}
+
+// DefaultPricingRules mirrors the launch price list and is used until an
+// admin activates a rule set
+func DefaultPricingRules() PricingRules {
+	return PricingRules{
+		Currency:     "INR",
+		FreeWebsites: true,
+		BasePrices: map[ComplexityLevel]Money{
+			ComplexityBasic:    Rupees(2999),
+			ComplexityStandard: Rupees(5999),
+			ComplexityAdvanced: Rupees(11999),
+		},
+		TypeMultipliers: map[RequestType]float64{
+			RequestTypeWebsite:   1.0,
+			RequestTypeMobileApp: 1.5,
+			RequestTypeBoth:      1.5,
+		},
+		HostingSurcharges: map[HostingType]Money{
+			HostingWhitelabel: Rupees(1999),
+		},
+	}
+}
+
+// Validate checks that the rules can price every request
+func (r PricingRules) Validate() error {
+	if r.Currency == "" {
+		return errors.New("currency is required")
+	}
+	for _, level := range []ComplexityLevel{ComplexityBasic, ComplexityStandard, ComplexityAdvanced} {
+		price, ok := r.BasePrices[level]
+		if !ok {
+			return fmt.Errorf("base price for %q is required", level)
+		}
+		if price.IsNegative() {
+			return fmt.Errorf("base price for %q cannot be negative", level)
+		}
+	}
+	for reqType, m := range r.TypeMultipliers {
+		if m <= 0 {
+			return fmt.Errorf("multiplier for %q must be positive", reqType)
+		}
+	}
+	for hosting, amount := range r.HostingSurcharges {
+		if amount.IsNegative() {
+			return fmt.Errorf("surcharge for %q cannot be negative", hosting)
+		}
+	}
+	seen := map[string]bool{}
+	for _, addOn := range r.AddOns {
+		if addOn.Code == "" {
+			return errors.New("add-on code is required")
+		}
+		if seen[addOn.Code] {
+			return fmt.Errorf("duplicate add-on code %q", addOn.Code)
+		}
+		if addOn.Amount.IsNegative() {
+			return fmt.Errorf("add-on %q cannot be negative", addOn.Code)
+		}
+		seen[addOn.Code] = true
+	}
+	return nil
+}
+
+// Evaluate prices the input and returns a line-item breakdown. Rule amounts
+// are denominated in the rule set's currency. Under FreeWebsites the build
+// itself is free, but add-ons are still charged.
+func (r PricingRules) Evaluate(in PricingInput) PriceBreakdown {
+	zero := NewMoney(0, r.Currency)
+	b := PriceBreakdown{Currency: r.Currency, Lines: []QuoteLineItem{}, Subtotal: zero, Total: zero}
+
+	free := r.FreeWebsites && in.RequestType == RequestTypeWebsite && in.HostingType != HostingWhitelabel
+	if free {
+		b.Lines = append(b.Lines, QuoteLineItem{Code: "free_website", Label: "Free website build", Amount: zero})
+	} else {
+		r.priceBuild(&b, in)
+	}
+
+	for _, code := range in.AddOns {
+		for _, addOn := range r.AddOns {
+			if addOn.Code == code && addOn.appliesTo(in.RequestType) {
+				b.Lines = append(b.Lines, QuoteLineItem{
+					Code:   "addon_" + addOn.Code,
+					Label:  addOn.Label,
+					Amount: addOn.Amount.In(r.Currency),
+				})
+			}
+		}
+	}
+
+	for _, line := range b.Lines {
+		b.Total = b.Total.Add(line.Amount)
+	}
+	b.Subtotal = b.Total
+	// A build priced at zero is not free unless the policy made it so
+	b.Free = free && b.Total.IsZero()
+	return b
+}
+
+// priceBuild adds the base price, request type premium and hosting
+// surcharge lines
+func (r PricingRules) priceBuild(b *PriceBreakdown, in PricingInput) {
+	base := r.BasePrices[in.Complexity].In(r.Currency)
+	b.Lines = append(b.Lines, QuoteLineItem{
+		Code:   "base",
+		Label:  fmt.Sprintf("Base price (%s)", in.Complexity),
+		Amount: base,
+	})
+
+	if m, ok := r.TypeMultipliers[in.RequestType]; ok && m != 1 {
+		b.Lines = append(b.Lines, QuoteLineItem{
+			Code:   "type_" + string(in.RequestType),
+			Label:  fmt.Sprintf("%s premium (×%g)", in.RequestType, m),
+			Amount: base.Mul(m).Sub(base),
+		})
+	}
+
+	if amount, ok := r.HostingSurcharges[in.HostingType]; ok && !amount.IsZero() {
+		b.Lines = append(b.Lines, QuoteLineItem{
+			Code:   "hosting_" + string(in.HostingType),
+			Label:  fmt.Sprintf("%s hosting", in.HostingType),
+			Amount: amount.In(r.Currency),
+		})
+	}
+}
+
+// ValidateInput reports add-ons the rules do not offer for the request type
+func (r PricingRules) ValidateInput(in PricingInput) error {
+	for _, code := range in.AddOns {
+		found := false
+		for _, addOn := range r.AddOns {
+			if addOn.Code == code && addOn.appliesTo(in.RequestType) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %q is not available for %s requests", ErrUnknownAddOn, code, in.RequestType)
+		}
+	}
+	return nil
+}
+
+// Quote evaluates the rule set and stamps the breakdown with its version
+func (s *PricingRuleSet) Quote(in PricingInput) PriceBreakdown {
+	b := s.Rules.Evaluate(in)
+	b.RuleVersion = s.Version
+	return b
+}
+
+func (a PricingAddOn) appliesTo(reqType RequestType) bool {
+	if len(a.AppliesTo) == 0 {
+		return true
+	}
+	for _, t := range a.AppliesTo {
+		if t == reqType {
+			return true
+		}
+	}
+	return false
+}
+
+// PricingRepository defines the interface for pricing rule data access
+type PricingRepository interface {
+	Create(ctx context.Context, set *PricingRuleSet) error
+	FindByVersion(ctx context.Context, version int) (*PricingRuleSet, error)
+	FindActive(ctx context.Context) (*PricingRuleSet, error)
+	List(ctx context.Context) ([]PricingRuleSet, error)
+	Activate(ctx context.Context, version int) error
+}
+
+// PricingService defines the interface for pricing business logic
+type PricingService interface {
+	ActiveRuleSet(ctx context.Context) (*PricingRuleSet, error)
+	Quote(ctx context.Context, in PricingInput) (*PriceBreakdown, error)
+	CreateDraft(ctx context.Context, createdBy uuid.UUID, req *CreatePricingRuleSetRequest) (*PricingRuleSet, error)
+	ListRuleSets(ctx context.Context) ([]PricingRuleSet, error)
+	GetRuleSet(ctx context.Context, version int) (*PricingRuleSet, error)
+	RequestBreakdown(ctx context.Context, req *BuildRequest) (*PriceBreakdown, error)
+	// Reprice prices a request under the active rule set, including its discounts
+	Reprice(ctx context.Context, req *BuildRequest) (*PriceBreakdown, error)
+	PreviewImpact(ctx context.Context, version int) (*PricingImpact, error)
+	Activate(ctx context.Context, version int) (*PricingRuleSet, error)
+}
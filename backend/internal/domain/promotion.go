@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiscountKind is how a discount is computed
+type DiscountKind string
+
+const (
+	DiscountPercent DiscountKind = "percent" // percentage of the rule subtotal
+	DiscountFixed   DiscountKind = "fixed"   // fixed amount off
+)
+
+// DiscountSource records how a discount came to be on a request
+type DiscountSource string
+
+const (
+	DiscountFromCode   DiscountSource = "code"   // redeemed by the student
+	DiscountFromWaiver DiscountSource = "waiver" // granted by staff (scholarships, partnerships)
+)
+
+// DiscountCode is a promotion students can redeem on their own requests
+type DiscountCode struct {
+	ID              uuid.UUID     `json:"id"`
+	Code            string        `json:"code"`
+	Description     string        `json:"description,omitempty"`
+	Kind            DiscountKind  `json:"kind"`
+	Percent         float64       `json:"percent,omitempty"`
+	Amount          Money         `json:"amount"`
+	MaxUses         *int          `json:"max_uses,omitempty"`          // across all students; nil is unlimited
+	PerStudentLimit *int          `json:"per_student_limit,omitempty"` // nil is unlimited
+	UsedCount       int           `json:"used_count"`
+	RequestTypes    []RequestType `json:"request_types,omitempty"` // empty means every type
+	StartsAt        *time.Time    `json:"starts_at,omitempty"`
+	ExpiresAt       *time.Time    `json:"expires_at,omitempty"`
+	Active          bool          `json:"active"`
+	CreatedBy       uuid.UUID     `json:"created_by"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// CheckRedeemable reports why the code cannot be used on a request of the
+// given type at time now, if it cannot
+func (c *DiscountCode) CheckRedeemable(reqType RequestType, now time.Time) error {
+	switch {
+	case !c.Active:
+		return fmt.Errorf("%w: code is no longer active", ErrDiscountNotApplicable)
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		return fmt.Errorf("%w: code is not valid yet", ErrDiscountNotApplicable)
+	case c.ExpiresAt != nil && !now.Before(*c.ExpiresAt):
+		return fmt.Errorf("%w: code has expired", ErrDiscountNotApplicable)
+	case c.MaxUses != nil && c.UsedCount >= *c.MaxUses:
+		return fmt.Errorf("%w: code has been fully redeemed", ErrDiscountNotApplicable)
+	}
+	if len(c.RequestTypes) > 0 {
+		for _, t := range c.RequestTypes {
+			if t == reqType {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: code does not apply to %s requests", ErrDiscountNotApplicable, reqType)
+	}
+	return nil
+}
+
+// RequestDiscount is a discount attached to a request, either a redeemed code
+// or a staff waiver. Its value is recomputed whenever the request is priced;
+// AppliedAmount is the value when it was attached, kept for reporting.
+type RequestDiscount struct {
+	ID            uuid.UUID      `json:"id"`
+	RequestID     uuid.UUID      `json:"request_id"`
+	UserID        uuid.UUID      `json:"user_id"` // the student the request belongs to
+	Source        DiscountSource `json:"source"`
+	CodeID        *uuid.UUID     `json:"code_id,omitempty"`
+	Code          string         `json:"code,omitempty"`
+	Label         string         `json:"label"`
+	Kind          DiscountKind   `json:"kind"`
+	Percent       float64        `json:"percent,omitempty"`
+	Amount        Money          `json:"amount"`
+	AppliedAmount Money          `json:"applied_amount"`
+	Reason        string         `json:"reason,omitempty"`
+	GrantedBy     *uuid.UUID     `json:"granted_by,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// DiscountLines prices discounts against a rule subtotal, in the order they
+// were attached. Each line is negative and the running total never drops
+// below zero, so stacked discounts cannot make a request pay out.
+func DiscountLines(subtotal Money, discounts []RequestDiscount) []QuoteLineItem {
+	lines := []QuoteLineItem{}
+	remaining := subtotal
+	for _, d := range discounts {
+		off := d.Amount.In(subtotal.Currency)
+		if d.Kind == DiscountPercent {
+			off = subtotal.Percent(d.Percent)
+		}
+		if off.Cmp(remaining) > 0 {
+			off = remaining
+		}
+		if !off.IsPositive() {
+			continue
+		}
+		remaining = remaining.Sub(off)
+
+		code := "waiver"
+		if d.Source == DiscountFromCode {
+			code = "discount_" + strings.ToLower(d.Code)
+		}
+		lines = append(lines, QuoteLineItem{Code: code, Label: d.Label, Amount: off.Neg()})
+	}
+	return lines
+}
+
+// WaivesInFull reports whether the discounts explicitly wipe out the fee: a
+// 100% code or waiver, or any staff waiver. Only then does a request that
+// owes nothing count as paid.
+func WaivesInFull(discounts []RequestDiscount) bool {
+	for _, d := range discounts {
+		if d.Source == DiscountFromWaiver || (d.Kind == DiscountPercent && d.Percent >= 100) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateDiscountCodeRequest is the input for creating a discount code (admin)
+type CreateDiscountCodeRequest struct {
+	Code            string        `json:"code" binding:"required,max=40"`
+	Description     string        `json:"description"`
+	Kind            DiscountKind  `json:"kind" binding:"required,oneof=percent fixed"`
+	Percent         float64       `json:"percent"`
+	Amount          Money         `json:"amount"`
+	MaxUses         *int          `json:"max_uses" binding:"omitempty,gt=0"`
+	PerStudentLimit *int          `json:"per_student_limit" binding:"omitempty,gt=0"`
+	RequestTypes    []RequestType `json:"request_types" binding:"omitempty,dive,oneof=website mobile_app both"`
+	StartsAt        *time.Time    `json:"starts_at"`
+	ExpiresAt       *time.Time    `json:"expires_at"`
+}
+
+// UpdateDiscountCodeRequest changes the limits of an existing code (admin).
+// The discount itself is fixed once created so past redemptions stay honest.
+type UpdateDiscountCodeRequest struct {
+	Active          *bool      `json:"active"`
+	MaxUses         *int       `json:"max_uses" binding:"omitempty,gt=0"`
+	PerStudentLimit *int       `json:"per_student_limit" binding:"omitempty,gt=0"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+}
+
+// RedeemCodeRequest is the student's input for applying a code
+type RedeemCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GrantWaiverRequest is the staff input for waiving part or all of a fee.
+// Percent 100 waives the fee entirely.
+type GrantWaiverRequest struct {
+	Kind    DiscountKind `json:"kind" binding:"required,oneof=percent fixed"`
+	Percent float64      `json:"percent"`
+	Amount  Money        `json:"amount"`
+	Label   string       `json:"label"`
+	Reason  string       `json:"reason" binding:"required"`
+}
+
+// PromotionReportItem totals the discounts given by one code, or by waivers
+type PromotionReportItem struct {
+	Source      DiscountSource `json:"source"`
+	Code        string         `json:"code,omitempty"`
+	Redemptions int            `json:"redemptions"`
+	Requests    int            `json:"requests"`
+	Discounted  Money          `json:"discounted"`
+}
+
+// PromotionReport summarises discounts given in a period
+type PromotionReport struct {
+	From       *time.Time            `json:"from,omitempty"`
+	To         *time.Time            `json:"to,omitempty"`
+	Currency   string                `json:"currency"`
+	Discounted Money                 `json:"discounted"`
+	Items      []PromotionReportItem `json:"items"`
+}
+
+// PromotionRepository defines the interface for promotion data access
+type PromotionRepository interface {
+	CreateCode(ctx context.Context, code *DiscountCode) error
+	FindCodeByID(ctx context.Context, id uuid.UUID) (*DiscountCode, error)
+	FindCodeByCode(ctx context.Context, code string) (*DiscountCode, error)
+	ListCodes(ctx context.Context) ([]DiscountCode, error)
+	UpdateCode(ctx context.Context, code *DiscountCode) error
+	// Redeem attaches a code discount and counts the use atomically. It
+	// enforces the code's total and per-student limits under a row lock and
+	// returns ErrDiscountNotApplicable when either is exhausted.
+	Redeem(ctx context.Context, d *RequestDiscount) error
+	AddWaiver(ctx context.Context, d *RequestDiscount) error
+	FindDiscount(ctx context.Context, id uuid.UUID) (*RequestDiscount, error)
+	// RemoveDiscount detaches a discount, giving back a code use
+	RemoveDiscount(ctx context.Context, d *RequestDiscount) error
+	ListByRequest(ctx context.Context, requestID uuid.UUID) ([]RequestDiscount, error)
+	Report(ctx context.Context, from, to *time.Time) ([]PromotionReportItem, error)
+}
+
+// PromotionService defines the interface for promotion business logic
+type PromotionService interface {
+	CreateCode(ctx context.Context, createdBy uuid.UUID, req *CreateDiscountCodeRequest) (*DiscountCode, error)
+	ListCodes(ctx context.Context) ([]DiscountCode, error)
+	UpdateCode(ctx context.Context, id uuid.UUID, req *UpdateDiscountCodeRequest) (*DiscountCode, error)
+	Redeem(ctx context.Context, userID, requestID uuid.UUID, code string) (*RequestDiscount, error)
+	GrantWaiver(ctx context.Context, grantedBy, requestID uuid.UUID, req *GrantWaiverRequest) (*RequestDiscount, error)
+	RemoveDiscount(ctx context.Context, requestID, discountID uuid.UUID) error
+	ListForRequest(ctx context.Context, requestID uuid.UUID) ([]RequestDiscount, error)
+	Report(ctx context.Context, from, to *time.Time) (*PromotionReport, error)
+}
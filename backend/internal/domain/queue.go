@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComplexityHours is the build time a queued request is assumed to need when
+// estimating which weekend it lands on
+var ComplexityHours = map[ComplexityLevel]int{
+	ComplexityBasic:    2,
+	ComplexityStandard: 4,
+	ComplexityAdvanced: 8,
+}
+
+// EstimatedHours returns the hours a request of the given complexity is
+// assumed to need, counting an unset complexity as basic
+func EstimatedHours(c ComplexityLevel) int {
+	if hours := ComplexityHours[c]; hours > 0 {
+		return hours
+	}
+	return ComplexityHours[ComplexityBasic]
+}
+
+// ActiveBuildStatuses are the statuses in which a request is taking up a
+// builder's time
+var ActiveBuildStatuses = []RequestStatus{StatusScheduled, StatusBuilding, StatusReview, StatusDeploying}
+
+// QueuePolicy decides the order queued requests are built in. The zero value
+// is plain first in, first out.
+type QueuePolicy struct {
+	// OnePerStudent puts a student's second queued request behind everyone
+	// else's first, and holds all of theirs back while one is being built
+	OnePerStudent bool
+	// FinalYearBoost ranks final-year students as if they had queued this
+	// much earlier
+	FinalYearBoost time.Duration
+}
+
+// QueueEntry is a queued request and what the policy needs to rank it
+type QueueEntry struct {
+	RequestID      uuid.UUID       `json:"request_id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	BuilderID      *uuid.UUID      `json:"builder_id,omitempty"`
+	Title          string          `json:"title"`
+	Complexity     ComplexityLevel `json:"complexity"`
+	QueuedAt       time.Time       `json:"queued_at"`
+	GraduationYear *int            `json:"-"`
+	ActiveBuilds   int             `json:"active_builds"` // the student's requests in ActiveBuildStatuses
+
+	// Set by RankQueue
+	Position  int  `json:"position"`   // 1-based
+	FinalYear bool `json:"final_year"` // got the final-year boost
+	Deferred  bool `json:"deferred"`   // held back by OnePerStudent
+	tier      int  // OnePerStudent round the entry is built in
+}
+
+// IsFinalYear reports whether a student graduating in graduationYear is in
+// their final year. Academic years run from July to June.
+func IsFinalYear(graduationYear int, now time.Time) bool {
+	endsIn := now.Year()
+	if now.Month() >= time.July {
+		endsIn++
+	}
+	return graduationYear <= endsIn
+}
+
+// RankQueue orders queued requests under the policy and numbers them from 1
+func RankQueue(entries []QueueEntry, policy QueuePolicy, now time.Time) []QueueEntry {
+	ranked := make([]QueueEntry, len(entries))
+	copy(ranked, entries)
+
+	effective := func(e *QueueEntry) time.Time {
+		if e.FinalYear {
+			return e.QueuedAt.Add(-policy.FinalYearBoost)
+		}
+		return e.QueuedAt
+	}
+	for i := range ranked {
+		e := &ranked[i]
+		e.FinalYear = policy.FinalYearBoost > 0 && e.GraduationYear != nil && IsFinalYear(*e.GraduationYear, now)
+	}
+	byTime := func(a, b *QueueEntry) bool {
+		ta, tb := effective(a), effective(b)
+		if !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		return a.RequestID.String() < b.RequestID.String()
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return byTime(&ranked[i], &ranked[j]) })
+
+	if policy.OnePerStudent {
+		// Each student gets one request per round; a student with a build in
+		// progress sits out the first round
+		seen := map[uuid.UUID]int{}
+		for i := range ranked {
+			e := &ranked[i]
+			e.tier = seen[e.UserID]
+			if e.ActiveBuilds > 0 {
+				e.tier++
+			}
+			e.Deferred = e.tier > 0
+			seen[e.UserID]++
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if ranked[i].tier != ranked[j].tier {
+				return ranked[i].tier < ranked[j].tier
+			}
+			return byTime(&ranked[i], &ranked[j])
+		})
+	}
+
+	for i := range ranked {
+		ranked[i].Position = i + 1
+	}
+	return ranked
+}
+
+// EstimateWeekends assigns ranked entries to the first upcoming slot with
+// enough hours and project capacity left, and returns the Saturday of the
+// weekend each request is expected to be built on. Requests that do not fit
+// in the known slots are missing from the result.
+func EstimateWeekends(ranked []QueueEntry, slots []WeekendSlot) map[uuid.UUID]time.Time {
+	type capacity struct {
+		date     time.Time
+		hours    int
+		projects int
+	}
+	free := make([]capacity, 0, len(slots))
+	for _, s := range slots {
+		if s.Status == SlotFull {
+			continue
+		}
+		free = append(free, capacity{
+			date:     s.Date,
+			hours:    s.TotalHours - s.BookedHours,
+			projects: s.MaxProjects - s.BookedProjects,
+		})
+	}
+	sort.SliceStable(free, func(i, j int) bool { return free[i].date.Before(free[j].date) })
+
+	estimates := map[uuid.UUID]time.Time{}
+	for _, e := range ranked {
+		hours := EstimatedHours(e.Complexity)
+		for i := range free {
+			if free[i].projects > 0 && free[i].hours >= hours {
+				free[i].projects--
+				free[i].hours -= hours
+				estimates[e.RequestID] = weekendSaturday(free[i].date)
+				break
+			}
+		}
+	}
+	return estimates
+}
+
+// weekendSaturday returns the Saturday of the weekend a slot date falls on
+func weekendSaturday(date time.Time) time.Time {
+	if date.Weekday() == time.Sunday {
+		return date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// QueuePosition is where a queued request stands and when it is likely built
+type QueuePosition struct {
+	RequestID        uuid.UUID  `json:"request_id"`
+	Position         int        `json:"position"` // 1 is next
+	QueueLength      int        `json:"queue_length"`
+	EstimatedHours   int        `json:"estimated_hours"`
+	EstimatedWeekend *time.Time `json:"estimated_weekend,omitempty"` // absent when no upcoming slot has room
+	FinalYear        bool       `json:"final_year"`
+	Deferred         bool       `json:"deferred"`
+}
+
+// SetGraduationYearRequest records when a student graduates, for the
+// final-year boost. A null year clears it.
+type SetGraduationYearRequest struct {
+	GraduationYear *int `json:"graduation_year" binding:"omitempty,min=2000,max=2100"`
+}
+
+// QueueRepository defines the interface for build queue data access
+type QueueRepository interface {
+	// ListQueued returns every queued request, with QueuedAt the time it
+	// entered the queue
+	ListQueued(ctx context.Context) ([]QueueEntry, error)
+	// SetGraduationYear returns ErrUserNotFound for unknown users
+	SetGraduationYear(ctx context.Context, userID uuid.UUID, year *int) error
+}
+
+// QueueService defines the interface for the build queue
+type QueueService interface {
+	// List returns the queue in build order
+	List(ctx context.Context) ([]QueueEntry, error)
+	// Position returns ErrNotQueued unless the request is queued
+	Position(ctx context.Context, requestID uuid.UUID) (*QueuePosition, error)
+	SetGraduationYear(ctx context.Context, userID uuid.UUID, year *int) error
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuoteStatus tracks a quote from draft to the student's decision
+type QuoteStatus string
+
+const (
+	QuoteDraft      QuoteStatus = "draft"
+	QuoteSent       QuoteStatus = "sent"
+	QuoteAccepted   QuoteStatus = "accepted"
+	QuoteDeclined   QuoteStatus = "declined"
+	QuoteSuperseded QuoteStatus = "superseded" // replaced by a newer revision
+	QuoteExpired    QuoteStatus = "expired"
+)
+
+// Quote is a builder's priced offer for a paid request. Each revision is kept
+// as its own row so the negotiation history survives.
+type Quote struct {
+	ID             uuid.UUID       `json:"id"`
+	RequestID      uuid.UUID       `json:"request_id"`
+	Revision       int             `json:"revision"`
+	Status         QuoteStatus     `json:"status"`
+	Currency       string          `json:"currency"`
+	Lines          []QuoteLineItem `json:"lines"`
+	Total          Money           `json:"total"`
+	ValidUntil     time.Time       `json:"valid_until"`
+	Notes          string          `json:"notes,omitempty"`
+	PricingVersion *int            `json:"pricing_version,omitempty"`
+	CreatedBy      uuid.UUID       `json:"created_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+	SentAt         *time.Time      `json:"sent_at,omitempty"`
+	RespondedAt    *time.Time      `json:"responded_at,omitempty"`
+	ResponseNote   string          `json:"response_note,omitempty"`
+}
+
+// IsOpen reports whether the student can still act on the quote
+func (q *Quote) IsOpen() bool {
+	return q.Status == QuoteDraft || q.Status == QuoteSent
+}
+
+// CreateQuoteRequest is the input for drafting a quote (builder/admin).
+// When Lines is empty the request's current pricing breakdown is used.
+type CreateQuoteRequest struct {
+	Lines      []QuoteLineItem `json:"lines"`
+	ValidUntil time.Time       `json:"valid_until" binding:"required"`
+	Notes      string          `json:"notes"`
+	Send       bool            `json:"send"` // send to the student straight away
+}
+
+// RespondQuoteRequest is the student's accept/decline input
+type RespondQuoteRequest struct {
+	Note string `json:"note"`
+}
+
+// QuoteRepository defines the interface for quote data access
+type QuoteRepository interface {
+	Create(ctx context.Context, quote *Quote) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Quote, error)
+	ListByRequest(ctx context.Context, requestID uuid.UUID) ([]Quote, error)
+	FindAccepted(ctx context.Context, requestID uuid.UUID) (*Quote, error)
+	NextRevision(ctx context.Context, requestID uuid.UUID) (int, error)
+	UpdateStatus(ctx context.Context, quote *Quote) error
+	MarkAccepted(ctx context.Context, quote *Quote) error
+	SupersedeOpen(ctx context.Context, requestID uuid.UUID, exceptID uuid.UUID) error
+}
+
+// QuoteService defines the interface for the quote-and-approve workflow
+type QuoteService interface {
+	TransitionGuard
+
+	Create(ctx context.Context, builderID, requestID uuid.UUID, req *CreateQuoteRequest) (*Quote, error)
+	Send(ctx context.Context, quoteID uuid.UUID) (*Quote, error)
+	ListForRequest(ctx context.Context, requestID uuid.UUID, includeDrafts bool) ([]Quote, error)
+	Accept(ctx context.Context, studentID, requestID, quoteID uuid.UUID, req *RespondQuoteRequest) (*Quote, error)
+	Decline(ctx context.Context, studentID, requestID, quoteID uuid.UUID, req *RespondQuoteRequest) (*Quote, error)
+}
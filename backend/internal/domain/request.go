@@ -16,6 +16,15 @@ const (
 	RequestTypeBoth      RequestType = "both"
 )
 
+// IsValid reports whether the request type is known
+func (t RequestType) IsValid() bool {
+	switch t {
+	case RequestTypeWebsite, RequestTypeMobileApp, RequestTypeBoth:
+		return true
+	}
+	return false
+}
+
 // RequestStatus tracks the lifecycle of a build request
 type RequestStatus string
 
@@ -31,6 +40,21 @@ const (
 	StatusRejected   RequestStatus = "rejected"
 )
 
+// IsValid reports whether the status is known
+func (s RequestStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusQueued, StatusScheduled, StatusBuilding, StatusReview,
+		StatusDeploying, StatusCompleted, StatusCancelled, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// IsClosed reports whether the request has finished its lifecycle
+func (s RequestStatus) IsClosed() bool {
+	return s == StatusCompleted || s == StatusCancelled || s == StatusRejected
+}
+
 // HostingType defines where the project will be deployed
 type HostingType string
 
@@ -41,6 +65,15 @@ const (
 	HostingWhitelabel  HostingType = "whitelabel"
 )
 
+// IsValid reports whether the hosting type is known
+func (h HostingType) IsValid() bool {
+	switch h {
+	case HostingFreeVercel, HostingFreeReplit, HostingFreeHeroku, HostingWhitelabel:
+		return true
+	}
+	return false
+}
+
 // ComplexityLevel determines pricing for paid services
 type ComplexityLevel string
 
@@ -50,6 +83,15 @@ const (
 	ComplexityAdvanced ComplexityLevel = "advanced"
 )
 
+// IsValid reports whether the complexity level is known
+func (c ComplexityLevel) IsValid() bool {
+	switch c {
+	case ComplexityBasic, ComplexityStandard, ComplexityAdvanced:
+		return true
+	}
+	return false
+}
+
 // BuildRequest represents a student's project request
 type BuildRequest struct {
 	ID              uuid.UUID       `json:"id"`
@@ -62,9 +104,10 @@ type BuildRequest struct {
 	HostingType     HostingType     `json:"hosting_type"`
 	
 	// Whitelabel-specific fields
-	WhitelabelDomain   string `json:"whitelabel_domain,omitempty"`
-	WhitelabelBranding string `json:"whitelabel_branding,omitempty"`
-	WhitelabelHosting  string `json:"whitelabel_hosting_platform,omitempty"`
+	WhitelabelDomain   string       `json:"whitelabel_domain,omitempty"`
+	WhitelabelBranding string       `json:"whitelabel_branding,omitempty"`
+	WhitelabelHosting  string       `json:"whitelabel_hosting_platform,omitempty"`
+	DomainStatus       DomainStatus `json:"domain_status,omitempty"` // DNS ownership check of WhitelabelDomain
 	
 	// Technical details
 	TechRequirements string `json:"tech_requirements,omitempty"`
@@ -75,8 +118,14 @@ type BuildRequest struct {
 	HostingEmail     string `json:"hosting_email,omitempty"`
 	
 	// Pricing
-	EstimatedCost    float64 `json:"estimated_cost"`
-	IsFree           bool    `json:"is_free"`
+	EstimatedCost    Money    `json:"estimated_cost"`
+	Currency         string   `json:"currency"`
+	IsFree           bool     `json:"is_free"`
+	AddOns           []string `json:"add_ons,omitempty"`
+	PricingVersion   *int     `json:"pricing_version,omitempty"` // rule set the estimate was computed with
+	
+	// Staff-only tags for grouping requests (e.g. "hackathon")
+	Labels           []string `json:"labels,omitempty"`
 	
 	// Delivery
 	DeliveryURL      string    `json:"delivery_url,omitempty"`
@@ -86,10 +135,16 @@ type BuildRequest struct {
 	ScheduledWeekend time.Time `json:"scheduled_weekend,omitempty"`
 	BuilderID        *uuid.UUID `json:"builder_id,omitempty"`
 	
+	// Bumped on every write, and exposed as the ETag
+	Version          int       `json:"version"`
+	
 	// Timestamps
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+
+	// Set on creation only, never stored: earlier requests this one resembles
+	PossibleDuplicates []DuplicateMatch `json:"possible_duplicates,omitempty"`
 }
 
 // CreateBuildRequest is the input for creating a new request
@@ -105,26 +160,90 @@ type CreateBuildRequest struct {
 	WhitelabelDomain  string      `json:"whitelabel_domain"`
 	WhitelabelBranding string     `json:"whitelabel_branding"`
 	WhitelabelHosting string      `json:"whitelabel_hosting_platform"`
+	AddOns            []string    `json:"add_ons"`
 }
 
 // UpdateBuildRequest is the input for updating a request (admin)
 type UpdateBuildRequest struct {
 	Status          *RequestStatus  `json:"status"`
 	Complexity      *ComplexityLevel `json:"complexity"`
-	EstimatedCost   *float64        `json:"estimated_cost"`
+	EstimatedCost   *Money          `json:"estimated_cost"`
 	ScheduledWeekend *time.Time     `json:"scheduled_weekend"`
 	BuilderID       *uuid.UUID      `json:"builder_id"`
 	DeliveryURL     *string         `json:"delivery_url"`
 	RepoURL         *string         `json:"repo_url"`
+	AddOns          []string        `json:"add_ons"` // nil leaves add-ons unchanged
+
+	// IfVersion, from the If-Match header, rejects the update with
+	// ErrVersionConflict unless the request is still at that version
+	IfVersion *int `json:"-"`
+}
+
+// RequestSortField is a column that request listings can be ordered by
+type RequestSortField string
+
+const (
+	SortByCreatedAt        RequestSortField = "created_at"
+	SortByUpdatedAt        RequestSortField = "updated_at"
+	SortByScheduledWeekend RequestSortField = "scheduled_weekend"
+	SortByEstimatedCost    RequestSortField = "estimated_cost"
+	SortByTitle            RequestSortField = "title"
+	SortByStatus           RequestSortField = "status"
+	SortByRelevance        RequestSortField = "relevance" // only meaningful with a search query
+)
+
+// IsValid reports whether the sort field is supported
+func (f RequestSortField) IsValid() bool {
+	switch f {
+	case SortByCreatedAt, SortByUpdatedAt, SortByScheduledWeekend,
+		SortByEstimatedCost, SortByTitle, SortByStatus, SortByRelevance:
+		return true
+	}
+	return false
 }
 
 // RequestFilter for listing/searching requests
 type RequestFilter struct {
 	UserID      *uuid.UUID
 	Status      *RequestStatus
+	Statuses    []RequestStatus // matches any of the given statuses
 	RequestType *RequestType
-	Limit       int
-	Offset      int
+	BuilderID   *uuid.UUID
+	HostingType *HostingType
+	Complexity  *ComplexityLevel
+	IsFree      *bool
+	Label       string // requests carrying this label
+
+	// Free-text search over title, description and tech requirements
+	Query string
+
+	// Date ranges (inclusive from, exclusive to)
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	ScheduledFrom *time.Time
+	ScheduledTo   *time.Time
+
+	// Requests that entered their current status before this time
+	StatusChangedBefore *time.Time
+
+	// Ordering — defaults to relevance when searching, created_at otherwise
+	SortBy   RequestSortField
+	SortDesc bool
+
+	// Pagination: After selects keyset mode, otherwise Offset is used (deprecated)
+	Limit        int
+	Offset       int
+	After        *Cursor
+	IncludeTotal bool // total is 0 unless requested
+}
+
+// SupportsCursor reports whether the filter's ordering is (created_at, id),
+// which keyset pagination requires
+func (f RequestFilter) SupportsCursor() bool {
+	if f.SortBy == "" {
+		return f.Query == ""
+	}
+	return f.SortBy == SortByCreatedAt
 }
 
 // IsPaidRequest checks if a request type is typically charged (pricing discussed offline with builder)
@@ -137,41 +256,40 @@ func (r *BuildRequest) IsPaidRequest() bool {
 	return true
 }
 
-// CalculateCost estimates the cost based on type and complexity
-func CalculateCost(reqType RequestType, complexity ComplexityLevel, hosting HostingType) float64 {
-	if reqType == RequestTypeWebsite && hosting != HostingWhitelabel {
-		return 0.0
-	}
-
-	baseCost := map[ComplexityLevel]float64{
-		ComplexityBasic:    2999.0,  // ₹2,999
-		ComplexityStandard: 5999.0,  // ₹5,999
-		ComplexityAdvanced: 11999.0, // ₹11,999
-	}
-
-	cost := baseCost[complexity]
-
-	// Mobile app premium
-	if reqType == RequestTypeMobileApp || reqType == RequestTypeBoth {
-		cost *= 1.5
-	}
+// CalculateCost estimates the cost based on type and complexity using the
+// default price list. Services price requests through PricingService, which
+// honours the active rule set.
+func CalculateCost(reqType RequestType, complexity ComplexityLevel, hosting HostingType) Money {
+	return DefaultPricingRules().Evaluate(PricingInput{
+		RequestType: reqType,
+		Complexity:  complexity,
+		HostingType: hosting,
+	}).Total
+}
 
-	// Whitelabel premium
-	if hosting == HostingWhitelabel {
-		cost += 1999.0
+// PricingInput returns the inputs used to price this request
+func (r *BuildRequest) PricingInput() PricingInput {
+	return PricingInput{
+		RequestType: r.RequestType,
+		Complexity:  r.Complexity,
+		HostingType: r.HostingType,
+		AddOns:      r.AddOns,
 	}
-
-	return cost
 }
 
 // BuildRequestRepository defines the interface for request data access
 type BuildRequestRepository interface {
 	Create(ctx context.Context, req *BuildRequest) error
 	FindByID(ctx context.Context, id uuid.UUID) (*BuildRequest, error)
+	// LockByID reads the request with a row lock held until the transaction
+	// ends; call it inside Transactor.WithinTx
+	LockByID(ctx context.Context, id uuid.UUID) (*BuildRequest, error)
 	Update(ctx context.Context, req *BuildRequest) error
 	List(ctx context.Context, filter RequestFilter) ([]BuildRequest, int, error)
 	CountByStatus(ctx context.Context, status RequestStatus) (int, error)
 	GetWeekendRequests(ctx context.Context, weekendStart time.Time) ([]BuildRequest, error)
+	// UpdateMany saves several requests in one transaction: all or none
+	UpdateMany(ctx context.Context, reqs []*BuildRequest) error
 }
 
 // BuildRequestService defines the interface for request business logic
@@ -179,6 +297,7 @@ type BuildRequestService interface {
 	Create(ctx context.Context, userID uuid.UUID, req *CreateBuildRequest) (*BuildRequest, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*BuildRequest, error)
 	Update(ctx context.Context, id uuid.UUID, req *UpdateBuildRequest) (*BuildRequest, error)
-	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]BuildRequest, int, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, filter RequestFilter) ([]BuildRequest, int, error)
 	ListAll(ctx context.Context, filter RequestFilter) ([]BuildRequest, int, error)
+	BulkUpdate(ctx context.Context, req *BulkUpdateRequest) (*BulkResult, error)
 }
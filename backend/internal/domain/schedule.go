@@ -21,14 +21,52 @@ type WeekendSlot struct {
 	ID            uuid.UUID  `json:"id"`
 	Date          time.Time  `json:"date"`
 	DayOfWeek     string     `json:"day_of_week"` // "saturday" or "sunday"
-	TotalHours    int        `json:"total_hours"`  // 8
+	TotalHours    int        `json:"total_hours"`  // the builders' declared hours
 	BookedHours   int        `json:"booked_hours"`
 	MaxProjects   int        `json:"max_projects"`
 	BookedProjects int       `json:"booked_projects"`
 	Status        SlotStatus `json:"status"`
+	Version       int        `json:"version"` // bumped on every booking change
 	CreatedAt     time.Time  `json:"created_at"`
 }
 
+// Fits reports whether the slot can take one more project of the given hours
+func (s *WeekendSlot) Fits(hours int) bool {
+	return s.BookedProjects < s.MaxProjects && s.BookedHours+hours <= s.TotalHours
+}
+
+// Book takes hours and a project place, updating the status
+func (s *WeekendSlot) Book(hours int) {
+	s.BookedHours += hours
+	s.BookedProjects++
+	s.updateStatus()
+}
+
+// SetCapacity changes the slot's hours. Bookings beyond a reduced capacity
+// are kept; the slot is full until they are released.
+func (s *WeekendSlot) SetCapacity(hours int) {
+	s.TotalHours = hours
+	s.updateStatus()
+}
+
+// Release gives back what Book took
+func (s *WeekendSlot) Release(hours int) {
+	s.BookedHours = max(s.BookedHours-hours, 0)
+	s.BookedProjects = max(s.BookedProjects-1, 0)
+	s.updateStatus()
+}
+
+func (s *WeekendSlot) updateStatus() {
+	switch {
+	case s.BookedProjects >= s.MaxProjects || s.BookedHours >= s.TotalHours:
+		s.Status = SlotFull
+	case s.BookedProjects > 0:
+		s.Status = SlotBooked
+	default:
+		s.Status = SlotAvailable
+	}
+}
+
 // ScheduleEntry links a build request to a weekend slot
 type ScheduleEntry struct {
 	ID          uuid.UUID     `json:"id"`
@@ -57,17 +95,35 @@ type CreateSlotRequest struct {
 	MaxProjects int       `json:"max_projects" binding:"required,min=1"`
 }
 
+// CreateEntryRequest books a request into a slot (admin)
+type CreateEntryRequest struct {
+	RequestID uuid.UUID `json:"request_id" binding:"required"`
+	SlotID    uuid.UUID `json:"slot_id" binding:"required"`
+	Hours     int       `json:"estimated_hours" binding:"required,min=1,max=8"`
+}
+
+// MoveEntryRequest moves a booking to another slot; omit estimated_hours to
+// keep the booked hours
+type MoveEntryRequest struct {
+	SlotID uuid.UUID `json:"slot_id" binding:"required"`
+	Hours  int       `json:"estimated_hours" binding:"omitempty,min=1,max=8"`
+}
+
 // ScheduleRepository defines the interface for schedule data access
 type ScheduleRepository interface {
 	CreateSlot(ctx context.Context, slot *WeekendSlot) error
 	FindSlotByDate(ctx context.Context, date time.Time) (*WeekendSlot, error)
 	FindSlotByID(ctx context.Context, id uuid.UUID) (*WeekendSlot, error)
+	// LockSlot reads the slot with a row lock held until the transaction
+	// ends; call it inside Transactor.WithinTx
+	LockSlot(ctx context.Context, id uuid.UUID) (*WeekendSlot, error)
 	UpdateSlot(ctx context.Context, slot *WeekendSlot) error
 	ListUpcomingSlots(ctx context.Context, limit int) ([]WeekendSlot, error)
 	
 	CreateEntry(ctx context.Context, entry *ScheduleEntry) error
 	FindEntriesBySlot(ctx context.Context, slotID uuid.UUID) ([]ScheduleEntry, error)
 	FindEntriesByRequest(ctx context.Context, requestID uuid.UUID) ([]ScheduleEntry, error)
+	FindEntryByID(ctx context.Context, id uuid.UUID) (*ScheduleEntry, error)
 	UpdateEntry(ctx context.Context, entry *ScheduleEntry) error
 }
 
@@ -75,8 +131,34 @@ type ScheduleRepository interface {
 type ScheduleService interface {
 	GetUpcomingSlots(ctx context.Context) ([]WeekendSlot, error)
 	GetScheduleForWeekend(ctx context.Context, date time.Time) (*ScheduleView, error)
+	// ScheduleRequest books a request into a slot, moving it out of any
+	// slot it was booked into before
 	ScheduleRequest(ctx context.Context, requestID uuid.UUID, slotID uuid.UUID, hours int) (*ScheduleEntry, error)
+	// Reschedule moves a booking to another slot, or changes its hours when
+	// the slot is the same; hours of 0 keeps the booked hours
+	Reschedule(ctx context.Context, entryID uuid.UUID, slotID uuid.UUID, hours int) (*ScheduleEntry, error)
+	// Unschedule releases a booking and puts the request back in the queue
+	Unschedule(ctx context.Context, entryID uuid.UUID) (*ScheduleEntry, error)
 	AutoGenerateWeekendSlots(ctx context.Context, weeksAhead int) error
+	// RefreshCapacity sets slot hours from builder availability, for the
+	// slots on the given dates or every upcoming slot when none are given
+	RefreshCapacity(ctx context.Context, dates ...time.Time) error
+	// BuilderHoursLeft returns each builder's declared hours in the slot
+	// less what they are booked for; nil when nobody declared the day
+	BuilderHoursLeft(ctx context.Context, slot *WeekendSlot) (BuilderHours, error)
+	// AssignBuilder moves the request's bookings to the builder, refusing
+	// one who has no hours left for them. Call it in the transaction that
+	// saves the request's new builder.
+	AssignBuilder(ctx context.Context, req *BuildRequest, builderID *uuid.UUID) error
+
+	// Standby waitlists a request for a full slot, or for the next available
+	// one. It is promoted straight away if there is room.
+	Standby(ctx context.Context, req *CreateStandbyRequest) (*StandbyEntry, error)
+	ListStandby(ctx context.Context, slotID *uuid.UUID) ([]StandbyEntry, error)
+	CancelStandby(ctx context.Context, id uuid.UUID) (*StandbyEntry, error)
+	// AfterTransition frees a request's slot when it leaves the schedule
+	// (e.g. cancelled), promoting the next standby
+	TransitionObserver
 }
 
 // NextWeekendSaturday returns the next Saturday date
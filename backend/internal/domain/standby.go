@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StandbyStatus tracks a request on a slot's waitlist
+type StandbyStatus string
+
+const (
+	StandbyWaiting   StandbyStatus = "waiting"
+	StandbyPromoted  StandbyStatus = "promoted"  // booked into a slot
+	StandbyCancelled StandbyStatus = "cancelled" // withdrawn, or the request was closed
+)
+
+// StandbyEntry puts a request on the waitlist of one slot, or of whichever
+// slot frees up first when SlotID is nil. Waiting entries are promoted in the
+// order they were created.
+type StandbyEntry struct {
+	ID             uuid.UUID     `json:"id"`
+	RequestID      uuid.UUID     `json:"request_id"`
+	SlotID         *uuid.UUID    `json:"slot_id,omitempty"` // nil: next available
+	Hours          int           `json:"estimated_hours"`
+	Status         StandbyStatus `json:"status"`
+	PromotedSlotID *uuid.UUID    `json:"promoted_slot_id,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	PromotedAt     *time.Time    `json:"promoted_at,omitempty"`
+}
+
+// CreateStandbyRequest puts a request on standby; omit slot_id for the next
+// available slot
+type CreateStandbyRequest struct {
+	RequestID uuid.UUID  `json:"request_id" binding:"required"`
+	SlotID    *uuid.UUID `json:"slot_id"`
+	Hours     int        `json:"estimated_hours" binding:"required,min=1,max=8"`
+}
+
+// StandbyRepository defines the interface for waitlist data access
+type StandbyRepository interface {
+	Create(ctx context.Context, entry *StandbyEntry) error
+	FindByID(ctx context.Context, id uuid.UUID) (*StandbyEntry, error)
+	// FindWaitingByRequest returns the request's waiting entry, nil if none
+	FindWaitingByRequest(ctx context.Context, requestID uuid.UUID) (*StandbyEntry, error)
+	// ListWaiting returns waiting entries, oldest first. With a slot, only
+	// entries for that slot or for the next available one.
+	ListWaiting(ctx context.Context, slotID *uuid.UUID) ([]StandbyEntry, error)
+	Update(ctx context.Context, entry *StandbyEntry) error
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Transactor runs a unit of work in one database transaction. Repositories
+// called with the context handed to fn take part in the transaction; fn
+// returning an error rolls everything back. Nested calls join the outer
+// transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit runs fn once the transaction carried by ctx commits, or
+	// straight away outside one. It is dropped if the transaction rolls back.
+	AfterCommit(ctx context.Context, fn func(ctx context.Context))
+}
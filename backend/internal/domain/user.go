@@ -57,16 +57,27 @@ type AuthResponse struct {
 	User         User   `json:"user"`
 }
 
+// UserFilter for listing users, newest first
+type UserFilter struct {
+	Limit        int
+	Offset       int     // deprecated, used only when After is nil
+	After        *Cursor // keyset position on (created_at, id)
+	IncludeTotal bool    // total is 0 unless requested
+}
+
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	// LockByID reads the user with a row lock held until the transaction
+	// ends; call it inside Transactor.WithinTx
+	LockByID(ctx context.Context, id uuid.UUID) (*User, error)
 	Update(ctx context.Context, user *User) error
 	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
 	SetOTP(ctx context.Context, email, otp string, expiresAt time.Time) error
 	VerifyOTP(ctx context.Context, email, otp string) error
-	List(ctx context.Context, limit, offset int) ([]User, int, error)
+	List(ctx context.Context, filter UserFilter) ([]User, int, error)
 }
 
 // UserService defines the interface for user business logic
@@ -76,6 +87,6 @@ type UserService interface {
 	Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error)
 	GetProfile(ctx context.Context, userID uuid.UUID) (*User, error)
 	AdminResetPassword(ctx context.Context, targetUserID uuid.UUID, newPassword string) error
-	ListUsers(ctx context.Context, limit, offset int) ([]User, int, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]User, int, error)
 	CreateOrUpdateAdmin(ctx context.Context, user *User) error
 }
@@ -0,0 +1,85 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// utf8BOM lets Excel detect UTF-8 when opening the file directly
+const utf8BOM = "\ufeff"
+
+type csvWriter struct {
+	w       io.Writer
+	out     *csv.Writer
+	columns []domain.ExportColumn
+	record  []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: w, out: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(columns []domain.ExportColumn) error {
+	c.columns = columns
+	c.record = make([]string, len(columns))
+	if _, err := io.WriteString(c.w, utf8BOM); err != nil {
+		return err
+	}
+	for i, col := range columns {
+		c.record[i] = col.Header
+	}
+	return c.out.Write(c.record)
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	for i, col := range c.columns {
+		c.record[i] = csvCell(col.Type, values[i])
+	}
+	return c.out.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.out.Flush()
+	return c.out.Error()
+}
+
+// csvCell formats a value as text. Dates are ISO 8601 so spreadsheets parse
+// them, and text that a spreadsheet would run as a formula is quoted.
+func csvCell(typ domain.ColumnType, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if typ == domain.ColumnText {
+			return neutralizeFormula(v)
+		}
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if typ == domain.ColumnDate {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// neutralizeFormula prefixes text starting with a formula trigger with an
+// apostrophe, so student-supplied titles cannot run in the committee's Excel
+func neutralizeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
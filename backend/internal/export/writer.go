@@ -0,0 +1,19 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// NewTableWriter creates a streaming spreadsheet writer for the format
+func NewTableWriter(format domain.ExportFormat, w io.Writer) (domain.TableWriter, error) {
+	switch format {
+	case domain.ExportCSV:
+		return newCSVWriter(w), nil
+	case domain.ExportXLSX:
+		return newXLSXWriter(w), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported format %q", domain.ErrInvalidExport, format)
+}
@@ -0,0 +1,224 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// The workbook's fixed parts. A single worksheet is streamed last, so rows go
+// straight into the zip without being held in memory.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	// Cell formats, indexed by the xf* constants below
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="6">
+<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>
+<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>
+<xf numFmtId="1" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>
+</cellXfs>
+</styleSheet>`
+)
+
+const (
+	xfDefault = iota
+	xfHeader
+	xfInteger
+	xfMoney
+	xfDate
+	xfDateTime
+)
+
+// excelEpoch is day zero of Excel's 1900 date system, allowing for its
+// phantom 29 February 1900
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	columns []domain.ExportColumn
+	row     int
+}
+
+func newXLSXWriter(w io.Writer) *xlsxWriter {
+	return &xlsxWriter{zip: zip.NewWriter(w)}
+}
+
+func (x *xlsxWriter) WriteHeader(columns []domain.ExportColumn) error {
+	x.columns = columns
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, p := range parts {
+		f, err := x.zip.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<cols>`)
+	for i, col := range columns {
+		fmt.Fprintf(x.sheet, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, columnWidth(col))
+	}
+	x.sheet.WriteString(`</cols><sheetData>`)
+
+	x.row = 1
+	x.sheet.WriteString(`<row r="1">`)
+	for i, col := range columns {
+		x.inlineString(i, col.Header, xfHeader)
+	}
+	x.sheet.WriteString(`</row>`)
+	return nil
+}
+
+func (x *xlsxWriter) WriteRow(values []interface{}) error {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for i, col := range x.columns {
+		x.cell(i, col.Type, values[i])
+	}
+	x.sheet.WriteString(`</row>`)
+	// Hand full buffers to the zip stream as we go
+	if x.sheet.Buffered() > 32*1024 {
+		return x.sheet.Flush()
+	}
+	return nil
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.sheet != nil {
+		x.sheet.WriteString(`</sheetData></worksheet>`)
+		if err := x.sheet.Flush(); err != nil {
+			return err
+		}
+	}
+	return x.zip.Close()
+}
+
+// cell writes one typed cell. Numbers, booleans and dates are stored as
+// values with a number format so spreadsheets can sort and sum them.
+func (x *xlsxWriter) cell(col int, typ domain.ColumnType, v interface{}) {
+	ref := cellRef(col, x.row)
+	switch v := v.(type) {
+	case nil:
+		return
+	case int64:
+		fmt.Fprintf(x.sheet, `<c r="%s" s="%d"><v>%d</v></c>`, ref, xfInteger, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+	case time.Time:
+		style := xfDateTime
+		if typ == domain.ColumnDate {
+			style = xfDate
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, excelSerial(v))
+	case string:
+		if typ == domain.ColumnMoney {
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				fmt.Fprintf(x.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xfMoney, v)
+				return
+			}
+		}
+		x.inlineString(col, v, xfDefault)
+	}
+}
+
+func (x *xlsxWriter) inlineString(col int, s string, style int) {
+	fmt.Fprintf(x.sheet, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, cellRef(col, x.row), style)
+	xml.EscapeText(x.sheet, []byte(s))
+	x.sheet.WriteString(`</t></is></c>`)
+}
+
+// excelSerial converts a time to Excel's fractional day count, keeping the
+// wall-clock time the database returned
+func excelSerial(t time.Time) string {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	days := wall.Sub(excelEpoch).Hours() / 24
+	return strconv.FormatFloat(days, 'f', -1, 64)
+}
+
+// cellRef returns the A1-style reference of a zero-based column and row
+func cellRef(col, row int) string {
+	var letters []byte
+	for col >= 0 {
+		letters = append([]byte{byte('A' + col%26)}, letters...)
+		col = col/26 - 1
+	}
+	return string(letters) + strconv.Itoa(row)
+}
+
+// columnWidth picks a readable width for a column from its type and header
+func columnWidth(col domain.ExportColumn) int {
+	width := 20
+	switch col.Type {
+	case domain.ColumnInteger, domain.ColumnBoolean:
+		width = 10
+	case domain.ColumnMoney, domain.ColumnDate:
+		width = 14
+	case domain.ColumnDateTime:
+		width = 18
+	}
+	if n := len(strings.TrimSpace(col.Header)) + 2; n > width {
+		width = n
+	}
+	return width
+}
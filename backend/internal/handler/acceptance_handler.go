@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// AcceptanceHandler handles delivery sign-off, revision rounds and ratings
+type AcceptanceHandler struct {
+	acceptanceService domain.AcceptanceService
+	requestService    domain.BuildRequestService
+}
+
+// NewAcceptanceHandler creates a new acceptance handler
+func NewAcceptanceHandler(acceptanceService domain.AcceptanceService, requestService domain.BuildRequestService) *AcceptanceHandler {
+	return &AcceptanceHandler{
+		acceptanceService: acceptanceService,
+		requestService:    requestService,
+	}
+}
+
+// GetStatus returns the sign-off state, revision allowance and rating of a request
+// GET /api/v1/requests/:id/acceptance
+func (h *AcceptanceHandler) GetStatus(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondAcceptanceError)
+	if !ok {
+		return
+	}
+
+	status, err := h.acceptanceService.Status(c.Request.Context(), requestID)
+	if err != nil {
+		respondAcceptanceError(c, "fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": status})
+}
+
+// Accept signs off a delivery in review, completing the request
+// POST /api/v1/requests/:id/acceptance
+func (h *AcceptanceHandler) Accept(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondAcceptanceError)
+	if !ok {
+		return
+	}
+
+	var req domain.AcceptDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	status, err := h.acceptanceService.Accept(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondAcceptanceError(c, "accept_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Delivery accepted — thanks! You can now rate your build.",
+		"data":    status,
+	})
+}
+
+// RequestRevision sends a delivery in review back to the builder
+// POST /api/v1/requests/:id/revisions
+func (h *AcceptanceHandler) RequestRevision(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondAcceptanceError)
+	if !ok {
+		return
+	}
+
+	var req domain.RequestRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	status, err := h.acceptanceService.RequestRevision(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondAcceptanceError(c, "revision_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Revision requested — your builder has been notified",
+		"data":    status,
+	})
+}
+
+// Rate records or updates the student's rating of an accepted request
+// PUT /api/v1/requests/:id/rating
+func (h *AcceptanceHandler) Rate(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondAcceptanceError)
+	if !ok {
+		return
+	}
+
+	var req domain.RateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rating, err := h.acceptanceService.Rate(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondAcceptanceError(c, "rating_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Thanks for your feedback",
+		"data":    rating,
+	})
+}
+
+// BuilderQuality returns per-builder review and rating metrics (admin only)
+// GET /api/v1/admin/builders/quality
+func (h *AcceptanceHandler) BuilderQuality(c *gin.Context) {
+	quality, err := h.acceptanceService.BuilderQuality(c.Request.Context())
+	if err != nil {
+		respondAcceptanceError(c, "fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": quality})
+}
+
+func respondAcceptanceError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrNotInReview), errors.Is(err, domain.ErrRevisionLimitReached),
+		errors.Is(err, domain.ErrNotAccepted), errors.Is(err, domain.ErrPaymentRequired),
+		errors.Is(err, domain.ErrStatusDerived), errors.Is(err, domain.ErrVersionConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -13,17 +13,19 @@ import (
 
 // AdminHandler handles admin dashboard endpoints
 type AdminHandler struct {
-	requestService  domain.BuildRequestService
-	scheduleService domain.ScheduleService
-	authService     domain.UserService
+	requestService    domain.BuildRequestService
+	scheduleService   domain.ScheduleService
+	authService       domain.UserService
+	acceptanceService domain.AcceptanceService
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(requestService domain.BuildRequestService, scheduleService domain.ScheduleService, authService domain.UserService) *AdminHandler {
+func NewAdminHandler(requestService domain.BuildRequestService, scheduleService domain.ScheduleService, authService domain.UserService, acceptanceService domain.AcceptanceService) *AdminHandler {
 	return &AdminHandler{
-		requestService:  requestService,
-		scheduleService: scheduleService,
-		authService:     authService,
+		requestService:    requestService,
+		scheduleService:   scheduleService,
+		authService:       authService,
+		acceptanceService: acceptanceService,
 	}
 }
 
@@ -38,15 +40,16 @@ func (h *AdminHandler) Dashboard(c *gin.Context) {
 		domain.StatusQueued,
 		domain.StatusScheduled,
 		domain.StatusBuilding,
+		domain.StatusReview,
 		domain.StatusCompleted,
 	}
 
 	stats := make(map[string]interface{})
 	for _, status := range statuses {
 		filter := domain.RequestFilter{
-			Status: &status,
-			Limit:  1,
-			Offset: 0,
+			Status:       &status,
+			Limit:        1,
+			IncludeTotal: true,
 		}
 		_, total, err := h.requestService.ListAll(ctx, filter)
 		if err != nil {
@@ -58,19 +61,44 @@ func (h *AdminHandler) Dashboard(c *gin.Context) {
 	// Get upcoming slots
 	slots, _ := h.scheduleService.GetUpcomingSlots(ctx)
 
+	// Slot capacity follows builder availability, so total it from the slots
+	var totalHours, bookedHours int
+	for _, slot := range slots {
+		totalHours += slot.TotalHours
+		bookedHours += slot.BookedHours
+	}
+
+	// Per-builder ratings and revision rates
+	quality, _ := h.acceptanceService.BuilderQuality(ctx)
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
-			"request_stats":  stats,
-			"upcoming_slots": slots,
-			"build_hours":    "8 hours per day (Sat + Sun)",
+			"request_stats":   stats,
+			"upcoming_slots":  slots,
+			"builder_quality": quality,
+			"build_hours":     gin.H{"total": totalHours, "booked": bookedHours},
 		},
 	})
 }
 
-// ListUsers returns all users (admin only)
-// GET /api/v1/admin/users
+// ListUsers returns users, newest first (admin only)
+// GET /api/v1/admin/users?limit=100&cursor=...
 func (h *AdminHandler) ListUsers(c *gin.Context) {
-	users, total, err := h.authService.ListUsers(c.Request.Context(), 200, 0)
+	page, err := parsePageParams(c, domain.MaxPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_pagination",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	users, total, err := h.authService.ListUsers(c.Request.Context(), domain.UserFilter{
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+		After:        page.After,
+		IncludeTotal: page.IncludeTotal,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "list_users_failed",
@@ -79,10 +107,11 @@ func (h *AdminHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  users,
-		"total": total,
+	next := nextCursor(len(users), page.Limit, func() domain.Cursor {
+		last := users[len(users)-1]
+		return domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
 	})
+	respondPage(c, users, total, page, next)
 }
 
 // ResetPassword resets a user's password (admin only)
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// AutomationHandler manages the rules that act on stale and idle requests (admin)
+type AutomationHandler struct {
+	automationService domain.AutomationService
+}
+
+// NewAutomationHandler creates a new automation handler
+func NewAutomationHandler(automationService domain.AutomationService) *AutomationHandler {
+	return &AutomationHandler{automationService: automationService}
+}
+
+// ListRules returns every automation rule
+// GET /api/v1/admin/automation/rules
+func (h *AutomationHandler) ListRules(c *gin.Context) {
+	rules, err := h.automationService.ListRules(c.Request.Context())
+	if err != nil {
+		respondAutomationError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// CreateRule creates an automation rule, disabled unless enabled is set
+// POST /api/v1/admin/automation/rules
+func (h *AutomationHandler) CreateRule(c *gin.Context) {
+	var req domain.SaveAutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.automationService.CreateRule(c.Request.Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		respondAutomationError(c, "create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Automation rule created",
+		"data":    rule,
+	})
+}
+
+// GetRule returns one automation rule
+// GET /api/v1/admin/automation/rules/:ruleId
+func (h *AutomationHandler) GetRule(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.automationService.GetRule(c.Request.Context(), ruleID)
+	if err != nil {
+		respondAutomationError(c, "not_found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rule})
+}
+
+// UpdateRule replaces an automation rule; enabled is kept when omitted
+// PUT /api/v1/admin/automation/rules/:ruleId
+func (h *AutomationHandler) UpdateRule(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SaveAutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.automationService.UpdateRule(c.Request.Context(), ruleID, &req)
+	if err != nil {
+		respondAutomationError(c, "update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Automation rule updated",
+		"data":    rule,
+	})
+}
+
+// DeleteRule removes an automation rule and its history
+// DELETE /api/v1/admin/automation/rules/:ruleId
+func (h *AutomationHandler) DeleteRule(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.automationService.DeleteRule(c.Request.Context(), ruleID); err != nil {
+		respondAutomationError(c, "delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Automation rule deleted"})
+}
+
+// History lists the requests a rule has acted on, newest first
+// GET /api/v1/admin/automation/rules/:ruleId/history
+func (h *AutomationHandler) History(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	hits, err := h.automationService.ListHits(c.Request.Context(), ruleID)
+	if err != nil {
+		respondAutomationError(c, "history_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": hits})
+}
+
+// Preview lists the requests the rule would act on now, without acting.
+// Works for disabled rules too, so a rule can be checked before enabling it.
+// POST /api/v1/admin/automation/rules/:ruleId/preview
+func (h *AutomationHandler) Preview(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.automationService.Preview(c.Request.Context(), ruleID)
+	if err != nil {
+		respondAutomationError(c, "preview_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": run})
+}
+
+// Run runs a rule now instead of waiting for the background job
+// POST /api/v1/admin/automation/rules/:ruleId/run
+func (h *AutomationHandler) Run(c *gin.Context) {
+	ruleID, ok := parseRuleID(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.automationService.Run(c.Request.Context(), ruleID)
+	if err != nil {
+		respondAutomationError(c, "run_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Automation rule run",
+		"data":    run,
+	})
+}
+
+func parseRuleID(c *gin.Context) (uuid.UUID, bool) {
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid automation rule ID"})
+		return uuid.Nil, false
+	}
+	return ruleID, true
+}
+
+func respondAutomationError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrAutomationRuleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAutomationRule):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// AvailabilityHandler lets builders declare the weekends they can build
+type AvailabilityHandler struct {
+	availabilityService domain.AvailabilityService
+}
+
+// NewAvailabilityHandler creates a new availability handler
+func NewAvailabilityHandler(availabilityService domain.AvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{availabilityService: availabilityService}
+}
+
+// Calendar returns the caller's patterns, day entries and resolved hours for
+// each weekend day in the range, the next eight weeks by default
+// GET /api/v1/admin/availability?from=2026-03-01&to=2026-04-30
+func (h *AvailabilityHandler) Calendar(c *gin.Context) {
+	from := time.Now()
+	to := from.AddDate(0, 0, 56)
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		s := c.Query(p.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_date",
+				"message": p.name + " must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		*p.dst = t
+	}
+
+	cal, err := h.availabilityService.Calendar(c.Request.Context(), getUserIDFromContext(c), from, to)
+	if err != nil {
+		respondAvailabilityError(c, "fetch_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": cal})
+}
+
+// CreatePattern declares weekly availability
+// POST /api/v1/admin/availability/patterns
+func (h *AvailabilityHandler) CreatePattern(c *gin.Context) {
+	var req domain.CreateAvailabilityPatternRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	pattern, err := h.availabilityService.CreatePattern(c.Request.Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		respondAvailabilityError(c, "create_failed", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Weekly availability saved",
+		"data":    pattern,
+	})
+}
+
+// DeletePattern removes one of the caller's weekly patterns
+// DELETE /api/v1/admin/availability/patterns/:id
+func (h *AvailabilityHandler) DeletePattern(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pattern ID"})
+		return
+	}
+
+	if err := h.availabilityService.DeletePattern(c.Request.Context(), getUserIDFromContext(c), id); err != nil {
+		respondAvailabilityError(c, "delete_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Weekly availability removed"})
+}
+
+// SetDay declares the caller's availability on one weekend day, overriding
+// their weekly pattern; hours of 0 marks them away
+// PUT /api/v1/admin/availability/days/:date
+func (h *AvailabilityHandler) SetDay(c *gin.Context) {
+	date, ok := parseAvailabilityDate(c)
+	if !ok {
+		return
+	}
+	var req domain.SetAvailabilityDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	day, err := h.availabilityService.SetDay(c.Request.Context(), getUserIDFromContext(c), date, &req)
+	if err != nil {
+		respondAvailabilityError(c, "update_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Availability saved",
+		"data":    day,
+	})
+}
+
+// ClearDay removes the caller's entry for one day, so their weekly pattern
+// applies again
+// DELETE /api/v1/admin/availability/days/:date
+func (h *AvailabilityHandler) ClearDay(c *gin.Context) {
+	date, ok := parseAvailabilityDate(c)
+	if !ok {
+		return
+	}
+
+	if err := h.availabilityService.ClearDay(c.Request.Context(), getUserIDFromContext(c), date); err != nil {
+		respondAvailabilityError(c, "delete_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Availability cleared"})
+}
+
+func parseAvailabilityDate(c *gin.Context) (time.Time, bool) {
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_date",
+			"message": "Date must be in YYYY-MM-DD format",
+		})
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func respondAvailabilityError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrAvailabilityNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidAvailability):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// CertificateHandler handles TLS certificates for whitelabel domains
+type CertificateHandler struct {
+	certService    domain.CertificateService
+	requestService domain.BuildRequestService
+}
+
+// NewCertificateHandler creates a new certificate handler
+func NewCertificateHandler(certService domain.CertificateService, requestService domain.BuildRequestService) *CertificateHandler {
+	return &CertificateHandler{
+		certService:    certService,
+		requestService: requestService,
+	}
+}
+
+// Get returns the certificate status of a request's custom domain
+// GET /api/v1/requests/:id/certificate
+func (h *CertificateHandler) Get(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondCertificateError)
+	if !ok {
+		return
+	}
+
+	cert, err := h.certService.Get(c.Request.Context(), requestID)
+	if err != nil {
+		respondCertificateError(c, "not_found", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": cert})
+}
+
+// Provision requests a certificate for a verified whitelabel domain (admin)
+// POST /api/v1/admin/requests/:id/certificate
+func (h *CertificateHandler) Provision(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	cert, err := h.certService.Provision(c.Request.Context(), requestID)
+	if err != nil {
+		respondCertificateError(c, "provision_failed", err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Certificate requested — check its status in a minute",
+		"data":    cert,
+	})
+}
+
+// Download returns the issued chain and private key as PEM (admin), for
+// installing on the student's host
+// GET /api/v1/admin/requests/:id/certificate/pem
+func (h *CertificateHandler) Download(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	cert, err := h.certService.Get(c.Request.Context(), requestID)
+	if err != nil {
+		respondCertificateError(c, "not_found", err)
+		return
+	}
+	if cert.CertPEM == "" {
+		respondCertificateError(c, "not_issued", domain.ErrCertificateNotFound)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+cert.Domain+`.pem"`)
+	c.Data(http.StatusOK, "application/x-pem-file", []byte(cert.CertPEM+cert.KeyPEM))
+}
+
+// Challenge answers ACME HTTP-01 challenges. It is public: the CA fetches it
+// over plain HTTP on the student's domain, which points at this server.
+// GET /.well-known/acme-challenge/:token
+func (h *CertificateHandler) Challenge(c *gin.Context) {
+	keyAuth, err := h.certService.ChallengeResponse(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, domain.ErrChallengeNotFound) {
+			c.String(http.StatusNotFound, "not found")
+			return
+		}
+		c.String(http.StatusInternalServerError, "error")
+		return
+	}
+	c.String(http.StatusOK, keyAuth)
+}
+
+func respondCertificateError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrCertificateNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrNoCustomDomain), errors.Is(err, domain.ErrDomainNotVerified):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrCertificatesDisabled):
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// DeliverableHandler handles the separately shipped parts of a request
+type DeliverableHandler struct {
+	deliverableService domain.DeliverableService
+	requestService     domain.BuildRequestService
+}
+
+// NewDeliverableHandler creates a new deliverable handler
+func NewDeliverableHandler(deliverableService domain.DeliverableService, requestService domain.BuildRequestService) *DeliverableHandler {
+	return &DeliverableHandler{
+		deliverableService: deliverableService,
+		requestService:     requestService,
+	}
+}
+
+// ListForRequest returns a request's deliverables
+// GET /api/v1/requests/:id/deliverables
+func (h *DeliverableHandler) ListForRequest(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondDeliverableError)
+	if !ok {
+		return
+	}
+
+	deliverables, err := h.deliverableService.ListForRequest(c.Request.Context(), requestID)
+	if err != nil {
+		respondDeliverableError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": deliverables})
+}
+
+// Create adds a deliverable, or the usual ones for the request type when no
+// kind is given (staff only)
+// POST /api/v1/admin/requests/:id/deliverables
+func (h *DeliverableHandler) Create(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req domain.CreateDeliverableRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	deliverables, err := h.deliverableService.Create(c.Request.Context(), requestID, &req)
+	if err != nil {
+		respondDeliverableError(c, "create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Deliverables added",
+		"data":    deliverables,
+	})
+}
+
+// Update changes a deliverable's status, links or handover checklist; the
+// request's status follows (staff only)
+// PATCH /api/v1/admin/requests/:id/deliverables/:deliverableId
+func (h *DeliverableHandler) Update(c *gin.Context) {
+	requestID, deliverableID, ok := parseDeliverableIDs(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateDeliverableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	deliverable, err := h.deliverableService.Update(c.Request.Context(), getUserIDFromContext(c), requestID, deliverableID, &req)
+	if err != nil {
+		respondDeliverableError(c, "update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Deliverable updated",
+		"data":    deliverable,
+	})
+}
+
+// AddArtifact records a build output for a deliverable (staff only)
+// POST /api/v1/admin/requests/:id/deliverables/:deliverableId/artifacts
+func (h *DeliverableHandler) AddArtifact(c *gin.Context) {
+	requestID, deliverableID, ok := parseDeliverableIDs(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AddBuildArtifactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	deliverable, err := h.deliverableService.AddArtifact(c.Request.Context(), requestID, deliverableID, &req)
+	if err != nil {
+		respondDeliverableError(c, "artifact_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Build artifact added",
+		"data":    deliverable,
+	})
+}
+
+func parseDeliverableIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	deliverableID, err := uuid.Parse(c.Param("deliverableId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid deliverable ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return requestID, deliverableID, true
+}
+
+func respondDeliverableError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrDeliverableNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrDeliverablesNotStarted), errors.Is(err, domain.ErrChecklistIncomplete),
+		errors.Is(err, domain.ErrQuoteNotAccepted), errors.Is(err, domain.ErrPaymentRequired),
+		errors.Is(err, domain.ErrDomainNotVerified), errors.Is(err, domain.ErrVersionConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
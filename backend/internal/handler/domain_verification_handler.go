@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// DomainVerificationHandler lets students prove they control a whitelabel domain
+type DomainVerificationHandler struct {
+	verificationService domain.DomainVerificationService
+	requestService      domain.BuildRequestService
+}
+
+// NewDomainVerificationHandler creates a new domain verification handler
+func NewDomainVerificationHandler(verificationService domain.DomainVerificationService, requestService domain.BuildRequestService) *DomainVerificationHandler {
+	return &DomainVerificationHandler{
+		verificationService: verificationService,
+		requestService:      requestService,
+	}
+}
+
+// Get returns the TXT record the student has to publish, and the current state
+// GET /api/v1/requests/:id/domain
+func (h *DomainVerificationHandler) Get(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondDomainError)
+	if !ok {
+		return
+	}
+
+	v, err := h.verificationService.Get(c.Request.Context(), requestID)
+	if err != nil {
+		respondDomainError(c, "not_found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": v})
+}
+
+// Verify checks DNS for the TXT record now
+// POST /api/v1/requests/:id/domain/verify
+func (h *DomainVerificationHandler) Verify(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondDomainError)
+	if !ok {
+		return
+	}
+
+	v, err := h.verificationService.Verify(c.Request.Context(), requestID)
+	if err != nil {
+		respondDomainError(c, "verify_failed", err)
+		return
+	}
+
+	message := "Domain verified"
+	if v.Status != domain.DomainVerified {
+		message = "Domain not verified yet: " + v.LastError
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    v,
+	})
+}
+
+func respondDomainError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrNoCustomDomain):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Versioned records (requests, weekend slots) use their version as a strong
+// ETag, e.g. "7". Updates send it back in If-Match.
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// requireIfMatch returns the version from the If-Match header. It answers
+// 428 when the header is missing and 400 when it is not one of our ETags.
+func requireIfMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error":   "precondition_required",
+			"message": "send the ETag from the last read in the If-Match header",
+		})
+		return 0, false
+	}
+	version, err := parseETag(header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+func parseETag(etag string) (int, error) {
+	etag = strings.TrimPrefix(etag, "W/")
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, errors.New(`If-Match must be a quoted ETag such as "3"`)
+	}
+	version, err := strconv.Atoi(etag[1 : len(etag)-1])
+	if err != nil || version < 1 {
+		return 0, errors.New(`If-Match must be a quoted ETag such as "3"`)
+	}
+	return version, nil
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// ExportHandler handles spreadsheet exports (admin)
+type ExportHandler struct {
+	exportService domain.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService domain.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// Columns lists the columns a dataset can be exported with
+// GET /api/v1/admin/exports/:dataset/columns
+func (h *ExportHandler) Columns(c *gin.Context) {
+	columns := domain.ExportColumnsFor(domain.ExportDataset(c.Param("dataset")))
+	if columns == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "unknown_dataset",
+			"message": "dataset must be requests, users or schedules",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": columns})
+}
+
+// Export streams a dataset as CSV or XLSX. Requests take the same filters as
+// the admin listing; users take role and joined_from/joined_to; schedules
+// take from/to on the slot date. Dates are YYYY-MM-DD, both inclusive.
+// GET /api/v1/admin/exports/:dataset?format=xlsx&columns=title,status,student_name&status=completed
+func (h *ExportHandler) Export(c *gin.Context) {
+	q, err := parseExportQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_export",
+			"message": err.Error(),
+		})
+		return
+	}
+	if _, err := h.exportService.Prepare(q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_export",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", q.Dataset, time.Now().Format("2006-01-02"), q.Format)
+	c.Header("Content-Type", q.Format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := h.exportService.Export(c.Request.Context(), q, c.Writer); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "export_failed",
+				"message": err.Error(),
+			})
+			return
+		}
+		// Too late to change the status; the client gets a truncated file
+		log.Error().Err(err).Str("dataset", string(q.Dataset)).Msg("Export failed mid-stream")
+	}
+}
+
+func parseExportQuery(c *gin.Context) (*domain.ExportQuery, error) {
+	q := &domain.ExportQuery{
+		Dataset: domain.ExportDataset(c.Param("dataset")),
+		Format:  domain.ExportFormat(strings.ToLower(c.DefaultQuery("format", "csv"))),
+	}
+	if cols := c.Query("columns"); cols != "" {
+		for _, key := range strings.Split(cols, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				q.Columns = append(q.Columns, key)
+			}
+		}
+	}
+
+	switch q.Dataset {
+	case domain.ExportRequests:
+		filter, err := parseRequestFilter(c)
+		if err != nil {
+			return nil, err
+		}
+		q.Requests = filter
+	case domain.ExportUsers:
+		if role := c.Query("role"); role != "" {
+			r := domain.Role(role)
+			if r != domain.RoleStudent && r != domain.RoleBuilder && r != domain.RoleAdmin {
+				return nil, errors.New("role must be student, builder or admin")
+			}
+			q.Role = &r
+		}
+		return q, parseDateRange(c, "joined_from", "joined_to", &q.From, &q.To)
+	case domain.ExportSchedules:
+		return q, parseDateRange(c, "from", "to", &q.From, &q.To)
+	}
+	return q, nil
+}
+
+// parseDateRange reads an inclusive YYYY-MM-DD range into [from, to)
+func parseDateRange(c *gin.Context, fromKey, toKey string, from, to **time.Time) error {
+	if v := c.Query(fromKey); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("%s must be in YYYY-MM-DD format", fromKey)
+		}
+		*from = &t
+	}
+	if v := c.Query(toKey); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("%s must be in YYYY-MM-DD format", toKey)
+		}
+		t = t.AddDate(0, 0, 1)
+		*to = &t
+	}
+	return nil
+}
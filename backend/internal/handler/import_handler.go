@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// ImportHandler handles the legacy CSV import (admin)
+type ImportHandler struct {
+	importService domain.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService domain.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Upload stores a CSV and suggests a column mapping from its headers
+// POST /api/v1/admin/imports (multipart form, field "file")
+func (h *ImportHandler) Upload(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": "upload the CSV as the multipart field \"file\"",
+		})
+		return
+	}
+	if file.Size > domain.MaxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "file_too_large",
+			"message": "the CSV must be 5 MB or smaller",
+		})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation_error", "message": err.Error()})
+		return
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, domain.MaxImportBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation_error", "message": err.Error()})
+		return
+	}
+
+	batch, err := h.importService.Upload(c.Request.Context(), getUserIDFromContext(c), file.Filename, content)
+	if err != nil {
+		respondImportError(c, "upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "CSV uploaded — check the column mapping, then run a dry run",
+		"data":    batch,
+	})
+}
+
+// Get returns an import with its mapping and latest report
+// GET /api/v1/admin/imports/:id
+func (h *ImportHandler) Get(c *gin.Context) {
+	id, ok := parseImportID(c)
+	if !ok {
+		return
+	}
+	batch, err := h.importService.Get(c.Request.Context(), id)
+	if err != nil {
+		respondImportError(c, "not_found", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": batch})
+}
+
+// SetMapping maps import fields to CSV columns
+// PUT /api/v1/admin/imports/:id/mapping
+func (h *ImportHandler) SetMapping(c *gin.Context) {
+	id, ok := parseImportID(c)
+	if !ok {
+		return
+	}
+	var req domain.UpdateImportMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	batch, err := h.importService.SetMapping(c.Request.Context(), id, req.Mapping)
+	if err != nil {
+		respondImportError(c, "mapping_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Mapping saved",
+		"data":    batch,
+	})
+}
+
+// DryRun validates every row without writing anything and reports per-row errors
+// POST /api/v1/admin/imports/:id/dry-run
+func (h *ImportHandler) DryRun(c *gin.Context) {
+	id, ok := parseImportID(c)
+	if !ok {
+		return
+	}
+	batch, err := h.importService.DryRun(c.Request.Context(), id)
+	if err != nil {
+		respondImportError(c, "dry_run_failed", err)
+		return
+	}
+
+	message := "All rows are valid — ready to commit"
+	if batch.Status == domain.ImportInvalid {
+		message = "Some rows have errors — fix the file or mapping and run again"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    batch,
+	})
+}
+
+// Commit writes a validated import in one transaction
+// POST /api/v1/admin/imports/:id/commit
+func (h *ImportHandler) Commit(c *gin.Context) {
+	id, ok := parseImportID(c)
+	if !ok {
+		return
+	}
+	batch, err := h.importService.Commit(c.Request.Context(), id)
+	if err != nil {
+		respondImportError(c, "commit_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Import committed",
+		"data":    batch,
+	})
+}
+
+func parseImportID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondImportError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrImportNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidImport):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrImportNotValidated), errors.Is(err, domain.ErrImportCommitted):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
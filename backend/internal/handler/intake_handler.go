@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// IntakeHandler exposes request quotas, duplicate checks and quota overrides
+type IntakeHandler struct {
+	intakeService domain.IntakeService
+}
+
+// NewIntakeHandler creates a new intake handler
+func NewIntakeHandler(intakeService domain.IntakeService) *IntakeHandler {
+	return &IntakeHandler{intakeService: intakeService}
+}
+
+// Check reports whether a request could be submitted, with quota usage and
+// look-alike earlier requests, without creating it
+// POST /api/v1/requests/check
+func (h *IntakeHandler) Check(c *gin.Context) {
+	var req domain.CreateBuildRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.intakeService.Preview(c.Request.Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		respondIntakeError(c, "check_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+// GetOverride returns a student's quota override (admin only)
+// GET /api/v1/admin/users/:id/quota-override
+func (h *IntakeHandler) GetOverride(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	override, err := h.intakeService.GetOverride(c.Request.Context(), userID)
+	if err != nil {
+		respondIntakeError(c, "not_found", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": override})
+}
+
+// GrantOverride exempts a student from request quotas (admin only)
+// PUT /api/v1/admin/users/:id/quota-override
+func (h *IntakeHandler) GrantOverride(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req domain.GrantQuotaOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	override, err := h.intakeService.GrantOverride(c.Request.Context(), getUserIDFromContext(c), userID, &req)
+	if err != nil {
+		respondIntakeError(c, "override_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Quota override granted",
+		"data":    override,
+	})
+}
+
+// RevokeOverride puts a student back under the request quotas (admin only)
+// DELETE /api/v1/admin/users/:id/quota-override
+func (h *IntakeHandler) RevokeOverride(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if err := h.intakeService.RevokeOverride(c.Request.Context(), userID); err != nil {
+		respondIntakeError(c, "revoke_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Quota override revoked"})
+}
+
+func respondIntakeError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrQuotaOverrideNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidQuotaOverride), errors.Is(err, domain.ErrUnknownAddOn):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// InvoiceHandler serves GST invoices and receipts for paid requests
+type InvoiceHandler struct {
+	invoiceService domain.InvoiceService
+	requestService domain.BuildRequestService
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(invoiceService domain.InvoiceService, requestService domain.BuildRequestService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+		requestService: requestService,
+	}
+}
+
+// ListForRequest returns the invoices and receipts issued for a request
+// GET /api/v1/requests/:id/invoices
+func (h *InvoiceHandler) ListForRequest(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondInvoiceError)
+	if !ok {
+		return
+	}
+
+	invoices, err := h.invoiceService.ListForRequest(c.Request.Context(), requestID)
+	if err != nil {
+		respondInvoiceError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": invoices})
+}
+
+// Download streams the PDF of an invoice or receipt
+// GET /api/v1/requests/:id/invoices/:invoiceId/pdf
+func (h *InvoiceHandler) Download(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondInvoiceError)
+	if !ok {
+		return
+	}
+	invoiceID, err := uuid.Parse(c.Param("invoiceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invoice ID"})
+		return
+	}
+
+	doc, err := h.invoiceService.GetDocument(c.Request.Context(), requestID, invoiceID)
+	if err != nil {
+		respondInvoiceError(c, "download_failed", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, doc.Filename))
+	c.Data(http.StatusOK, doc.ContentType, doc.Content)
+}
+
+// Issue issues any outstanding receipts and, for completed requests, the tax
+// invoice. Normally this happens on completion; staff use it to retry.
+// POST /api/v1/admin/requests/:id/invoices
+func (h *InvoiceHandler) Issue(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	invoices, err := h.invoiceService.IssueForRequest(c.Request.Context(), requestID)
+	if err != nil {
+		respondInvoiceError(c, "issue_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invoices issued",
+		"data":    invoices,
+	})
+}
+
+func respondInvoiceError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrInvoiceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrInvoiceNotRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrQuoteNotAccepted):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
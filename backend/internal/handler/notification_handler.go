@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// NotificationHandler serves the current user's in-app notifications
+type NotificationHandler struct {
+	notificationService domain.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService domain.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// List returns the user's latest notifications; ?unread=true skips read ones
+// GET /api/v1/notifications
+func (h *NotificationHandler) List(c *gin.Context) {
+	unreadOnly := c.Query("unread") == "true"
+	notifications, err := h.notificationService.List(c.Request.Context(), getUserIDFromContext(c), unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "list_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": notifications})
+}
+
+// MarkRead marks one of the user's notifications as read
+// POST /api/v1/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationService.MarkRead(c.Request.Context(), getUserIDFromContext(c), id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "mark_read_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// pageParams holds the pagination query parameters shared by list endpoints
+type pageParams struct {
+	Limit        int
+	Offset       int
+	After        *domain.Cursor
+	IncludeTotal bool
+	legacy       bool // offset mode, kept for older clients
+}
+
+// parsePageParams reads ?limit, ?cursor, ?include_total and the deprecated
+// ?offset. Offset mode is used only when offset is given without a cursor; it
+// always includes the total, as it did before cursors existed.
+func parsePageParams(c *gin.Context, defaultLimit int) (pageParams, error) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	p := pageParams{Limit: domain.ClampPageSize(limit, defaultLimit)}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := domain.DecodeCursor(cursor)
+		if err != nil {
+			return p, err
+		}
+		p.After = after
+	} else if offsetStr, ok := c.GetQuery("offset"); ok {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return p, errors.New("offset must be a non-negative integer")
+		}
+		p.Offset = offset
+		p.legacy = true
+		p.IncludeTotal = true
+		c.Header("Deprecation", "true")
+	}
+
+	if totalStr := c.Query("include_total"); totalStr != "" {
+		includeTotal, err := strconv.ParseBool(totalStr)
+		if err != nil {
+			return p, errors.New("include_total must be true or false")
+		}
+		p.IncludeTotal = p.IncludeTotal || includeTotal
+	}
+	return p, nil
+}
+
+// nextCursor returns the cursor for the page after one ending at last, or
+// nil when the page was not full and there is nothing more to fetch.
+func nextCursor(count, limit int, last func() domain.Cursor) *string {
+	if count == 0 || count < limit {
+		return nil
+	}
+	next := last().Encode()
+	return &next
+}
+
+// respondPage writes a paginated list response
+func respondPage(c *gin.Context, data interface{}, total int, p pageParams, next *string) {
+	resp := gin.H{
+		"data":        data,
+		"limit":       p.Limit,
+		"next_cursor": next,
+	}
+	if p.IncludeTotal {
+		resp["total"] = total
+	}
+	if p.legacy {
+		resp["offset"] = p.Offset
+	}
+	c.JSON(http.StatusOK, resp)
+}
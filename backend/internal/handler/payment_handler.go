@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/makeitexist/backend/internal/payment"
+)
+
+// PaymentHandler handles payment intents, refunds and provider webhooks
+type PaymentHandler struct {
+	paymentService domain.PaymentService
+	requestService domain.BuildRequestService
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(paymentService domain.PaymentService, requestService domain.BuildRequestService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		requestService: requestService,
+	}
+}
+
+// CreateIntent starts a payment against the request's accepted quote
+// POST /api/v1/requests/:id/payments
+func (h *PaymentHandler) CreateIntent(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondPaymentError)
+	if !ok {
+		return
+	}
+
+	var req domain.CreatePaymentIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	intent, err := h.paymentService.CreateIntent(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondPaymentError(c, "payment_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Payment started — complete it with the provider to confirm",
+		"data":    intent,
+	})
+}
+
+// GetSummary returns the payment position and ledger of a request
+// GET /api/v1/requests/:id/payments
+func (h *PaymentHandler) GetSummary(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondPaymentError)
+	if !ok {
+		return
+	}
+
+	summary, err := h.paymentService.Summary(c.Request.Context(), requestID)
+	if err != nil {
+		respondPaymentError(c, "fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summary})
+}
+
+// Refund returns part or all of a captured payment (admin only)
+// POST /api/v1/admin/payments/:intentId/refund
+func (h *PaymentHandler) Refund(c *gin.Context) {
+	intentID, err := uuid.Parse(c.Param("intentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payment intent ID"})
+		return
+	}
+
+	var req domain.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.paymentService.Refund(c.Request.Context(), intentID, &req)
+	if err != nil {
+		respondPaymentError(c, "refund_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Refund issued",
+		"data":    entry,
+	})
+}
+
+// Webhook ingests signed events from a payment provider
+// POST /api/v1/webhooks/payments/:provider
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_body"})
+		return
+	}
+
+	signature := c.GetHeader("X-Razorpay-Signature")
+	applied, err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), signature, body)
+	if err != nil {
+		respondPaymentError(c, "webhook_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"applied": applied})
+}
+
+func respondPaymentError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrPaymentIntentNotFound),
+		errors.Is(err, domain.ErrUnknownPaymentProvider):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, payment.ErrInvalidSignature):
+		status = http.StatusUnauthorized
+	case errors.Is(err, domain.ErrInvalidPaymentAmount), errors.Is(err, domain.ErrPaymentNotRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrQuoteNotAccepted):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrCurrencyMismatch):
+		// Providers retry 5xx responses; this event can never apply
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
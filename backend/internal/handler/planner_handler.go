@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// PlannerHandler proposes and applies weekend plans (admin)
+type PlannerHandler struct {
+	plannerService domain.PlannerService
+}
+
+// NewPlannerHandler creates a new planner handler
+func NewPlannerHandler(plannerService domain.PlannerService) *PlannerHandler {
+	return &PlannerHandler{plannerService: plannerService}
+}
+
+// Propose packs queued requests into a weekend's slots without booking
+// anything. The weekend defaults to the next one.
+// GET /api/v1/admin/schedule/plan?weekend=2026-02-21
+func (h *PlannerHandler) Propose(c *gin.Context) {
+	weekend := domain.NextWeekendSaturday()
+	if raw := c.Query("weekend"); raw != "" {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil || date.Weekday() != time.Saturday {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_date",
+				"message": "weekend must be a Saturday in YYYY-MM-DD format",
+			})
+			return
+		}
+		weekend = date
+	}
+
+	plan, err := h.plannerService.Propose(c.Request.Context(), weekend)
+	if err != nil {
+		respondPlannerError(c, "plan_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review the plan, drop any assignments you disagree with, then apply it",
+		"data":    plan,
+	})
+}
+
+// Apply books a reviewed plan in one transaction; nothing is booked if any
+// slot or request changed since it was proposed
+// POST /api/v1/admin/schedule/plan/apply
+func (h *PlannerHandler) Apply(c *gin.Context) {
+	var plan domain.WeekendPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entries, err := h.plannerService.Apply(c.Request.Context(), &plan)
+	if err != nil {
+		respondPlannerError(c, "apply_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Plan applied",
+		"data":    entries,
+	})
+}
+
+func respondPlannerError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrNoWeekendSlots), errors.Is(err, domain.ErrSlotNotFound),
+		errors.Is(err, domain.ErrRequestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrEmptyPlan):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrPlanStale), errors.Is(err, domain.ErrSlotFull),
+		errors.Is(err, domain.ErrRequestNotSchedulable), errors.Is(err, domain.ErrQuoteNotAccepted),
+		errors.Is(err, domain.ErrPaymentRequired), errors.Is(err, domain.ErrDomainNotVerified),
+		errors.Is(err, domain.ErrVersionConflict), errors.Is(err, domain.ErrBuilderUnavailable):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
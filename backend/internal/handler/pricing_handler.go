@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// PricingHandler handles pricing rule and quote endpoints
+type PricingHandler struct {
+	pricingService domain.PricingService
+	requestService domain.BuildRequestService
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService domain.PricingService, requestService domain.BuildRequestService) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+		requestService: requestService,
+	}
+}
+
+// GetActiveRules returns the price list currently in effect
+// GET /api/v1/pricing
+func (h *PricingHandler) GetActiveRules(c *gin.Context) {
+	set, err := h.pricingService.ActiveRuleSet(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "fetch_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": set})
+}
+
+// Estimate prices a hypothetical request with the active rules
+// POST /api/v1/pricing/estimate
+func (h *PricingHandler) Estimate(c *gin.Context) {
+	var req domain.PricingInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	quote, err := h.pricingService.Quote(c.Request.Context(), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrUnknownAddOn) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"error":   "estimate_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": quote})
+}
+
+// GetRequestBreakdown returns the itemized estimate for a build request
+// GET /api/v1/requests/:id/pricing
+func (h *PricingHandler) GetRequestBreakdown(c *gin.Context) {
+	req, ok := loadAuthorizedRequest(c, h.requestService, respondPricingError)
+	if !ok {
+		return
+	}
+
+	breakdown, err := h.pricingService.RequestBreakdown(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "pricing_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": breakdown})
+}
+
+// ListRuleSets returns every pricing rule set version (admin only)
+// GET /api/v1/admin/pricing/rules
+func (h *PricingHandler) ListRuleSets(c *gin.Context) {
+	sets, err := h.pricingService.ListRuleSets(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "list_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": sets})
+}
+
+// CreateRuleSet drafts a new pricing rule set (admin only)
+// POST /api/v1/admin/pricing/rules
+func (h *PricingHandler) CreateRuleSet(c *gin.Context) {
+	var req domain.CreatePricingRuleSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	set, err := h.pricingService.CreateDraft(c.Request.Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "create_failed",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Pricing rules drafted. Preview the impact, then activate.",
+		"data":    set,
+	})
+}
+
+// GetRuleSet returns a single pricing rule set (admin only)
+// GET /api/v1/admin/pricing/rules/:version
+func (h *PricingHandler) GetRuleSet(c *gin.Context) {
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	set, err := h.pricingService.GetRuleSet(c.Request.Context(), version)
+	if err != nil {
+		respondPricingError(c, "fetch_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": set})
+}
+
+// PreviewImpact shows how a rule set would reprice open requests (admin only)
+// GET /api/v1/admin/pricing/rules/:version/impact
+func (h *PricingHandler) PreviewImpact(c *gin.Context) {
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	impact, err := h.pricingService.PreviewImpact(c.Request.Context(), version)
+	if err != nil {
+		respondPricingError(c, "preview_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": impact})
+}
+
+// ActivateRuleSet makes a draft rule set the active price list (admin only)
+// POST /api/v1/admin/pricing/rules/:version/activate
+func (h *PricingHandler) ActivateRuleSet(c *gin.Context) {
+	version, ok := parseVersionParam(c)
+	if !ok {
+		return
+	}
+
+	set, err := h.pricingService.Activate(c.Request.Context(), version)
+	if err != nil {
+		respondPricingError(c, "activate_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pricing rules activated",
+		"data":    set,
+	})
+}
+
+func parseVersionParam(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_version",
+			"message": "Version must be a positive integer",
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+func respondPricingError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrPricingRuleSetNotFound), errors.Is(err, domain.ErrRequestNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
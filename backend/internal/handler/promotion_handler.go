@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// PromotionHandler handles discount codes, scholarships and fee waivers
+type PromotionHandler struct {
+	promotionService domain.PromotionService
+	requestService   domain.BuildRequestService
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(promotionService domain.PromotionService, requestService domain.BuildRequestService) *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: promotionService,
+		requestService:   requestService,
+	}
+}
+
+// Redeem applies a discount code to a request
+// POST /api/v1/requests/:id/discounts
+func (h *PromotionHandler) Redeem(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondPromotionError)
+	if !ok {
+		return
+	}
+
+	var req domain.RedeemCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	discount, err := h.promotionService.Redeem(c.Request.Context(), getUserIDFromContext(c), requestID, req.Code)
+	if err != nil {
+		respondPromotionError(c, "redeem_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Discount applied",
+		"data":    discount,
+	})
+}
+
+// ListForRequest returns the discounts applied to a request
+// GET /api/v1/requests/:id/discounts
+func (h *PromotionHandler) ListForRequest(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondPromotionError)
+	if !ok {
+		return
+	}
+
+	discounts, err := h.promotionService.ListForRequest(c.Request.Context(), requestID)
+	if err != nil {
+		respondPromotionError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": discounts})
+}
+
+// ListCodes returns every discount code (admin only)
+// GET /api/v1/admin/promotions/codes
+func (h *PromotionHandler) ListCodes(c *gin.Context) {
+	codes, err := h.promotionService.ListCodes(c.Request.Context())
+	if err != nil {
+		respondPromotionError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": codes})
+}
+
+// CreateCode creates a discount code (admin only)
+// POST /api/v1/admin/promotions/codes
+func (h *PromotionHandler) CreateCode(c *gin.Context) {
+	var req domain.CreateDiscountCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	code, err := h.promotionService.CreateCode(c.Request.Context(), getUserIDFromContext(c), &req)
+	if err != nil {
+		respondPromotionError(c, "create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Discount code created",
+		"data":    code,
+	})
+}
+
+// UpdateCode changes a code's limits or deactivates it (admin only)
+// PATCH /api/v1/admin/promotions/codes/:codeId
+func (h *PromotionHandler) UpdateCode(c *gin.Context) {
+	codeID, err := uuid.Parse(c.Param("codeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid discount code ID"})
+		return
+	}
+
+	var req domain.UpdateDiscountCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	code, err := h.promotionService.UpdateCode(c.Request.Context(), codeID, &req)
+	if err != nil {
+		respondPromotionError(c, "update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Discount code updated",
+		"data":    code,
+	})
+}
+
+// GrantWaiver waives part or all of a request's fee (admin only)
+// POST /api/v1/admin/requests/:id/waivers
+func (h *PromotionHandler) GrantWaiver(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req domain.GrantWaiverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	discount, err := h.promotionService.GrantWaiver(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondPromotionError(c, "waiver_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Waiver granted",
+		"data":    discount,
+	})
+}
+
+// RemoveDiscount takes a code or waiver off a request (admin only)
+// DELETE /api/v1/admin/requests/:id/discounts/:discountId
+func (h *PromotionHandler) RemoveDiscount(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+	discountID, err := uuid.Parse(c.Param("discountId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid discount ID"})
+		return
+	}
+
+	if err := h.promotionService.RemoveDiscount(c.Request.Context(), requestID, discountID); err != nil {
+		respondPromotionError(c, "remove_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Discount removed"})
+}
+
+// Report totals the discounts given in a period (admin only)
+// GET /api/v1/admin/promotions/report?from=2026-04-01&to=2027-04-01
+func (h *PromotionHandler) Report(c *gin.Context) {
+	var from, to *time.Time
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &from}, {"to", &to}} {
+		s := c.Query(p.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid_date",
+				"message": p.name + " must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		*p.dst = &t
+	}
+
+	report, err := h.promotionService.Report(c.Request.Context(), from, to)
+	if err != nil {
+		respondPromotionError(c, "report_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
+func respondPromotionError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrDiscountCodeNotFound),
+		errors.Is(err, domain.ErrDiscountNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidDiscount), errors.Is(err, domain.ErrDiscountNotApplicable):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrDiscountCodeExists), errors.Is(err, domain.ErrPriceLocked),
+		errors.Is(err, domain.ErrVersionConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
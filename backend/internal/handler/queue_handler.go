@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// QueueHandler exposes the build queue order
+type QueueHandler struct {
+	queueService   domain.QueueService
+	requestService domain.BuildRequestService
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(queueService domain.QueueService, requestService domain.BuildRequestService) *QueueHandler {
+	return &QueueHandler{
+		queueService:   queueService,
+		requestService: requestService,
+	}
+}
+
+// Position returns where a queued request stands and the weekend it is
+// likely to be built on
+// GET /api/v1/requests/:id/queue-position
+func (h *QueueHandler) Position(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondQueueError)
+	if !ok {
+		return
+	}
+
+	pos, err := h.queueService.Position(c.Request.Context(), requestID)
+	if err != nil {
+		respondQueueError(c, "not_queued", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": pos})
+}
+
+// List returns the whole queue in build order (admin only)
+// GET /api/v1/admin/queue
+func (h *QueueHandler) List(c *gin.Context) {
+	entries, err := h.queueService.List(c.Request.Context())
+	if err != nil {
+		respondQueueError(c, "list_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+// SetGraduationYear records when a student graduates, for the final-year
+// boost (admin only)
+// PUT /api/v1/admin/users/:id/graduation-year
+func (h *QueueHandler) SetGraduationYear(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req domain.SetGraduationYearRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := h.queueService.SetGraduationYear(c.Request.Context(), userID, req.GraduationYear); err != nil {
+		respondQueueError(c, "update_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Graduation year saved",
+		"data":    req,
+	})
+}
+
+func respondQueueError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrNotQueued):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
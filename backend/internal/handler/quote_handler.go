@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// QuoteHandler handles the quote-and-approve workflow for paid requests
+type QuoteHandler struct {
+	quoteService   domain.QuoteService
+	requestService domain.BuildRequestService
+}
+
+// NewQuoteHandler creates a new quote handler
+func NewQuoteHandler(quoteService domain.QuoteService, requestService domain.BuildRequestService) *QuoteHandler {
+	return &QuoteHandler{
+		quoteService:   quoteService,
+		requestService: requestService,
+	}
+}
+
+// ListForRequest returns the quote history of a request. Students only see
+// quotes that were sent to them; staff also see drafts.
+// GET /api/v1/requests/:id/quotes
+// GET /api/v1/admin/requests/:id/quotes
+func (h *QuoteHandler) ListForRequest(c *gin.Context) {
+	requestID, ok := authorizeRequest(c, h.requestService, respondQuoteError)
+	if !ok {
+		return
+	}
+
+	quotes, err := h.quoteService.ListForRequest(c.Request.Context(), requestID, isStaff(c))
+	if err != nil {
+		respondQuoteError(c, "list_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": quotes})
+}
+
+// Create drafts a new quote revision for a paid request (builder/admin)
+// POST /api/v1/admin/requests/:id/quotes
+func (h *QuoteHandler) Create(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+
+	var req domain.CreateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	quote, err := h.quoteService.Create(c.Request.Context(), getUserIDFromContext(c), requestID, &req)
+	if err != nil {
+		respondQuoteError(c, "create_failed", err)
+		return
+	}
+
+	message := "Quote drafted"
+	if quote.Status == domain.QuoteSent {
+		message = "Quote sent to the student"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+		"data":    quote,
+	})
+}
+
+// Send releases a draft quote to the student (builder/admin)
+// POST /api/v1/admin/quotes/:quoteId/send
+func (h *QuoteHandler) Send(c *gin.Context) {
+	quoteID, err := uuid.Parse(c.Param("quoteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quote ID"})
+		return
+	}
+
+	quote, err := h.quoteService.Send(c.Request.Context(), quoteID)
+	if err != nil {
+		respondQuoteError(c, "send_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Quote sent to the student",
+		"data":    quote,
+	})
+}
+
+// Accept records the student's acceptance of a quote
+// POST /api/v1/requests/:id/quotes/:quoteId/accept
+func (h *QuoteHandler) Accept(c *gin.Context) {
+	h.respond(c, "accept_failed", "Quote accepted — your request can now be scheduled", h.quoteService.Accept)
+}
+
+// Decline records the student declining a quote
+// POST /api/v1/requests/:id/quotes/:quoteId/decline
+func (h *QuoteHandler) Decline(c *gin.Context) {
+	h.respond(c, "decline_failed", "Quote declined — the builder will follow up", h.quoteService.Decline)
+}
+
+type quoteResponder func(ctx context.Context, studentID, requestID, quoteID uuid.UUID, req *domain.RespondQuoteRequest) (*domain.Quote, error)
+
+func (h *QuoteHandler) respond(c *gin.Context, code, message string, action quoteResponder) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return
+	}
+	quoteID, err := uuid.Parse(c.Param("quoteId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid quote ID"})
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req domain.RespondQuoteRequest
+	_ = c.ShouldBindJSON(&req)
+
+	quote, err := action(c.Request.Context(), getUserIDFromContext(c), requestID, quoteID, &req)
+	if err != nil {
+		respondQuoteError(c, code, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    quote,
+	})
+}
+
+func respondQuoteError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrRequestNotFound), errors.Is(err, domain.ErrQuoteNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrInvalidQuote), errors.Is(err, domain.ErrQuoteNotRequired):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrQuoteNotOpen), errors.Is(err, domain.ErrQuoteExpired),
+		errors.Is(err, domain.ErrVersionConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
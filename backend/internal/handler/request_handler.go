@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -39,8 +43,18 @@ func (h *RequestHandler) Create(c *gin.Context) {
 
 	buildReq, err := h.requestService.Create(c.Request.Context(), userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "create_failed",
+		status := http.StatusInternalServerError
+		code := "create_failed"
+		switch {
+		case errors.Is(err, domain.ErrUnknownAddOn), errors.Is(err, domain.ErrInvalidDomain):
+			status = http.StatusBadRequest
+		case errors.Is(err, domain.ErrQuotaExceeded):
+			status, code = http.StatusForbidden, "quota_exceeded"
+		case errors.Is(err, domain.ErrDuplicateRequest):
+			status, code = http.StatusConflict, "duplicate_request"
+		}
+		c.JSON(status, gin.H{
+			"error":   code,
 			"message": err.Error(),
 		})
 		return
@@ -76,11 +90,12 @@ func (h *RequestHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, req.Version)
 	c.JSON(http.StatusOK, gin.H{"data": req})
 }
 
 // ListMyRequests returns the authenticated user's requests
-// GET /api/v1/requests
+// GET /api/v1/requests?limit=20&cursor=...
 func (h *RequestHandler) ListMyRequests(c *gin.Context) {
 	userID := getUserIDFromContext(c)
 	if userID == uuid.Nil {
@@ -88,10 +103,22 @@ func (h *RequestHandler) ListMyRequests(c *gin.Context) {
 		return
 	}
 
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page, err := parsePageParams(c, domain.DefaultPageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_pagination",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	requests, total, err := h.requestService.ListByUser(c.Request.Context(), userID, limit, offset)
+	filter := domain.RequestFilter{
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+		After:        page.After,
+		IncludeTotal: page.IncludeTotal,
+	}
+	requests, total, err := h.requestService.ListByUser(c.Request.Context(), userID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "list_failed",
@@ -100,12 +127,7 @@ func (h *RequestHandler) ListMyRequests(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   requests,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
-	})
+	respondPage(c, requests, total, page, requestNextCursor(requests, filter))
 }
 
 // Update handles updating a build request (admin only)
@@ -117,6 +139,11 @@ func (h *RequestHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
 	var req domain.UpdateBuildRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -125,43 +152,125 @@ func (h *RequestHandler) Update(c *gin.Context) {
 		})
 		return
 	}
+	req.IfVersion = &version
 
 	updated, err := h.requestService.Update(c.Request.Context(), id, &req)
+	if errors.Is(err, domain.ErrVersionConflict) {
+		// Hand back what is there now so the client can merge and retry
+		current, findErr := h.requestService.GetByID(c.Request.Context(), id)
+		if findErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found", "message": findErr.Error()})
+			return
+		}
+		setETag(c, current.Version)
+		c.JSON(http.StatusPreconditionFailed, gin.H{
+			"error":   "precondition_failed",
+			"message": err.Error(),
+			"data":    current,
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrRequestNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrUnknownAddOn):
+			status = http.StatusBadRequest
+		case errors.Is(err, domain.ErrQuoteNotAccepted), errors.Is(err, domain.ErrPaymentRequired),
+			errors.Is(err, domain.ErrStatusDerived), errors.Is(err, domain.ErrAcceptanceRequired),
+			errors.Is(err, domain.ErrDomainNotVerified), errors.Is(err, domain.ErrWeekendDerived),
+			errors.Is(err, domain.ErrScheduleDerived), errors.Is(err, domain.ErrBuilderUnavailable):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
 			"error":   "update_failed",
 			"message": err.Error(),
 		})
 		return
 	}
 
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Request updated",
 		"data":    updated,
 	})
 }
 
-// ListAll returns all requests (admin only)
-// GET /api/v1/admin/requests
-func (h *RequestHandler) ListAll(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+// BulkUpdate applies one operation to many requests (admin only). Atomic runs
+// change nothing unless every request passes; best-effort runs report each.
+// POST /api/v1/admin/requests/bulk
+func (h *RequestHandler) BulkUpdate(c *gin.Context) {
+	var req domain.BulkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	filter := domain.RequestFilter{
-		Limit:  limit,
-		Offset: offset,
+	result, err := h.requestService.BulkUpdate(c.Request.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrInvalidBulkOperation), errors.Is(err, domain.ErrInvalidBuilder):
+			status = http.StatusBadRequest
+		case errors.Is(err, domain.ErrUserNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"error":   "bulk_update_failed",
+			"message": err.Error(),
+		})
+		return
 	}
 
-	// Optional status filter
-	if statusStr := c.Query("status"); statusStr != "" {
-		status := domain.RequestStatus(statusStr)
-		filter.Status = &status
+	if !result.Applied {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "bulk_rejected",
+			"message": domain.ErrBulkRejected.Error(),
+			"data":    result,
+		})
+		return
 	}
 
-	// Optional type filter
-	if typeStr := c.Query("type"); typeStr != "" {
-		reqType := domain.RequestType(typeStr)
-		filter.RequestType = &reqType
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Updated %d of %d requests", result.Succeeded, result.Matched),
+		"data":    result,
+	})
+}
+
+// ListAll returns all requests (admin only)
+// GET /api/v1/admin/requests?q=drama+club&status=pending,queued&sort=created_at&order=asc&cursor=...
+func (h *RequestHandler) ListAll(c *gin.Context) {
+	page, err := parsePageParams(c, 50)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_pagination",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter, err := parseRequestFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_filter",
+			"message": err.Error(),
+		})
+		return
+	}
+	filter.Limit = page.Limit
+	filter.Offset = page.Offset
+	filter.After = page.After
+	filter.IncludeTotal = page.IncludeTotal
+	if filter.After != nil && !filter.SupportsCursor() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_pagination",
+			"message": "cursor pagination requires ordering by created_at; use offset for other sort orders",
+		})
+		return
 	}
 
 	requests, total, err := h.requestService.ListAll(c.Request.Context(), filter)
@@ -173,14 +282,155 @@ func (h *RequestHandler) ListAll(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":   requests,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+	respondPage(c, requests, total, page, requestNextCursor(requests, filter))
+}
+
+// requestNextCursor returns the cursor for the next page of requests, if the
+// listing is ordered in a way keyset pagination can follow
+func requestNextCursor(requests []domain.BuildRequest, filter domain.RequestFilter) *string {
+	if !filter.SupportsCursor() {
+		return nil
+	}
+	return nextCursor(len(requests), filter.Limit, func() domain.Cursor {
+		last := requests[len(requests)-1]
+		return domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
 	})
 }
 
+// parseRequestFilter reads the admin listing query parameters:
+//
+//	q                             free-text search (title, description, tech requirements)
+//	status                        one or more statuses, comma separated
+//	type, hosting_type, complexity exact matches
+//	builder_id, is_free           exact matches
+//	label                         requests carrying the label
+//	created_from, created_to      YYYY-MM-DD, both inclusive
+//	scheduled_from, scheduled_to  YYYY-MM-DD, both inclusive
+//	sort, order                   sort field and asc|desc
+//
+// Pagination parameters are handled separately by parsePageParams.
+func parseRequestFilter(c *gin.Context) (domain.RequestFilter, error) {
+	filter := domain.RequestFilter{
+		Query: strings.TrimSpace(c.Query("q")),
+	}
+
+	// Optional status filter (single or comma-separated list)
+	if statusStr := c.Query("status"); statusStr != "" {
+		for _, part := range strings.Split(statusStr, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				status := domain.RequestStatus(part)
+				if !status.IsValid() {
+					return filter, fmt.Errorf("unknown status %q", part)
+				}
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+
+	// Optional type filter
+	if typeStr := c.Query("type"); typeStr != "" {
+		reqType := domain.RequestType(typeStr)
+		if !reqType.IsValid() {
+			return filter, fmt.Errorf("unknown type %q", typeStr)
+		}
+		filter.RequestType = &reqType
+	}
+
+	if hostingStr := c.Query("hosting_type"); hostingStr != "" {
+		hosting := domain.HostingType(hostingStr)
+		if !hosting.IsValid() {
+			return filter, fmt.Errorf("unknown hosting_type %q", hostingStr)
+		}
+		filter.HostingType = &hosting
+	}
+
+	if complexityStr := c.Query("complexity"); complexityStr != "" {
+		complexity := domain.ComplexityLevel(complexityStr)
+		if !complexity.IsValid() {
+			return filter, fmt.Errorf("unknown complexity %q", complexityStr)
+		}
+		filter.Complexity = &complexity
+	}
+
+	if builderStr := c.Query("builder_id"); builderStr != "" {
+		builderID, err := uuid.Parse(builderStr)
+		if err != nil {
+			return filter, errors.New("builder_id must be a valid UUID")
+		}
+		filter.BuilderID = &builderID
+	}
+
+	if freeStr := c.Query("is_free"); freeStr != "" {
+		isFree, err := strconv.ParseBool(freeStr)
+		if err != nil {
+			return filter, errors.New("is_free must be true or false")
+		}
+		filter.IsFree = &isFree
+	}
+
+	filter.Label = domain.NormalizeLabel(c.Query("label"))
+
+	dateRanges := []struct {
+		fromKey, toKey string
+		from, to       **time.Time
+	}{
+		{"created_from", "created_to", &filter.CreatedFrom, &filter.CreatedTo},
+		{"scheduled_from", "scheduled_to", &filter.ScheduledFrom, &filter.ScheduledTo},
+	}
+	for _, r := range dateRanges {
+		if v := c.Query(r.fromKey); v != "" {
+			from, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be in YYYY-MM-DD format", r.fromKey)
+			}
+			*r.from = &from
+		}
+		if v := c.Query(r.toKey); v != "" {
+			to, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be in YYYY-MM-DD format", r.toKey)
+			}
+			// Make the end date inclusive
+			to = to.AddDate(0, 0, 1)
+			*r.to = &to
+		}
+	}
+
+	// Optional ordering; descending unless order=asc
+	sortStr, order := c.Query("sort"), strings.ToLower(c.Query("order"))
+	if order != "" && order != "asc" && order != "desc" {
+		return filter, errors.New("order must be asc or desc")
+	}
+	if sortStr != "" || order != "" {
+		sortBy := domain.RequestSortField(sortStr)
+		switch {
+		case sortStr == "" && filter.Query != "":
+			sortBy = domain.SortByRelevance
+		case sortStr == "":
+			sortBy = domain.SortByCreatedAt
+		case !sortBy.IsValid():
+			return filter, fmt.Errorf("unsupported sort field %q", sortStr)
+		}
+		filter.SortBy = sortBy
+		filter.SortDesc = order != "asc"
+	}
+
+	return filter, nil
+}
+
+// Helper to extract the caller's role from gin context
+func getRoleFromContext(c *gin.Context) domain.Role {
+	role, _ := c.Get("role")
+	r, _ := role.(domain.Role)
+	return r
+}
+
+// isStaff reports whether the caller is an admin or builder
+func isStaff(c *gin.Context) bool {
+	role := getRoleFromContext(c)
+	return role == domain.RoleAdmin || role == domain.RoleBuilder
+}
+
 // Helper to extract user ID from gin context
 func getUserIDFromContext(c *gin.Context) uuid.UUID {
 	userIDStr, exists := c.Get("userID")
@@ -193,3 +443,36 @@ func getUserIDFromContext(c *gin.Context) uuid.UUID {
 	}
 	return id
 }
+
+// authorizeRequest parses :id and checks the caller owns the request or is
+// staff. Failures are written with the calling handler's respond function.
+func authorizeRequest(c *gin.Context, requestService domain.BuildRequestService,
+	respond func(c *gin.Context, code string, err error)) (uuid.UUID, bool) {
+	req, ok := loadAuthorizedRequest(c, requestService, respond)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return req.ID, true
+}
+
+// loadAuthorizedRequest is authorizeRequest for handlers that also need the
+// request itself.
+func loadAuthorizedRequest(c *gin.Context, requestService domain.BuildRequestService,
+	respond func(c *gin.Context, code string, err error)) (*domain.BuildRequest, bool) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request ID"})
+		return nil, false
+	}
+
+	req, err := requestService.GetByID(c.Request.Context(), requestID)
+	if err != nil {
+		respond(c, "not_found", err)
+		return nil, false
+	}
+	if !isStaff(c) && req.UserID != getUserIDFromContext(c) {
+		respond(c, "forbidden", domain.ErrForbidden)
+		return nil, false
+	}
+	return req, true
+}
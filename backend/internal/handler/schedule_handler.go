@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/makeitexist/backend/internal/domain"
 )
 
@@ -32,7 +34,7 @@ func (h *ScheduleHandler) GetUpcomingSlots(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":    slots,
-		"message": "Weekend build slots (Saturday & Sunday, 8 hours each)",
+		"message": "Weekend build slots (Saturday & Sunday), sized by builder availability",
 	})
 }
 
@@ -64,6 +66,7 @@ func (h *ScheduleHandler) GetScheduleForWeekend(c *gin.Context) {
 		return
 	}
 
+	setETag(c, view.Slot.Version)
 	c.JSON(http.StatusOK, gin.H{"data": view})
 }
 
@@ -82,3 +85,173 @@ func (h *ScheduleHandler) GenerateSlots(c *gin.Context) {
 		"message": "Weekend slots generated for the next 8 weeks",
 	})
 }
+
+// CreateEntry books a request into a slot, moving it out of any slot it held
+// (admin only)
+// POST /api/v1/admin/schedule/entries
+func (h *ScheduleHandler) CreateEntry(c *gin.Context) {
+	var req domain.CreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.scheduleService.ScheduleRequest(c.Request.Context(), req.RequestID, req.SlotID, req.Hours)
+	if err != nil {
+		respondScheduleError(c, "schedule_failed", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Request scheduled",
+		"data":    entry,
+	})
+}
+
+// MoveEntry moves a booking to another slot, or changes its hours (admin only)
+// PUT /api/v1/admin/schedule/entries/:id
+func (h *ScheduleHandler) MoveEntry(c *gin.Context) {
+	id, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+	var req domain.MoveEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.scheduleService.Reschedule(c.Request.Context(), id, req.SlotID, req.Hours)
+	if err != nil {
+		respondScheduleError(c, "reschedule_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Request rescheduled",
+		"data":    entry,
+	})
+}
+
+// DeleteEntry releases a booking and puts the request back in the queue
+// (admin only)
+// DELETE /api/v1/admin/schedule/entries/:id
+func (h *ScheduleHandler) DeleteEntry(c *gin.Context) {
+	id, ok := parseEntryID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.scheduleService.Unschedule(c.Request.Context(), id)
+	if err != nil {
+		respondScheduleError(c, "unschedule_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Request unscheduled",
+		"data":    entry,
+	})
+}
+
+// CreateStandby puts a request on the waitlist of a full slot, or of the next
+// available one when slot_id is omitted (admin only)
+// POST /api/v1/admin/schedule/standby
+func (h *ScheduleHandler) CreateStandby(c *gin.Context) {
+	var req domain.CreateStandbyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "validation_error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.scheduleService.Standby(c.Request.Context(), &req)
+	if err != nil {
+		respondScheduleError(c, "standby_failed", err)
+		return
+	}
+
+	message := "Request is on standby"
+	if entry.Status == domain.StandbyPromoted {
+		message = "A slot had room, so the request was booked straight away"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+		"data":    entry,
+	})
+}
+
+// ListStandby returns the waiting standby entries, oldest first (admin only)
+// GET /api/v1/admin/schedule/standby?slot_id=
+func (h *ScheduleHandler) ListStandby(c *gin.Context) {
+	var slotID *uuid.UUID
+	if raw := c.Query("slot_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot ID"})
+			return
+		}
+		slotID = &id
+	}
+
+	entries, err := h.scheduleService.ListStandby(c.Request.Context(), slotID)
+	if err != nil {
+		respondScheduleError(c, "fetch_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+// CancelStandby takes a request off the waitlist (admin only)
+// DELETE /api/v1/admin/schedule/standby/:id
+func (h *ScheduleHandler) CancelStandby(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid standby ID"})
+		return
+	}
+
+	entry, err := h.scheduleService.CancelStandby(c.Request.Context(), id)
+	if err != nil {
+		respondScheduleError(c, "cancel_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Standby cancelled",
+		"data":    entry,
+	})
+}
+
+func parseEntryID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondScheduleError(c *gin.Context, code string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, domain.ErrSlotNotFound), errors.Is(err, domain.ErrRequestNotFound),
+		errors.Is(err, domain.ErrStandbyNotFound), errors.Is(err, domain.ErrEntryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrSlotFull), errors.Is(err, domain.ErrEntryReleased),
+		errors.Is(err, domain.ErrAlreadyOnStandby), errors.Is(err, domain.ErrStandbyNotWaiting),
+		errors.Is(err, domain.ErrRequestNotSchedulable),
+		errors.Is(err, domain.ErrQuoteNotAccepted), errors.Is(err, domain.ErrPaymentRequired),
+		errors.Is(err, domain.ErrDomainNotVerified), errors.Is(err, domain.ErrVersionConflict),
+		errors.Is(err, domain.ErrBuilderUnavailable):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"error":   code,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,85 @@
+package invoice
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Seller is the supplier block printed on every document
+type Seller struct {
+	Name    string
+	Address string
+	GSTIN   string
+	State   string
+}
+
+type pdfRenderer struct {
+	seller    Seller
+	gstRate   int
+	templates *template.Template
+}
+
+// NewPDFRenderer creates a renderer that fills the embedded invoice and
+// receipt templates and lays them out as PDF
+func NewPDFRenderer(cfg *config.Config) (domain.InvoiceRenderer, error) {
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"money": func(v domain.Money, width int) string { return fmt.Sprintf("%*s", width, v.Decimal()) },
+		"pad":   func(s string, width int) string { return fmt.Sprintf("%-*.*s", width, width, s) },
+		"date":  func(t time.Time) string { return t.Format("02 Jan 2006") },
+		"half":  func(rate int) string { return fmt.Sprintf("%g", float64(rate)/2) },
+		"rule":  func(width int) string { return strings.Repeat("-", width) },
+	}).ParseFS(templateFS, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse invoice templates: %w", err)
+	}
+
+	return &pdfRenderer{
+		seller: Seller{
+			Name:    cfg.Invoice.SellerName,
+			Address: cfg.Invoice.SellerAddress,
+			GSTIN:   cfg.Invoice.SellerGSTIN,
+			State:   cfg.Invoice.SellerState,
+		},
+		gstRate:   cfg.Invoice.GSTRate,
+		templates: tmpl,
+	}, nil
+}
+
+func (r *pdfRenderer) Render(inv *domain.Invoice) (*domain.Artifact, error) {
+	var text bytes.Buffer
+	err := r.templates.ExecuteTemplate(&text, string(inv.Kind)+".tmpl", struct {
+		Seller  Seller
+		Invoice *domain.Invoice
+		GSTRate int
+	}{r.seller, inv, r.gstRate})
+	if err != nil {
+		return nil, err
+	}
+
+	content := writePDF(strings.Split(strings.TrimRight(text.String(), "\n"), "\n"))
+	sum := sha256.Sum256(content)
+	return &domain.Artifact{
+		ID:          uuid.New(),
+		RequestID:   inv.RequestID,
+		Kind:        string(inv.Kind),
+		Filename:    strings.NewReplacer("/", "-").Replace(inv.Number) + ".pdf",
+		ContentType: "application/pdf",
+		Size:        int64(len(content)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		Content:     content,
+		CreatedAt:   time.Now(),
+	}, nil
+}
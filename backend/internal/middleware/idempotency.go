@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// responseRecorder keeps a copy of the response while writing it through
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response when an authenticated
+// POST is retried with the same Idempotency-Key within ttl. Reusing a key
+// with a different body is rejected with 422; a retry that arrives while the
+// first attempt is still running gets 409. Server errors are not stored, so
+// those can be retried. Must run after AuthMiddleware.
+func IdempotencyMiddleware(repo domain.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(domain.IdempotencyKeyHeader)
+		if key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(key) > domain.MaxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "validation_error",
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+			return
+		}
+		userID, err := uuid.Parse(c.GetString("userID"))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "validation_error",
+				"message": "could not read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		path := c.Request.URL.RequestURI()
+		sum := sha256.New()
+		sum.Write([]byte(c.Request.Method + " " + path + "\n"))
+		sum.Write(body)
+
+		now := time.Now()
+		rec := &domain.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        path,
+			Fingerprint: hex.EncodeToString(sum.Sum(nil)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		ctx := c.Request.Context()
+		existing, err := repo.Reserve(ctx, rec)
+		if err != nil {
+			// Without the store, serve the request rather than fail it
+			log.Warn().Err(err).Msg("Failed to reserve idempotency key")
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != rec.Fingerprint:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "idempotency_key_reused",
+					"message": "this Idempotency-Key was already used for a different request",
+				})
+			case !existing.Completed():
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error":   "idempotency_key_in_use",
+					"message": "a request with this Idempotency-Key is still being processed; retry shortly",
+				})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The response has gone out; store it even if the client went away
+		ctx = context.WithoutCancel(ctx)
+		if recorder.Status() >= http.StatusInternalServerError {
+			if err := repo.Release(ctx, userID, key); err != nil {
+				log.Warn().Err(err).Msg("Failed to release idempotency key")
+			}
+			return
+		}
+		rec.StatusCode = recorder.Status()
+		rec.ContentType = recorder.Header().Get("Content-Type")
+		rec.ResponseBody = recorder.body.Bytes()
+		if err := repo.Complete(ctx, rec); err != nil {
+			log.Warn().Err(err).Msg("Failed to store idempotent response")
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package payment
+
+import (
+	"fmt"
+
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// NewProvider returns the payment provider selected by PAYMENT_PROVIDER
+func NewProvider(cfg *config.Config) (domain.PaymentProvider, error) {
+	switch cfg.Payment.Provider {
+	case "razorpay":
+		return NewRazorpayProvider(cfg.Payment.RazorpayKeyID, cfg.Payment.RazorpayKeySecret, cfg.Payment.WebhookSecret), nil
+	case "fake":
+		return NewFakeProvider(cfg.Payment.WebhookSecret), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", cfg.Payment.Provider)
+}
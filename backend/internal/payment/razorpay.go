@@ -0,0 +1,198 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// ErrInvalidSignature is returned when a webhook signature does not verify
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+const razorpayBaseURL = "https://api.razorpay.com/v1"
+
+type razorpayProvider struct {
+	keyID         string
+	keySecret     string
+	webhookSecret string
+	baseURL       string
+	client        *http.Client
+}
+
+// NewRazorpayProvider creates a Razorpay payment provider
+func NewRazorpayProvider(keyID, keySecret, webhookSecret string) domain.PaymentProvider {
+	return &razorpayProvider{
+		keyID:         keyID,
+		keySecret:     keySecret,
+		webhookSecret: webhookSecret,
+		baseURL:       razorpayBaseURL,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *razorpayProvider) Name() string { return "razorpay" }
+
+func (p *razorpayProvider) CreateOrder(ctx context.Context, intent *domain.PaymentIntent) (string, error) {
+	body := map[string]interface{}{
+		"amount":          intent.Amount.Minor,
+		"currency":        intent.Currency,
+		"receipt":         intent.ID.String(),
+		"partial_payment": true,
+		"notes": map[string]string{
+			"request_id": intent.RequestID.String(),
+			"quote_id":   intent.QuoteID.String(),
+		},
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := p.post(ctx, "/orders", body, &resp); err != nil {
+		return "", fmt.Errorf("razorpay: create order: %w", err)
+	}
+	return resp.ID, nil
+}
+
+func (p *razorpayProvider) Refund(ctx context.Context, paymentID string, amount domain.Money) (string, error) {
+	body := map[string]interface{}{"amount": amount.Minor}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := p.post(ctx, "/payments/"+paymentID+"/refund", body, &resp); err != nil {
+		return "", fmt.Errorf("razorpay: refund: %w", err)
+	}
+	return resp.ID, nil
+}
+
+func (p *razorpayProvider) ParseWebhook(signature string, body []byte) ([]domain.PaymentEvent, error) {
+	if !verifySignature(p.webhookSecret, signature, body) {
+		return nil, ErrInvalidSignature
+	}
+	return decodeRazorpayEvents(body)
+}
+
+func (p *razorpayProvider) post(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.keyID, p.keySecret)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Description string `json:"description"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(raw, &apiErr)
+		return fmt.Errorf("status %d: %s", resp.StatusCode, apiErr.Error.Description)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// verifySignature checks a hex HMAC-SHA256 of the raw body, as Razorpay
+// sends in the X-Razorpay-Signature header
+func verifySignature(secret, signature string, body []byte) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// razorpayWebhook is the subset of the Razorpay webhook envelope we consume
+type razorpayWebhook struct {
+	Event   string `json:"event"`
+	Payload struct {
+		Payment *struct {
+			Entity struct {
+				ID        string `json:"id"`
+				OrderID   string `json:"order_id"`
+				Amount    int64  `json:"amount"`
+				Currency  string `json:"currency"`
+				CreatedAt int64  `json:"created_at"`
+			} `json:"entity"`
+		} `json:"payment"`
+		Refund *struct {
+			Entity struct {
+				ID        string `json:"id"`
+				PaymentID string `json:"payment_id"`
+				Amount    int64  `json:"amount"`
+				Currency  string `json:"currency"`
+				CreatedAt int64  `json:"created_at"`
+			} `json:"entity"`
+		} `json:"refund"`
+	} `json:"payload"`
+}
+
+// decodeRazorpayEvents maps captured payments and processed refunds to
+// payment events; other event types are ignored
+func decodeRazorpayEvents(body []byte) ([]domain.PaymentEvent, error) {
+	var hook razorpayWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	switch hook.Event {
+	case "payment.captured":
+		if hook.Payload.Payment == nil {
+			return nil, errors.New("payment.captured without payment entity")
+		}
+		e := hook.Payload.Payment.Entity
+		return []domain.PaymentEvent{{
+			Kind:       domain.LedgerPayment,
+			OrderID:    e.OrderID,
+			PaymentID:  e.ID,
+			Amount:     domain.NewMoney(e.Amount, e.Currency),
+			Currency:   e.Currency,
+			OccurredAt: unixOrNow(e.CreatedAt),
+		}}, nil
+	case "refund.processed":
+		if hook.Payload.Refund == nil {
+			return nil, errors.New("refund.processed without refund entity")
+		}
+		e := hook.Payload.Refund.Entity
+		return []domain.PaymentEvent{{
+			Kind:       domain.LedgerRefund,
+			PaymentID:  e.PaymentID,
+			RefundID:   e.ID,
+			Amount:     domain.NewMoney(e.Amount, e.Currency),
+			Currency:   e.Currency,
+			OccurredAt: unixOrNow(e.CreatedAt),
+		}}, nil
+	}
+	return nil, nil
+}
+
+func unixOrNow(ts int64) time.Time {
+	if ts == 0 {
+		return time.Now()
+	}
+	return time.Unix(ts, 0)
+}
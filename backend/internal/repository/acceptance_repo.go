@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type acceptanceRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAcceptanceRepository creates a new acceptance repository
+func NewAcceptanceRepository(db *pgxpool.Pool) domain.AcceptanceRepository {
+	return &acceptanceRepo{db: db}
+}
+
+func (r *acceptanceRepo) AddReview(ctx context.Context, rv *domain.DeliveryReview) error {
+	query := `
+		INSERT INTO delivery_reviews (id, request_id, round, outcome, notes, reviewed_by, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		rv.ID, rv.RequestID, rv.Round, rv.Outcome, rv.Notes, rv.ReviewedBy, rv.CreatedAt,
+	)
+	return err
+}
+
+func (r *acceptanceRepo) ListReviews(ctx context.Context, requestID uuid.UUID) ([]domain.DeliveryReview, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT id, request_id, round, outcome, COALESCE(notes, ''), reviewed_by, created_at
+		FROM delivery_reviews WHERE request_id = $1 ORDER BY round
+	`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.DeliveryReview
+	for rows.Next() {
+		var rv domain.DeliveryReview
+		if err := rows.Scan(
+			&rv.ID, &rv.RequestID, &rv.Round, &rv.Outcome, &rv.Notes, &rv.ReviewedBy, &rv.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *acceptanceRepo) UpsertRating(ctx context.Context, rt *domain.Rating) error {
+	query := `
+		INSERT INTO ratings (id, request_id, user_id, builder_id, builder_score, request_score, feedback,
+		       created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+		ON CONFLICT (request_id) DO UPDATE
+		SET builder_score = EXCLUDED.builder_score, request_score = EXCLUDED.request_score,
+		    feedback = EXCLUDED.feedback, builder_id = EXCLUDED.builder_id, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	return conn(ctx, r.db).QueryRow(ctx, query,
+		rt.ID, rt.RequestID, rt.UserID, rt.BuilderID, rt.BuilderScore, rt.RequestScore, rt.Feedback,
+		rt.CreatedAt, rt.UpdatedAt,
+	).Scan(&rt.ID, &rt.CreatedAt)
+}
+
+func (r *acceptanceRepo) FindRating(ctx context.Context, requestID uuid.UUID) (*domain.Rating, error) {
+	rt := &domain.Rating{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT id, request_id, user_id, builder_id, builder_score, request_score, COALESCE(feedback, ''),
+		       created_at, updated_at
+		FROM ratings WHERE request_id = $1
+	`, requestID).Scan(
+		&rt.ID, &rt.RequestID, &rt.UserID, &rt.BuilderID, &rt.BuilderScore, &rt.RequestScore, &rt.Feedback,
+		&rt.CreatedAt, &rt.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rt, err
+}
+
+func (r *acceptanceRepo) BuilderQuality(ctx context.Context) ([]domain.BuilderQuality, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		WITH reviews AS (
+			SELECT request_id,
+			       COUNT(*) FILTER (WHERE outcome = 'revision_requested') AS revisions,
+			       BOOL_OR(outcome = 'accepted') AS accepted
+			FROM delivery_reviews
+			GROUP BY request_id
+		)
+		SELECT u.id, u.full_name,
+		       COUNT(*) FILTER (WHERE br.status = 'completed'),
+		       COUNT(rt.id),
+		       COALESCE(AVG(rt.builder_score), 0)::float8,
+		       COALESCE(AVG(rt.request_score), 0)::float8,
+		       COALESCE(AVG(rv.revisions) FILTER (WHERE rv.accepted), 0)::float8,
+		       COALESCE(AVG(CASE WHEN rv.revisions = 0 THEN 1 ELSE 0 END) FILTER (WHERE rv.accepted), 0)::float8
+		FROM build_requests br
+		JOIN users u ON u.id = br.builder_id
+		LEFT JOIN ratings rt ON rt.request_id = br.id
+		LEFT JOIN reviews rv ON rv.request_id = br.id
+		GROUP BY u.id, u.full_name
+		ORDER BY 5 DESC, 3 DESC, u.full_name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quality []domain.BuilderQuality
+	for rows.Next() {
+		var q domain.BuilderQuality
+		if err := rows.Scan(
+			&q.BuilderID, &q.Name, &q.Completed, &q.Rated, &q.AvgBuilderScore, &q.AvgRequestScore,
+			&q.AvgRevisions, &q.FirstTimeAcceptance,
+		); err != nil {
+			return nil, err
+		}
+		quality = append(quality, q)
+	}
+	return quality, rows.Err()
+}
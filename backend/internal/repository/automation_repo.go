@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type automationRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAutomationRepository creates a new automation repository
+func NewAutomationRepository(db *pgxpool.Pool) domain.AutomationRepository {
+	return &automationRepo{db: db}
+}
+
+const automationRuleColumns = `id, name, COALESCE(description, ''), enabled, status, days, conditions, action,
+	target_status, COALESCE(label, ''), COALESCE(recipient, ''), COALESCE(message, ''),
+	last_run_at, created_by, created_at, updated_at`
+
+func scanAutomationRule(row pgx.Row) (*domain.AutomationRule, error) {
+	r := &domain.AutomationRule{}
+	err := row.Scan(
+		&r.ID, &r.Name, &r.Description, &r.Enabled, &r.Status, &r.Days, &r.Conditions, &r.Action,
+		&r.TargetStatus, &r.Label, &r.Recipient, &r.Message,
+		&r.LastRunAt, &r.CreatedBy, &r.CreatedAt, &r.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *automationRepo) CreateRule(ctx context.Context, rule *domain.AutomationRule) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO automation_rules (
+			id, name, description, enabled, status, days, conditions, action,
+			target_status, label, recipient, message, created_by, created_at, updated_at
+		) VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), $13, $14, $14)`,
+		rule.ID, rule.Name, rule.Description, rule.Enabled, rule.Status, rule.Days, rule.Conditions, rule.Action,
+		rule.TargetStatus, rule.Label, rule.Recipient, rule.Message, rule.CreatedBy, rule.CreatedAt,
+	)
+	return err
+}
+
+func (r *automationRepo) FindRule(ctx context.Context, id uuid.UUID) (*domain.AutomationRule, error) {
+	rule, err := scanAutomationRule(r.db.QueryRow(ctx,
+		`SELECT `+automationRuleColumns+` FROM automation_rules WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *automationRepo) ListRules(ctx context.Context, enabledOnly bool) ([]domain.AutomationRule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+automationRuleColumns+` FROM automation_rules
+		WHERE enabled OR NOT $1
+		ORDER BY created_at, id`, enabledOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.AutomationRule
+	for rows.Next() {
+		rule, err := scanAutomationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *automationRepo) UpdateRule(ctx context.Context, rule *domain.AutomationRule) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE automation_rules SET
+			name=$1, description=NULLIF($2, ''), enabled=$3, status=$4, days=$5, conditions=$6, action=$7,
+			target_status=$8, label=NULLIF($9, ''), recipient=NULLIF($10, ''), message=NULLIF($11, ''), updated_at=$12
+		WHERE id=$13`,
+		rule.Name, rule.Description, rule.Enabled, rule.Status, rule.Days, rule.Conditions, rule.Action,
+		rule.TargetStatus, rule.Label, rule.Recipient, rule.Message, rule.UpdatedAt, rule.ID,
+	)
+	return err
+}
+
+func (r *automationRepo) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM automation_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAutomationRuleNotFound
+	}
+	return nil
+}
+
+func (r *automationRepo) MarkRun(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE automation_rules SET last_run_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func (r *automationRepo) FindMatches(ctx context.Context, rule *domain.AutomationRule, now time.Time, limit int) ([]domain.AutomationMatch, error) {
+	where, args := buildRequestWhere(rule.RequestFilter(now))
+	args = append(args, rule.ID, limit)
+	query := fmt.Sprintf(`
+		SELECT id, title, status, user_id, builder_id, status_changed_at
+		FROM build_requests %s
+		AND NOT EXISTS (
+			SELECT 1 FROM automation_hits h
+			WHERE h.rule_id = $%d AND h.request_id = build_requests.id
+			  AND h.status_changed_at = build_requests.status_changed_at
+		)
+		ORDER BY status_changed_at, id
+		LIMIT $%d`, where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []domain.AutomationMatch
+	for rows.Next() {
+		var m domain.AutomationMatch
+		if err := rows.Scan(&m.RequestID, &m.Title, &m.Status, &m.UserID, &m.BuilderID, &m.StatusChangedAt); err != nil {
+			return nil, err
+		}
+		m.IdleDays = int(now.Sub(m.StatusChangedAt).Hours() / 24)
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func (r *automationRepo) ClaimHit(ctx context.Context, hit *domain.AutomationHit) (bool, error) {
+	result, err := r.db.Exec(ctx, `
+		INSERT INTO automation_hits (id, rule_id, request_id, status_changed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (rule_id, request_id, status_changed_at) DO NOTHING`,
+		hit.ID, hit.RuleID, hit.RequestID, hit.StatusChangedAt, hit.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *automationRepo) FinishHit(ctx context.Context, hit *domain.AutomationHit) error {
+	_, err := r.db.Exec(ctx, `UPDATE automation_hits SET ok = $1, error = NULLIF($2, '') WHERE id = $3`,
+		hit.OK, hit.Error, hit.ID)
+	return err
+}
+
+func (r *automationRepo) ListHits(ctx context.Context, ruleID uuid.UUID, limit int) ([]domain.AutomationHit, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, rule_id, request_id, status_changed_at, ok, COALESCE(error, ''), created_at
+		FROM automation_hits WHERE rule_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2`, ruleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []domain.AutomationHit
+	for rows.Next() {
+		var h domain.AutomationHit
+		if err := rows.Scan(&h.ID, &h.RuleID, &h.RequestID, &h.StatusChangedAt, &h.OK, &h.Error, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
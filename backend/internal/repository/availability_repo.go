@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type availabilityRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewAvailabilityRepository creates a new builder availability repository
+func NewAvailabilityRepository(db *pgxpool.Pool) domain.AvailabilityRepository {
+	return &availabilityRepo{db: db}
+}
+
+const patternColumns = `id, builder_id, day_of_week, hours, COALESCE(from_time, ''), COALESCE(until_time, ''), valid_from, valid_until, created_at`
+
+func scanPattern(row pgx.Row) (*domain.AvailabilityPattern, error) {
+	p := &domain.AvailabilityPattern{}
+	err := row.Scan(&p.ID, &p.BuilderID, &p.DayOfWeek, &p.Hours, &p.From, &p.Until, &p.ValidFrom, &p.ValidUntil, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *availabilityRepo) CreatePattern(ctx context.Context, p *domain.AvailabilityPattern) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO builder_availability_patterns (id, builder_id, day_of_week, hours, from_time, until_time, valid_from, valid_until, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, $9)`,
+		p.ID, p.BuilderID, p.DayOfWeek, p.Hours, p.From, p.Until, p.ValidFrom, p.ValidUntil, p.CreatedAt,
+	)
+	return err
+}
+
+func (r *availabilityRepo) FindPatternByID(ctx context.Context, id uuid.UUID) (*domain.AvailabilityPattern, error) {
+	p, err := scanPattern(conn(ctx, r.db).QueryRow(ctx, `SELECT `+patternColumns+` FROM builder_availability_patterns WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *availabilityRepo) DeletePattern(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM builder_availability_patterns WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAvailabilityNotFound
+	}
+	return nil
+}
+
+func (r *availabilityRepo) ListPatterns(ctx context.Context, builderID uuid.UUID) ([]domain.AvailabilityPattern, error) {
+	return r.listPatterns(ctx, `WHERE builder_id = $1`, builderID)
+}
+
+func (r *availabilityRepo) ListPatternsOn(ctx context.Context, date time.Time) ([]domain.AvailabilityPattern, error) {
+	return r.listPatterns(ctx, `
+		WHERE day_of_week = $1 AND valid_from <= $2 AND (valid_until IS NULL OR valid_until >= $2)`,
+		strings.ToLower(date.Weekday().String()), date)
+}
+
+func (r *availabilityRepo) listPatterns(ctx context.Context, where string, args ...interface{}) ([]domain.AvailabilityPattern, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT `+patternColumns+` FROM builder_availability_patterns `+where+` ORDER BY valid_from, created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	patterns := []domain.AvailabilityPattern{}
+	for rows.Next() {
+		p, err := scanPattern(rows)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, *p)
+	}
+	return patterns, rows.Err()
+}
+
+func (r *availabilityRepo) UpsertDay(ctx context.Context, d *domain.AvailabilityDay) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO builder_availability_days (builder_id, date, hours, from_time, until_time, note, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+		ON CONFLICT (builder_id, date) DO UPDATE SET
+			hours = EXCLUDED.hours, from_time = EXCLUDED.from_time, until_time = EXCLUDED.until_time,
+			note = EXCLUDED.note, updated_at = EXCLUDED.updated_at`,
+		d.BuilderID, d.Date, d.Hours, d.From, d.Until, d.Note, d.UpdatedAt,
+	)
+	return err
+}
+
+func (r *availabilityRepo) DeleteDay(ctx context.Context, builderID uuid.UUID, date time.Time) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM builder_availability_days WHERE builder_id = $1 AND date = $2`, builderID, date)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrAvailabilityNotFound
+	}
+	return nil
+}
+
+func (r *availabilityRepo) ListDays(ctx context.Context, builderID *uuid.UUID, from, to time.Time) ([]domain.AvailabilityDay, error) {
+	query := `
+		SELECT builder_id, date, hours, COALESCE(from_time, ''), COALESCE(until_time, ''), note, updated_at
+		FROM builder_availability_days WHERE date BETWEEN $1 AND $2`
+	args := []interface{}{from, to}
+	if builderID != nil {
+		query += ` AND builder_id = $3`
+		args = append(args, *builderID)
+	}
+	rows, err := conn(ctx, r.db).Query(ctx, query+` ORDER BY date, builder_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []domain.AvailabilityDay{}
+	for rows.Next() {
+		var d domain.AvailabilityDay
+		if err := rows.Scan(&d.BuilderID, &d.Date, &d.Hours, &d.From, &d.Until, &d.Note, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
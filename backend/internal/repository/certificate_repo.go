@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type certificateRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewCertificateRepository creates a new certificate repository
+func NewCertificateRepository(db *pgxpool.Pool) domain.CertificateRepository {
+	return &certificateRepo{db: db}
+}
+
+const certificateColumns = `
+	id, request_id, domain, status, COALESCE(cert_pem, ''), COALESCE(key_pem, ''), COALESCE(issuer, ''),
+	not_before, not_after, renew_after, attempts, COALESCE(last_error, ''), created_at, updated_at`
+
+func scanCertificate(row pgx.Row) (*domain.Certificate, error) {
+	c := &domain.Certificate{}
+	err := row.Scan(
+		&c.ID, &c.RequestID, &c.Domain, &c.Status, &c.CertPEM, &c.KeyPEM, &c.Issuer,
+		&c.NotBefore, &c.NotAfter, &c.RenewAfter, &c.Attempts, &c.LastError, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *certificateRepo) FindByRequest(ctx context.Context, requestID uuid.UUID) (*domain.Certificate, error) {
+	c, err := scanCertificate(r.db.QueryRow(ctx,
+		`SELECT `+certificateColumns+` FROM certificates WHERE request_id = $1`, requestID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// Upsert keeps an issued certificate for the same domain (so a re-provision
+// only brings renewal forward) but starts over when the domain changed
+func (r *certificateRepo) Upsert(ctx context.Context, c *domain.Certificate) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO certificates (id, request_id, domain, status, renew_after, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (request_id) DO UPDATE SET
+			domain = EXCLUDED.domain,
+			status = CASE WHEN certificates.domain = EXCLUDED.domain AND certificates.status = 'issued'
+			              THEN certificates.status ELSE EXCLUDED.status END,
+			cert_pem = CASE WHEN certificates.domain = EXCLUDED.domain THEN certificates.cert_pem END,
+			key_pem = CASE WHEN certificates.domain = EXCLUDED.domain THEN certificates.key_pem END,
+			issuer = CASE WHEN certificates.domain = EXCLUDED.domain THEN certificates.issuer END,
+			not_before = CASE WHEN certificates.domain = EXCLUDED.domain THEN certificates.not_before END,
+			not_after = CASE WHEN certificates.domain = EXCLUDED.domain THEN certificates.not_after END,
+			renew_after = EXCLUDED.renew_after,
+			attempts = 0,
+			last_error = NULL,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+certificateColumns,
+		c.ID, c.RequestID, c.Domain, c.Status, c.RenewAfter, time.Now(),
+	).Scan(
+		&c.ID, &c.RequestID, &c.Domain, &c.Status, &c.CertPEM, &c.KeyPEM, &c.Issuer,
+		&c.NotBefore, &c.NotAfter, &c.RenewAfter, &c.Attempts, &c.LastError, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+func (r *certificateRepo) Update(ctx context.Context, c *domain.Certificate) error {
+	c.UpdatedAt = time.Now()
+	_, err := r.db.Exec(ctx, `
+		UPDATE certificates SET
+			status=$1, cert_pem=NULLIF($2, ''), key_pem=NULLIF($3, ''), issuer=NULLIF($4, ''),
+			not_before=$5, not_after=$6, renew_after=$7, attempts=$8, last_error=NULLIF($9, ''), updated_at=$10
+		WHERE id=$11`,
+		c.Status, c.CertPEM, c.KeyPEM, c.Issuer, c.NotBefore, c.NotAfter,
+		c.RenewAfter, c.Attempts, c.LastError, c.UpdatedAt, c.ID,
+	)
+	return err
+}
+
+func (r *certificateRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.Certificate, error) {
+	// The returned rows carry the old renew_after, from before the lease
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+			SELECT id, renew_after FROM certificates
+			WHERE renew_after <= $1
+			ORDER BY renew_after
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE certificates c SET renew_after = $2
+		FROM due WHERE c.id = due.id
+		RETURNING c.id, c.request_id, c.domain, c.status, COALESCE(c.cert_pem, ''), COALESCE(c.key_pem, ''),
+		          COALESCE(c.issuer, ''), c.not_before, c.not_after, due.renew_after, c.attempts,
+		          COALESCE(c.last_error, ''), c.created_at, c.updated_at`,
+		now, leaseUntil, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []domain.Certificate{}
+	for rows.Next() {
+		c, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, *c)
+	}
+	return certs, rows.Err()
+}
+
+func (r *certificateRepo) AccountKey(ctx context.Context, directoryURL string) (string, error) {
+	var key string
+	err := r.db.QueryRow(ctx, `SELECT key_pem FROM acme_accounts WHERE directory_url = $1`, directoryURL).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return key, err
+}
+
+func (r *certificateRepo) SaveAccountKey(ctx context.Context, directoryURL, keyPEM string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO acme_accounts (directory_url, key_pem) VALUES ($1, $2)
+		ON CONFLICT (directory_url) DO NOTHING`, directoryURL, keyPEM)
+	return err
+}
+
+func (r *certificateRepo) PutChallenge(ctx context.Context, token, keyAuth, domainName string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO acme_challenges (token, key_auth, domain) VALUES ($1, $2, $3)
+		ON CONFLICT (token) DO UPDATE SET key_auth = EXCLUDED.key_auth, domain = EXCLUDED.domain`,
+		token, keyAuth, domainName)
+	return err
+}
+
+func (r *certificateRepo) Challenge(ctx context.Context, token string) (string, error) {
+	var keyAuth string
+	err := r.db.QueryRow(ctx, `SELECT key_auth FROM acme_challenges WHERE token = $1`, token).Scan(&keyAuth)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return keyAuth, err
+}
+
+func (r *certificateRepo) DeleteChallenge(ctx context.Context, token string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM acme_challenges WHERE token = $1`, token)
+	return err
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type deliverableRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewDeliverableRepository creates a new deliverable repository
+func NewDeliverableRepository(db *pgxpool.Pool) domain.DeliverableRepository {
+	return &deliverableRepo{db: db}
+}
+
+const deliverableColumns = `id, request_id, kind, label, status, COALESCE(delivery_url, ''),
+	COALESCE(repo_url, ''), COALESCE(store_url, ''), artifacts, checklist, created_at, updated_at,
+	delivered_at`
+
+func scanDeliverable(row pgx.Row) (*domain.Deliverable, error) {
+	d := &domain.Deliverable{}
+	err := row.Scan(
+		&d.ID, &d.RequestID, &d.Kind, &d.Label, &d.Status, &d.DeliveryURL,
+		&d.RepoURL, &d.StoreURL, &d.Artifacts, &d.Checklist, &d.CreatedAt, &d.UpdatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if d.Artifacts == nil {
+		d.Artifacts = []domain.BuildArtifact{}
+	}
+	if d.Checklist == nil {
+		d.Checklist = []domain.ChecklistItem{}
+	}
+	return d, nil
+}
+
+func (r *deliverableRepo) Create(ctx context.Context, d *domain.Deliverable) error {
+	query := `
+		INSERT INTO deliverables (id, request_id, kind, label, status, delivery_url, repo_url, store_url,
+		       artifacts, checklist, created_at, updated_at, delivered_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12, $13)
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		d.ID, d.RequestID, d.Kind, d.Label, d.Status, d.DeliveryURL, d.RepoURL, d.StoreURL,
+		d.Artifacts, d.Checklist, d.CreatedAt, d.UpdatedAt, d.DeliveredAt,
+	)
+	return err
+}
+
+func (r *deliverableRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Deliverable, error) {
+	d, err := scanDeliverable(conn(ctx, r.db).QueryRow(ctx, `SELECT `+deliverableColumns+` FROM deliverables WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *deliverableRepo) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Deliverable, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+deliverableColumns+` FROM deliverables WHERE request_id = $1 ORDER BY created_at, id`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliverables []domain.Deliverable
+	for rows.Next() {
+		d, err := scanDeliverable(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliverables = append(deliverables, *d)
+	}
+	return deliverables, rows.Err()
+}
+
+func (r *deliverableRepo) Update(ctx context.Context, d *domain.Deliverable) error {
+	query := `
+		UPDATE deliverables SET label=$1, status=$2, delivery_url=NULLIF($3, ''), repo_url=NULLIF($4, ''),
+		       store_url=NULLIF($5, ''), artifacts=$6, checklist=$7, updated_at=$8, delivered_at=$9
+		WHERE id=$10
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		d.Label, d.Status, d.DeliveryURL, d.RepoURL, d.StoreURL, d.Artifacts, d.Checklist,
+		d.UpdatedAt, d.DeliveredAt, d.ID,
+	)
+	return err
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type domainVerificationRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewDomainVerificationRepository creates a new domain verification repository
+func NewDomainVerificationRepository(db *pgxpool.Pool) domain.DomainVerificationRepository {
+	return &domainVerificationRepo{db: db}
+}
+
+func (r *domainVerificationRepo) Find(ctx context.Context, requestID uuid.UUID) (*domain.DomainVerification, error) {
+	v := &domain.DomainVerification{RequestID: requestID}
+	err := r.db.QueryRow(ctx, `
+		SELECT hosting_type, COALESCE(whitelabel_domain, ''), COALESCE(domain_status, ''),
+		       COALESCE(domain_token, ''), COALESCE(domain_check_error, ''), domain_checked_at, domain_verified_at
+		FROM build_requests WHERE id = $1`, requestID,
+	).Scan(&v.HostingType, &v.Domain, &v.Status, &v.Token, &v.LastError, &v.CheckedAt, &v.VerifiedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func (r *domainVerificationRepo) Save(ctx context.Context, v *domain.DomainVerification) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE build_requests SET
+			domain_status=$1, domain_token=$2, domain_check_error=NULLIF($3, ''),
+			domain_checked_at=$4, domain_verified_at=$5
+		WHERE id=$6`,
+		v.Status, v.Token, v.LastError, v.CheckedAt, v.VerifiedAt, v.RequestID,
+	)
+	return err
+}
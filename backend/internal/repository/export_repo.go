@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// exportColumnSQL maps each dataset's export columns to SQL expressions.
+// Names are looked up with correlated subqueries rather than joins so the
+// request filter and ordering helpers apply unchanged. Every expression
+// yields text, bigint, boolean, date or timestamptz.
+var exportColumnSQL = map[domain.ExportDataset]map[string]string{
+	domain.ExportRequests: {
+		"id":                "id::text",
+		"title":             "title",
+		"status":            "status",
+		"request_type":      "request_type",
+		"complexity":        "complexity",
+		"hosting_type":      "hosting_type",
+		"student_name":      "(SELECT u.full_name FROM users u WHERE u.id = build_requests.user_id)",
+		"student_email":     "(SELECT u.email FROM users u WHERE u.id = build_requests.user_id)",
+		"student_id":        "(SELECT u.student_id FROM users u WHERE u.id = build_requests.user_id)",
+		"builder_name":      "(SELECT u.full_name FROM users u WHERE u.id = build_requests.builder_id)",
+		"is_free":           "is_free",
+		"estimated_cost":    "estimated_cost::text",
+		"currency":          "currency",
+		"labels":            "array_to_string(labels, ', ')",
+		"scheduled_weekend": "scheduled_weekend::date",
+		"delivery_url":      "delivery_url",
+		"created_at":        "created_at",
+		"completed_at":      "completed_at",
+	},
+	domain.ExportUsers: {
+		"id":                 "id::text",
+		"full_name":          "full_name",
+		"email":              "email",
+		"student_id":         "student_id",
+		"role":               "role",
+		"provider":           "provider",
+		"is_verified":        "is_verified",
+		"requests":           "(SELECT COUNT(*) FROM build_requests br WHERE br.user_id = users.id)",
+		"completed_requests": "(SELECT COUNT(*) FROM build_requests br WHERE br.user_id = users.id AND br.status = 'completed')",
+		"created_at":         "created_at",
+	},
+	domain.ExportSchedules: {
+		"slot_date":       "ws.date",
+		"day_of_week":     "ws.day_of_week",
+		"request_id":      "se.request_id::text",
+		"request_title":   "br.title",
+		"student_name":    "(SELECT u.full_name FROM users u WHERE u.id = br.user_id)",
+		"builder_name":    "(SELECT u.full_name FROM users u WHERE u.id = COALESCE(se.builder_id, br.builder_id))",
+		"estimated_hours": "se.estimated_hours::bigint",
+		"status":          "se.status",
+		"start_time":      "se.start_time",
+		"end_time":        "se.end_time",
+		"notes":           "se.notes",
+	},
+}
+
+type exportRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(db *pgxpool.Pool) domain.ExportRepository {
+	return &exportRepo{db: db}
+}
+
+func (r *exportRepo) Stream(ctx context.Context, q domain.ExportQuery, columns []domain.ExportColumn, fn func(values []interface{}) error) error {
+	exprs := make([]string, len(columns))
+	for i, col := range columns {
+		expr, ok := exportColumnSQL[q.Dataset][col.Key]
+		if !ok {
+			return fmt.Errorf("%w: unknown column %q for %s", domain.ErrInvalidExport, col.Key, q.Dataset)
+		}
+		exprs[i] = expr
+	}
+	selectList := strings.Join(exprs, ", ")
+
+	var query string
+	var args []interface{}
+	switch q.Dataset {
+	case domain.ExportRequests:
+		var where, orderBy string
+		where, args = buildRequestWhere(q.Requests)
+		orderBy, args = buildRequestOrder(q.Requests, args)
+		query = fmt.Sprintf(`SELECT %s FROM build_requests %s %s`, selectList, where, orderBy)
+	case domain.ExportUsers:
+		where := `WHERE 1=1`
+		if q.Role != nil {
+			args = append(args, *q.Role)
+			where += fmt.Sprintf(` AND role = $%d`, len(args))
+		}
+		where, args = appendTimeRange(where, args, "created_at", q.From, q.To)
+		query = fmt.Sprintf(`SELECT %s FROM users %s ORDER BY created_at, id`, selectList, where)
+	case domain.ExportSchedules:
+		where := `WHERE 1=1`
+		where, args = appendTimeRange(where, args, "ws.date", q.From, q.To)
+		query = fmt.Sprintf(`
+			SELECT %s
+			FROM schedule_entries se
+			JOIN weekend_slots ws ON ws.id = se.slot_id
+			JOIN build_requests br ON br.id = se.request_id
+			%s
+			ORDER BY ws.date, se.start_time, se.id`, selectList, where)
+	default:
+		return fmt.Errorf("%w: unknown dataset %q", domain.ErrInvalidExport, q.Dataset)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// appendTimeRange adds an inclusive-from, exclusive-to range on column
+func appendTimeRange(where string, args []interface{}, column string, from, to *time.Time) (string, []interface{}) {
+	if from != nil {
+		args = append(args, *from)
+		where += fmt.Sprintf(` AND %s >= $%d`, column, len(args))
+	}
+	if to != nil {
+		args = append(args, *to)
+		where += fmt.Sprintf(` AND %s < $%d`, column, len(args))
+	}
+	return where, args
+}
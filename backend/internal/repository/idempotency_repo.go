@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type idempotencyRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewIdempotencyRepository creates a new idempotency key repository
+func NewIdempotencyRepository(db *pgxpool.Pool) domain.IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+func (r *idempotencyRepo) Reserve(ctx context.Context, rec *domain.IdempotencyRecord) (*domain.IdempotencyRecord, error) {
+	var reserved bool
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (user_id, key, method, path, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, key) DO UPDATE SET
+			method = EXCLUDED.method, path = EXCLUDED.path, fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL, content_type = NULL, response_body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at <= $8)
+		RETURNING TRUE`,
+		rec.UserID, rec.Key, rec.Method, rec.Path, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt,
+		rec.CreatedAt.Add(-domain.IdempotencyLockTimeout),
+	).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	existing := &domain.IdempotencyRecord{}
+	var status *int
+	var contentType *string
+	err = r.db.QueryRow(ctx, `
+		SELECT user_id, key, method, path, fingerprint, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys WHERE user_id = $1 AND key = $2`, rec.UserID, rec.Key,
+	).Scan(
+		&existing.UserID, &existing.Key, &existing.Method, &existing.Path, &existing.Fingerprint,
+		&status, &contentType, &existing.ResponseBody, &existing.CreatedAt, &existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if status != nil {
+		existing.StatusCode = *status
+	}
+	if contentType != nil {
+		existing.ContentType = *contentType
+	}
+	return existing, nil
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE idempotency_keys SET status_code=$1, content_type=$2, response_body=$3
+		WHERE user_id=$4 AND key=$5 AND fingerprint=$6`,
+		rec.StatusCode, rec.ContentType, rec.ResponseBody, rec.UserID, rec.Key, rec.Fingerprint,
+	)
+	return err
+}
+
+func (r *idempotencyRepo) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND status_code IS NULL`, userID, key)
+	return err
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type importRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewImportRepository creates a new import repository
+func NewImportRepository(db *pgxpool.Pool) domain.ImportRepository {
+	return &importRepo{db: db}
+}
+
+func (r *importRepo) CreateBatch(ctx context.Context, b *domain.ImportBatch) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO import_batches (id, filename, content, headers, mapping, status, row_count, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		b.ID, b.Filename, b.Content, b.Headers, b.Mapping, b.Status, b.RowCount,
+		b.CreatedBy, b.CreatedAt, b.UpdatedAt,
+	)
+	return err
+}
+
+func (r *importRepo) FindBatch(ctx context.Context, id uuid.UUID) (*domain.ImportBatch, error) {
+	b := &domain.ImportBatch{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, filename, content, headers, mapping, status, row_count, report,
+		       created_by, created_at, updated_at, committed_at
+		FROM import_batches WHERE id = $1`, id,
+	).Scan(
+		&b.ID, &b.Filename, &b.Content, &b.Headers, &b.Mapping, &b.Status, &b.RowCount, &b.Report,
+		&b.CreatedBy, &b.CreatedAt, &b.UpdatedAt, &b.CommittedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+// updateBatchQuery saves a batch's mapping, status and report
+const updateBatchQuery = `
+	UPDATE import_batches SET mapping=$1, status=$2, report=$3, updated_at=$4, committed_at=$5
+	WHERE id=$6`
+
+func (r *importRepo) UpdateBatch(ctx context.Context, b *domain.ImportBatch) error {
+	_, err := r.db.Exec(ctx, updateBatchQuery, b.Mapping, b.Status, b.Report, time.Now(), b.CommittedAt, b.ID)
+	return err
+}
+
+func (r *importRepo) ExistingExternalKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	return r.existing(ctx, `SELECT external_key FROM build_requests WHERE external_key = ANY($1)`, keys)
+}
+
+func (r *importRepo) ExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	return r.existing(ctx, `SELECT LOWER(email) FROM users WHERE LOWER(email) = ANY($1)`, emails)
+}
+
+func (r *importRepo) existing(ctx context.Context, query string, values []string) (map[string]bool, error) {
+	found := map[string]bool{}
+	if len(values) == 0 {
+		return found, nil
+	}
+	rows, err := r.db.Query(ctx, query, values)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		found[v] = true
+	}
+	return found, rows.Err()
+}
+
+func (r *importRepo) Commit(ctx context.Context, batch *domain.ImportBatch, records []domain.ImportRecord) (int, int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialise commits of the same batch so a double click cannot import twice
+	var status domain.ImportBatchStatus
+	if err := tx.QueryRow(ctx, `SELECT status FROM import_batches WHERE id = $1 FOR UPDATE`, batch.ID).Scan(&status); err != nil {
+		return 0, 0, err
+	}
+	if status == domain.ImportCommitted {
+		return 0, 0, domain.ErrImportCommitted
+	}
+
+	students := map[string]uuid.UUID{}
+	created, users := 0, 0
+	for i := range records {
+		rec := &records[i]
+		email := strings.ToLower(rec.StudentEmail)
+
+		userID, ok := students[email]
+		if !ok {
+			err := tx.QueryRow(ctx, `SELECT id FROM users WHERE LOWER(email) = $1 ORDER BY created_at LIMIT 1`, email).Scan(&userID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Placeholder account: claimed when the student first signs in with this email
+				userID = uuid.New()
+				name := rec.StudentName
+				if name == "" {
+					name = strings.SplitN(email, "@", 2)[0]
+				}
+				now := time.Now()
+				_, err = tx.Exec(ctx, `
+					INSERT INTO users (id, email, password_hash, full_name, student_id, role, is_verified, provider, created_at, updated_at)
+					VALUES ($1, $2, '', $3, $4, $5, FALSE, 'email', $6, $6)`,
+					userID, email, name, rec.StudentID, domain.RoleStudent, now,
+				)
+				users++
+			}
+			if err != nil {
+				return 0, 0, err
+			}
+			students[email] = userID
+		}
+
+		req := &rec.Request
+		result, err := tx.Exec(ctx, `
+			INSERT INTO build_requests (
+				id, user_id, title, description, request_type, status, complexity, hosting_type,
+				estimated_cost, currency, is_free, add_ons, labels, delivery_url, repo_url,
+				external_key, import_batch_id, created_at, updated_at, completed_at, status_changed_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, '{}', $12, NULLIF($13, ''), NULLIF($14, ''), $15, $16, $17, $17, $18, COALESCE($18, $17))
+			ON CONFLICT (external_key) WHERE external_key IS NOT NULL DO NOTHING`,
+			req.ID, userID, req.Title, req.Description, req.RequestType, req.Status, req.Complexity, req.HostingType,
+			req.EstimatedCost, currencyOrDefault(req.Currency), req.IsFree, nonNilStrings(req.Labels),
+			req.DeliveryURL, req.RepoURL, rec.ExternalKey, batch.ID, req.CreatedAt, req.CompletedAt,
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+		created += int(result.RowsAffected())
+	}
+
+	now := time.Now()
+	batch.Status = domain.ImportCommitted
+	batch.CommittedAt = &now
+	if batch.Report != nil {
+		batch.Report.Create = created
+		// Rows lost to a concurrent import join those skipped in validation
+		batch.Report.Skip += len(records) - created
+		batch.Report.NewStudents = users
+	}
+	if _, err := tx.Exec(ctx, updateBatchQuery,
+		batch.Mapping, batch.Status, batch.Report, now, batch.CommittedAt, batch.ID); err != nil {
+		return 0, 0, err
+	}
+	return created, users, tx.Commit(ctx)
+}
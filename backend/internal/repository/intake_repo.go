@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type quotaOverrideRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewQuotaOverrideRepository creates a new quota override repository
+func NewQuotaOverrideRepository(db *pgxpool.Pool) domain.QuotaOverrideRepository {
+	return &quotaOverrideRepo{db: db}
+}
+
+func (r *quotaOverrideRepo) Upsert(ctx context.Context, o *domain.QuotaOverride) error {
+	query := `
+		INSERT INTO quota_overrides (user_id, reason, granted_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET reason = EXCLUDED.reason, granted_by = EXCLUDED.granted_by,
+		    expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, o.UserID, o.Reason, o.GrantedBy, o.ExpiresAt, o.CreatedAt)
+	return err
+}
+
+func (r *quotaOverrideRepo) FindByUser(ctx context.Context, userID uuid.UUID) (*domain.QuotaOverride, error) {
+	o := &domain.QuotaOverride{}
+	err := conn(ctx, r.db).QueryRow(ctx, `
+		SELECT user_id, reason, granted_by, expires_at, created_at
+		FROM quota_overrides WHERE user_id = $1
+	`, userID).Scan(&o.UserID, &o.Reason, &o.GrantedBy, &o.ExpiresAt, &o.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return o, err
+}
+
+func (r *quotaOverrideRepo) Delete(ctx context.Context, userID uuid.UUID) error {
+	result, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM quota_overrides WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrQuotaOverrideNotFound
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type invoiceRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *pgxpool.Pool) domain.InvoiceRepository {
+	return &invoiceRepo{db: db}
+}
+
+const invoiceColumns = `id, request_id, kind, number, financial_year, sequence, source_ref, issued_at,
+	currency, buyer_name, buyer_email, place_of_supply, lines, taxable_amount, cgst, sgst, igst,
+	total, artifact_id`
+
+func scanInvoice(row pgx.Row) (*domain.Invoice, error) {
+	inv := &domain.Invoice{}
+	err := row.Scan(
+		&inv.ID, &inv.RequestID, &inv.Kind, &inv.Number, &inv.FinancialYear, &inv.Sequence,
+		&inv.SourceRef, &inv.IssuedAt, &inv.Currency, &inv.BuyerName, &inv.BuyerEmail,
+		&inv.PlaceOfSupply, &inv.Lines, &inv.TaxableAmount, &inv.CGST, &inv.SGST, &inv.IGST,
+		&inv.Total, &inv.ArtifactID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range []*domain.Money{&inv.TaxableAmount, &inv.CGST, &inv.SGST, &inv.IGST, &inv.Total} {
+		*m = m.In(inv.Currency)
+	}
+	for i := range inv.Lines {
+		l := &inv.Lines[i]
+		for _, m := range []*domain.Money{&l.Taxable, &l.CGST, &l.SGST, &l.IGST, &l.Total} {
+			*m = m.In(inv.Currency)
+		}
+	}
+	return inv, nil
+}
+
+func (r *invoiceRepo) Issue(ctx context.Context, inv *domain.Invoice, finalize func(*domain.Invoice) (*domain.Artifact, error)) (*domain.Invoice, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Taking the counter row lock first serializes issuers in the same series,
+	// so the existence check below cannot race with another issue
+	var seq int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO invoice_sequences (kind, financial_year, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (kind, financial_year)
+		DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`, inv.Kind, inv.FinancialYear).Scan(&seq)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := scanInvoice(tx.QueryRow(ctx,
+		`SELECT `+invoiceColumns+` FROM invoices WHERE kind = $1 AND source_ref = $2`, inv.Kind, inv.SourceRef))
+	if err == nil {
+		return existing, nil // rollback releases the number
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	inv.Sequence = seq
+	artifact, err := finalize(inv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", inv.Kind, err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO request_artifacts (id, request_id, kind, filename, content_type, size_bytes, sha256, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, artifact.ID, artifact.RequestID, artifact.Kind, artifact.Filename, artifact.ContentType,
+		artifact.Size, artifact.SHA256, artifact.Content, artifact.CreatedAt); err != nil {
+		return nil, err
+	}
+	inv.ArtifactID = artifact.ID
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO invoices (id, request_id, kind, number, financial_year, sequence, source_ref, issued_at,
+		       currency, buyer_name, buyer_email, place_of_supply, lines, taxable_amount, cgst, sgst, igst,
+		       total, artifact_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`, inv.ID, inv.RequestID, inv.Kind, inv.Number, inv.FinancialYear, inv.Sequence, inv.SourceRef,
+		inv.IssuedAt, inv.Currency, inv.BuyerName, inv.BuyerEmail, inv.PlaceOfSupply, inv.Lines,
+		inv.TaxableAmount, inv.CGST, inv.SGST, inv.IGST, inv.Total, inv.ArtifactID); err != nil {
+		return nil, err
+	}
+
+	return inv, tx.Commit(ctx)
+}
+
+func (r *invoiceRepo) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Invoice, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+invoiceColumns+` FROM invoices WHERE request_id = $1 ORDER BY issued_at, kind, sequence`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := []domain.Invoice{}
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, *inv)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *invoiceRepo) FindArtifact(ctx context.Context, id uuid.UUID) (*domain.Artifact, error) {
+	a := &domain.Artifact{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, request_id, kind, filename, content_type, size_bytes, sha256, content, created_at
+		FROM request_artifacts WHERE id = $1
+	`, id).Scan(&a.ID, &a.RequestID, &a.Kind, &a.Filename, &a.ContentType, &a.Size, &a.SHA256,
+		&a.Content, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
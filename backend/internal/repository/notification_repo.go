@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type notificationRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *pgxpool.Pool) domain.NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) Create(ctx context.Context, n *domain.Notification) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO notifications (id, user_id, request_id, message, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		n.ID, n.UserID, n.RequestID, n.Message, n.CreatedAt,
+	)
+	return err
+}
+
+func (r *notificationRepo) CreateForRole(ctx context.Context, role domain.Role, n *domain.Notification) (int, error) {
+	result, err := r.db.Exec(ctx, `
+		INSERT INTO notifications (user_id, request_id, message, created_at)
+		SELECT id, $2, $3, $4 FROM users WHERE role = $1`,
+		role, n.RequestID, n.Message, n.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+func (r *notificationRepo) ListForUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]domain.Notification, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, request_id, message, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (read_at IS NULL OR NOT $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []domain.Notification
+	for rows.Next() {
+		var n domain.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.RequestID, &n.Message, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
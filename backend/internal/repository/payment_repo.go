@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type paymentRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *pgxpool.Pool) domain.PaymentRepository {
+	return &paymentRepo{db: db}
+}
+
+const intentColumns = `id, request_id, quote_id, provider, provider_order_id, amount, currency,
+	amount_paid, amount_refunded, status, created_by, created_at, updated_at`
+
+func scanIntent(row pgx.Row) (*domain.PaymentIntent, error) {
+	p := &domain.PaymentIntent{}
+	err := row.Scan(
+		&p.ID, &p.RequestID, &p.QuoteID, &p.Provider, &p.ProviderOrderID, &p.Amount, &p.Currency,
+		&p.AmountPaid, &p.AmountRefunded, &p.Status, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.Amount = p.Amount.In(p.Currency)
+	p.AmountPaid = p.AmountPaid.In(p.Currency)
+	p.AmountRefunded = p.AmountRefunded.In(p.Currency)
+	return p, nil
+}
+
+func (r *paymentRepo) findIntent(ctx context.Context, where string, args ...interface{}) (*domain.PaymentIntent, error) {
+	p, err := scanIntent(conn(ctx, r.db).QueryRow(ctx, `SELECT `+intentColumns+` FROM payment_intents WHERE `+where, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *paymentRepo) CreateIntent(ctx context.Context, p *domain.PaymentIntent) error {
+	query := `
+		INSERT INTO payment_intents (id, request_id, quote_id, provider, provider_order_id, amount,
+		       currency, amount_paid, amount_refunded, status, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		p.ID, p.RequestID, p.QuoteID, p.Provider, p.ProviderOrderID, p.Amount,
+		p.Currency, p.AmountPaid, p.AmountRefunded, p.Status, p.CreatedBy, p.CreatedAt, p.UpdatedAt,
+	)
+	return err
+}
+
+func (r *paymentRepo) FindIntentByID(ctx context.Context, id uuid.UUID) (*domain.PaymentIntent, error) {
+	return r.findIntent(ctx, `id = $1`, id)
+}
+
+func (r *paymentRepo) LockIntentByID(ctx context.Context, id uuid.UUID) (*domain.PaymentIntent, error) {
+	return r.findIntent(ctx, `id = $1 FOR UPDATE`, id)
+}
+
+func (r *paymentRepo) FindIntentByOrderID(ctx context.Context, provider, orderID string) (*domain.PaymentIntent, error) {
+	return r.findIntent(ctx, `provider = $1 AND provider_order_id = $2`, provider, orderID)
+}
+
+func (r *paymentRepo) FindIntentByPaymentRef(ctx context.Context, provider, paymentRef string) (*domain.PaymentIntent, error) {
+	return r.findIntent(ctx, `id = (
+		SELECT intent_id FROM payment_ledger
+		WHERE provider = $1 AND provider_ref = $2 AND kind = 'payment'
+	)`, provider, paymentRef)
+}
+
+func (r *paymentRepo) ListIntentsByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.PaymentIntent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+intentColumns+` FROM payment_intents WHERE request_id = $1 ORDER BY created_at ASC`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var intents []domain.PaymentIntent
+	for rows.Next() {
+		p, err := scanIntent(rows)
+		if err != nil {
+			return nil, err
+		}
+		intents = append(intents, *p)
+	}
+	return intents, rows.Err()
+}
+
+func (r *paymentRepo) ListLedgerByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.LedgerEntry, error) {
+	query := `
+		SELECT id, request_id, intent_id, kind, amount, currency, provider, provider_ref,
+		       COALESCE(payment_ref, ''), COALESCE(note, ''), occurred_at, created_at
+		FROM payment_ledger WHERE request_id = $1
+		ORDER BY occurred_at ASC, created_at ASC
+	`
+	rows, err := conn(ctx, r.db).Query(ctx, query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.LedgerEntry
+	for rows.Next() {
+		var e domain.LedgerEntry
+		if err := rows.Scan(
+			&e.ID, &e.RequestID, &e.IntentID, &e.Kind, &e.Amount, &e.Currency,
+			&e.Provider, &e.ProviderRef, &e.PaymentRef, &e.Note, &e.OccurredAt, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		e.Amount = e.Amount.In(e.Currency)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *paymentRepo) ApplyLedgerEntry(ctx context.Context, e *domain.LedgerEntry) (bool, error) {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the intent so concurrent webhooks fold in one at a time
+	intent, err := scanIntent(tx.QueryRow(ctx,
+		`SELECT `+intentColumns+` FROM payment_intents WHERE id = $1 FOR UPDATE`, e.IntentID))
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(ctx, `
+		INSERT INTO payment_ledger (id, request_id, intent_id, kind, amount, currency, provider,
+		       provider_ref, payment_ref, note, occurred_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), NULLIF($10, ''), $11, $12)
+		ON CONFLICT (provider, provider_ref) DO NOTHING
+	`, e.ID, e.RequestID, e.IntentID, e.Kind, e.Amount, e.Currency, e.Provider,
+		e.ProviderRef, e.PaymentRef, e.Note, e.OccurredAt, e.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, nil // already recorded
+	}
+
+	if err := intent.Apply(e); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment_intents SET amount_paid=$1, amount_refunded=$2, status=$3, updated_at=NOW()
+		WHERE id=$4
+	`, intent.AmountPaid, intent.AmountRefunded, intent.Status, intent.ID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type pricingRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewPricingRepository creates a new pricing rule repository
+func NewPricingRepository(db *pgxpool.Pool) domain.PricingRepository {
+	return &pricingRepo{db: db}
+}
+
+const pricingColumns = `id, version, name, status, rules, created_by, created_at, activated_at`
+
+func scanRuleSet(row pgx.Row) (*domain.PricingRuleSet, error) {
+	set := &domain.PricingRuleSet{}
+	err := row.Scan(
+		&set.ID, &set.Version, &set.Name, &set.Status, &set.Rules,
+		&set.CreatedBy, &set.CreatedAt, &set.ActivatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+func (r *pricingRepo) Create(ctx context.Context, set *domain.PricingRuleSet) error {
+	query := `
+		INSERT INTO pricing_rule_sets (id, name, status, rules, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING version
+	`
+	return r.db.QueryRow(ctx, query,
+		set.ID, set.Name, set.Status, set.Rules, set.CreatedBy, set.CreatedAt,
+	).Scan(&set.Version)
+}
+
+func (r *pricingRepo) FindByVersion(ctx context.Context, version int) (*domain.PricingRuleSet, error) {
+	query := `SELECT ` + pricingColumns + ` FROM pricing_rule_sets WHERE version = $1`
+	set, err := scanRuleSet(r.db.QueryRow(ctx, query, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return set, err
+}
+
+func (r *pricingRepo) FindActive(ctx context.Context) (*domain.PricingRuleSet, error) {
+	query := `SELECT ` + pricingColumns + ` FROM pricing_rule_sets WHERE status = 'active'`
+	set, err := scanRuleSet(r.db.QueryRow(ctx, query))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return set, err
+}
+
+func (r *pricingRepo) List(ctx context.Context) ([]domain.PricingRuleSet, error) {
+	query := `SELECT ` + pricingColumns + ` FROM pricing_rule_sets ORDER BY version DESC`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []domain.PricingRuleSet
+	for rows.Next() {
+		set, err := scanRuleSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, *set)
+	}
+	return sets, rows.Err()
+}
+
+// Activate retires the current active rule set and activates the given
+// version in a single transaction
+func (r *pricingRepo) Activate(ctx context.Context, version int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE pricing_rule_sets SET status = 'retired' WHERE status = 'active' AND version <> $1`,
+		version); err != nil {
+		return err
+	}
+	result, err := tx.Exec(ctx,
+		`UPDATE pricing_rule_sets SET status = 'active', activated_at = NOW() WHERE version = $1 AND status = 'draft'`,
+		version)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("pricing rule set %d is not a draft", version)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// pgUniqueViolation is the SQLSTATE for a unique constraint violation
+const pgUniqueViolation = "23505"
+
+type promotionRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewPromotionRepository creates a new promotion repository
+func NewPromotionRepository(db *pgxpool.Pool) domain.PromotionRepository {
+	return &promotionRepo{db: db}
+}
+
+const discountCodeColumns = `id, code, COALESCE(description, ''), kind, percent, amount, currency,
+	max_uses, per_student_limit, used_count, request_types, starts_at, expires_at, active,
+	created_by, created_at`
+
+func scanDiscountCode(row pgx.Row) (*domain.DiscountCode, error) {
+	c := &domain.DiscountCode{}
+	var currency string
+	var types []string
+	err := row.Scan(
+		&c.ID, &c.Code, &c.Description, &c.Kind, &c.Percent, &c.Amount, &currency,
+		&c.MaxUses, &c.PerStudentLimit, &c.UsedCount, &types, &c.StartsAt, &c.ExpiresAt, &c.Active,
+		&c.CreatedBy, &c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.Amount = c.Amount.In(currency)
+	for _, t := range types {
+		c.RequestTypes = append(c.RequestTypes, domain.RequestType(t))
+	}
+	return c, nil
+}
+
+const requestDiscountColumns = `id, request_id, user_id, source, code_id, COALESCE(code, ''), label, kind,
+	percent, amount, applied_amount, currency, COALESCE(reason, ''), granted_by, created_at`
+
+func scanRequestDiscount(row pgx.Row) (*domain.RequestDiscount, error) {
+	d := &domain.RequestDiscount{}
+	var currency string
+	err := row.Scan(
+		&d.ID, &d.RequestID, &d.UserID, &d.Source, &d.CodeID, &d.Code, &d.Label, &d.Kind,
+		&d.Percent, &d.Amount, &d.AppliedAmount, &currency, &d.Reason, &d.GrantedBy, &d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Amount = d.Amount.In(currency)
+	d.AppliedAmount = d.AppliedAmount.In(currency)
+	return d, nil
+}
+
+func requestTypeStrings(types []domain.RequestType) []string {
+	out := make([]string, 0, len(types))
+	for _, t := range types {
+		out = append(out, string(t))
+	}
+	return out
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+func (r *promotionRepo) CreateCode(ctx context.Context, c *domain.DiscountCode) error {
+	query := `
+		INSERT INTO discount_codes (id, code, description, kind, percent, amount, currency, max_uses,
+		       per_student_limit, request_types, starts_at, expires_at, active, created_by, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		c.ID, c.Code, c.Description, c.Kind, c.Percent, c.Amount, c.Amount.Currency, c.MaxUses,
+		c.PerStudentLimit, requestTypeStrings(c.RequestTypes), c.StartsAt, c.ExpiresAt, c.Active,
+		c.CreatedBy, c.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrDiscountCodeExists
+	}
+	return err
+}
+
+func (r *promotionRepo) FindCodeByID(ctx context.Context, id uuid.UUID) (*domain.DiscountCode, error) {
+	c, err := scanDiscountCode(conn(ctx, r.db).QueryRow(ctx, `SELECT `+discountCodeColumns+` FROM discount_codes WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *promotionRepo) FindCodeByCode(ctx context.Context, code string) (*domain.DiscountCode, error) {
+	c, err := scanDiscountCode(conn(ctx, r.db).QueryRow(ctx,
+		`SELECT `+discountCodeColumns+` FROM discount_codes WHERE code = UPPER($1)`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *promotionRepo) ListCodes(ctx context.Context) ([]domain.DiscountCode, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `SELECT `+discountCodeColumns+` FROM discount_codes ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []domain.DiscountCode
+	for rows.Next() {
+		c, err := scanDiscountCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, *c)
+	}
+	return codes, rows.Err()
+}
+
+func (r *promotionRepo) UpdateCode(ctx context.Context, c *domain.DiscountCode) error {
+	query := `
+		UPDATE discount_codes SET active=$1, max_uses=$2, per_student_limit=$3, expires_at=$4
+		WHERE id=$5
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, c.Active, c.MaxUses, c.PerStudentLimit, c.ExpiresAt, c.ID)
+	return err
+}
+
+func (r *promotionRepo) Redeem(ctx context.Context, d *domain.RequestDiscount) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the code so concurrent redemptions count against its limits one at a time
+	code, err := scanDiscountCode(tx.QueryRow(ctx,
+		`SELECT `+discountCodeColumns+` FROM discount_codes WHERE id = $1 FOR UPDATE`, d.CodeID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrDiscountCodeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if code.MaxUses != nil && code.UsedCount >= *code.MaxUses {
+		return fmt.Errorf("%w: code has been fully redeemed", domain.ErrDiscountNotApplicable)
+	}
+	if code.PerStudentLimit != nil {
+		var used int
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM request_discounts
+			WHERE code_id = $1 AND user_id = $2 AND removed_at IS NULL
+		`, code.ID, d.UserID).Scan(&used); err != nil {
+			return err
+		}
+		if used >= *code.PerStudentLimit {
+			return fmt.Errorf("%w: you have already used this code", domain.ErrDiscountNotApplicable)
+		}
+	}
+
+	if err := insertRequestDiscount(ctx, tx, d); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: the request already has a discount code", domain.ErrDiscountNotApplicable)
+		}
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE discount_codes SET used_count = used_count + 1 WHERE id = $1`, code.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *promotionRepo) AddWaiver(ctx context.Context, d *domain.RequestDiscount) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertRequestDiscount(ctx, tx, d); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func insertRequestDiscount(ctx context.Context, tx pgx.Tx, d *domain.RequestDiscount) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO request_discounts (id, request_id, user_id, source, code_id, code, label, kind,
+		       percent, amount, applied_amount, currency, reason, granted_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15)
+	`, d.ID, d.RequestID, d.UserID, d.Source, d.CodeID, d.Code, d.Label, d.Kind,
+		d.Percent, d.Amount, d.AppliedAmount, d.Amount.Currency, d.Reason, d.GrantedBy, d.CreatedAt)
+	return err
+}
+
+func (r *promotionRepo) FindDiscount(ctx context.Context, id uuid.UUID) (*domain.RequestDiscount, error) {
+	d, err := scanRequestDiscount(conn(ctx, r.db).QueryRow(ctx,
+		`SELECT `+requestDiscountColumns+` FROM request_discounts WHERE id = $1 AND removed_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *promotionRepo) RemoveDiscount(ctx context.Context, d *domain.RequestDiscount) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		`UPDATE request_discounts SET removed_at = NOW() WHERE id = $1 AND removed_at IS NULL`, d.ID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrDiscountNotFound
+	}
+	if d.CodeID != nil {
+		if _, err := tx.Exec(ctx,
+			`UPDATE discount_codes SET used_count = GREATEST(used_count - 1, 0) WHERE id = $1`, *d.CodeID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *promotionRepo) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.RequestDiscount, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT `+requestDiscountColumns+` FROM request_discounts
+		WHERE request_id = $1 AND removed_at IS NULL
+		ORDER BY created_at, id
+	`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var discounts []domain.RequestDiscount
+	for rows.Next() {
+		d, err := scanRequestDiscount(rows)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, *d)
+	}
+	return discounts, rows.Err()
+}
+
+func (r *promotionRepo) Report(ctx context.Context, from, to *time.Time) ([]domain.PromotionReportItem, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, `
+		SELECT source, COALESCE(code, ''), currency, COUNT(*), COUNT(DISTINCT request_id), SUM(applied_amount)
+		FROM request_discounts
+		WHERE removed_at IS NULL
+		  AND ($1::timestamptz IS NULL OR created_at >= $1)
+		  AND ($2::timestamptz IS NULL OR created_at < $2)
+		GROUP BY source, COALESCE(code, ''), currency
+		ORDER BY SUM(applied_amount) DESC, 2
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []domain.PromotionReportItem
+	for rows.Next() {
+		var item domain.PromotionReportItem
+		var currency string
+		if err := rows.Scan(&item.Source, &item.Code, &currency, &item.Redemptions, &item.Requests, &item.Discounted); err != nil {
+			return nil, err
+		}
+		item.Discounted = item.Discounted.In(currency)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
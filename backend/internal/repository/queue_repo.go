@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type queueRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewQueueRepository creates a new build queue repository
+func NewQueueRepository(db *pgxpool.Pool) domain.QueueRepository {
+	return &queueRepo{db: db}
+}
+
+func (r *queueRepo) ListQueued(ctx context.Context) ([]domain.QueueEntry, error) {
+	active := make([]string, len(domain.ActiveBuildStatuses))
+	for i, st := range domain.ActiveBuildStatuses {
+		active[i] = string(st)
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT br.id, br.user_id, br.builder_id, br.title, br.complexity, br.status_changed_at, u.graduation_year,
+		       (SELECT COUNT(*) FROM build_requests a
+		        WHERE a.user_id = br.user_id AND a.status = ANY($2))
+		FROM build_requests br
+		JOIN users u ON u.id = br.user_id
+		WHERE br.status = $1
+		ORDER BY br.status_changed_at, br.id`,
+		domain.StatusQueued, active,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.QueueEntry{}
+	for rows.Next() {
+		var e domain.QueueEntry
+		if err := rows.Scan(
+			&e.RequestID, &e.UserID, &e.BuilderID, &e.Title, &e.Complexity, &e.QueuedAt, &e.GraduationYear, &e.ActiveBuilds,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *queueRepo) SetGraduationYear(ctx context.Context, userID uuid.UUID, year *int) error {
+	result, err := r.db.Exec(ctx, `UPDATE users SET graduation_year = $1, updated_at = NOW() WHERE id = $2`, year, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
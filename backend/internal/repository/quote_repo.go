@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type quoteRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewQuoteRepository creates a new quote repository
+func NewQuoteRepository(db *pgxpool.Pool) domain.QuoteRepository {
+	return &quoteRepo{db: db}
+}
+
+const quoteColumns = `id, request_id, revision, status, currency, lines, total, valid_until,
+	COALESCE(notes, ''), pricing_version, created_by, created_at, sent_at, responded_at,
+	COALESCE(response_note, '')`
+
+func scanQuote(row pgx.Row) (*domain.Quote, error) {
+	q := &domain.Quote{}
+	err := row.Scan(
+		&q.ID, &q.RequestID, &q.Revision, &q.Status, &q.Currency, &q.Lines, &q.Total,
+		&q.ValidUntil, &q.Notes, &q.PricingVersion, &q.CreatedBy, &q.CreatedAt,
+		&q.SentAt, &q.RespondedAt, &q.ResponseNote,
+	)
+	if err != nil {
+		return nil, err
+	}
+	q.Total = q.Total.In(q.Currency)
+	for i := range q.Lines {
+		q.Lines[i].Amount = q.Lines[i].Amount.In(q.Currency)
+	}
+	return q, nil
+}
+
+func (r *quoteRepo) Create(ctx context.Context, q *domain.Quote) error {
+	query := `
+		INSERT INTO quotes (id, request_id, revision, status, currency, lines, total, valid_until,
+		       notes, pricing_version, created_by, created_at, sent_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		q.ID, q.RequestID, q.Revision, q.Status, q.Currency, q.Lines, q.Total, q.ValidUntil,
+		q.Notes, q.PricingVersion, q.CreatedBy, q.CreatedAt, q.SentAt,
+	)
+	return err
+}
+
+func (r *quoteRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Quote, error) {
+	q, err := scanQuote(conn(ctx, r.db).QueryRow(ctx, `SELECT `+quoteColumns+` FROM quotes WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return q, err
+}
+
+func (r *quoteRepo) ListByRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Quote, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+quoteColumns+` FROM quotes WHERE request_id = $1 ORDER BY revision DESC`, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []domain.Quote
+	for rows.Next() {
+		q, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, *q)
+	}
+	return quotes, rows.Err()
+}
+
+func (r *quoteRepo) FindAccepted(ctx context.Context, requestID uuid.UUID) (*domain.Quote, error) {
+	q, err := scanQuote(conn(ctx, r.db).QueryRow(ctx,
+		`SELECT `+quoteColumns+` FROM quotes WHERE request_id = $1 AND status = 'accepted'`, requestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return q, err
+}
+
+func (r *quoteRepo) NextRevision(ctx context.Context, requestID uuid.UUID) (int, error) {
+	var next int
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT COALESCE(MAX(revision), 0) + 1 FROM quotes WHERE request_id = $1`, requestID).Scan(&next)
+	return next, err
+}
+
+func (r *quoteRepo) UpdateStatus(ctx context.Context, q *domain.Quote) error {
+	query := `
+		UPDATE quotes SET status=$1, sent_at=$2, responded_at=$3, response_note=$4
+		WHERE id=$5
+	`
+	_, err := conn(ctx, r.db).Exec(ctx, query, q.Status, q.SentAt, q.RespondedAt, q.ResponseNote, q.ID)
+	return err
+}
+
+// MarkAccepted accepts the quote and supersedes any earlier accepted or open
+// revision of the same request in one transaction. Only a sent quote can be
+// accepted, so a revision that was superseded or answered concurrently
+// returns ErrQuoteNotOpen
+func (r *quoteRepo) MarkAccepted(ctx context.Context, q *domain.Quote) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `
+		UPDATE quotes SET status = 'accepted', responded_at = $1, response_note = $2
+		WHERE id = $3 AND status = 'sent'
+	`, q.RespondedAt, q.ResponseNote, q.ID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrQuoteNotOpen
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE quotes SET status = 'superseded'
+		WHERE request_id = $1 AND id <> $2 AND status IN ('draft', 'sent', 'accepted')
+	`, q.RequestID, q.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *quoteRepo) SupersedeOpen(ctx context.Context, requestID uuid.UUID, exceptID uuid.UUID) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE quotes SET status = 'superseded'
+		WHERE request_id = $1 AND id <> $2 AND status IN ('draft', 'sent')
+	`, requestID, exceptID)
+	return err
+}
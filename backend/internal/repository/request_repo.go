@@ -27,40 +27,55 @@ func (r *requestRepo) Create(ctx context.Context, req *domain.BuildRequest) erro
 			id, user_id, title, description, request_type, status, complexity,
 			hosting_type, whitelabel_domain, whitelabel_branding, whitelabel_hosting_platform,
 			tech_requirements, reference_links, figma_link, hosting_email,
-			estimated_cost, is_free, created_at, updated_at
+			estimated_cost, currency, is_free, add_ons, pricing_version, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
 		)
 	`
-	_, err := r.db.Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		req.ID, req.UserID, req.Title, req.Description,
 		req.RequestType, req.Status, req.Complexity,
 		req.HostingType, req.WhitelabelDomain, req.WhitelabelBranding,
 		req.WhitelabelHosting, req.TechRequirements, req.ReferenceLinks,
-		req.Figma, req.HostingEmail, req.EstimatedCost, req.IsFree,
+		req.Figma, req.HostingEmail, req.EstimatedCost, currencyOrDefault(req.Currency), req.IsFree,
+		nonNilStrings(req.AddOns), req.PricingVersion,
 		req.CreatedAt, req.UpdatedAt,
 	)
 	return err
 }
 
 func (r *requestRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.BuildRequest, error) {
+	return r.findByID(ctx, id, "")
+}
+
+func (r *requestRepo) LockByID(ctx context.Context, id uuid.UUID) (*domain.BuildRequest, error) {
+	return r.findByID(ctx, id, "FOR UPDATE")
+}
+
+func (r *requestRepo) findByID(ctx context.Context, id uuid.UUID, lock string) (*domain.BuildRequest, error) {
 	query := `
 		SELECT id, user_id, title, description, request_type, status, complexity,
-		       hosting_type, whitelabel_domain, whitelabel_branding, whitelabel_hosting_platform,
-		       tech_requirements, reference_links, figma_link, hosting_email,
-		       estimated_cost, is_free, delivery_url, repo_url,
-		       scheduled_weekend, builder_id, created_at, updated_at, completed_at
+		       hosting_type, COALESCE(whitelabel_domain, ''), COALESCE(whitelabel_branding, ''),
+		       COALESCE(whitelabel_hosting_platform, ''), COALESCE(tech_requirements, ''),
+		       COALESCE(reference_links, ''), COALESCE(figma_link, ''), COALESCE(hosting_email, ''),
+		       estimated_cost, currency, is_free, add_ons, pricing_version, labels,
+		       COALESCE(delivery_url, ''), COALESCE(repo_url, ''),
+		       scheduled_weekend, builder_id, created_at, updated_at, completed_at,
+		       COALESCE(domain_status, ''), version
 		FROM build_requests WHERE id = $1
-	`
+	` + lock
 	req := &domain.BuildRequest{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	var scheduled *time.Time
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&req.ID, &req.UserID, &req.Title, &req.Description,
 		&req.RequestType, &req.Status, &req.Complexity,
 		&req.HostingType, &req.WhitelabelDomain, &req.WhitelabelBranding,
 		&req.WhitelabelHosting, &req.TechRequirements, &req.ReferenceLinks,
-		&req.Figma, &req.HostingEmail, &req.EstimatedCost, &req.IsFree,
-		&req.DeliveryURL, &req.RepoURL, &req.ScheduledWeekend, &req.BuilderID,
+		&req.Figma, &req.HostingEmail, &req.EstimatedCost, &req.Currency, &req.IsFree,
+		&req.AddOns, &req.PricingVersion, &req.Labels,
+		&req.DeliveryURL, &req.RepoURL, &scheduled, &req.BuilderID,
 		&req.CreatedAt, &req.UpdatedAt, &req.CompletedAt,
+		&req.DomainStatus, &req.Version,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -68,60 +83,228 @@ func (r *requestRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Build
 		}
 		return nil, err
 	}
+	if scheduled != nil {
+		req.ScheduledWeekend = *scheduled
+	}
+	req.EstimatedCost = req.EstimatedCost.In(req.Currency)
 	return req, nil
 }
 
-func (r *requestRepo) Update(ctx context.Context, req *domain.BuildRequest) error {
-	query := `
-		UPDATE build_requests SET
-			status=$1, complexity=$2, estimated_cost=$3, is_free=$4,
-			delivery_url=$5, repo_url=$6, scheduled_weekend=$7,
-			builder_id=$8, updated_at=$9, completed_at=$10
-		WHERE id=$11
-	`
-	_, err := r.db.Exec(ctx, query,
+// updateRequestQuery saves the fields admins and services may change,
+// provided nobody else saved the request since it was read
+const updateRequestQuery = `
+	UPDATE build_requests SET
+		status=$1, complexity=$2, estimated_cost=$3, is_free=$4,
+		delivery_url=$5, repo_url=$6, scheduled_weekend=$7,
+		builder_id=$8, updated_at=$9, completed_at=$10,
+		add_ons=$11, pricing_version=$12, currency=$13, labels=$14,
+		status_changed_at = CASE WHEN status = $1 THEN status_changed_at ELSE NOW() END,
+		version = version + 1
+	WHERE id=$15 AND version=$16
+`
+
+func updateRequestArgs(req *domain.BuildRequest) []interface{} {
+	return []interface{}{
 		req.Status, req.Complexity, req.EstimatedCost, req.IsFree,
-		req.DeliveryURL, req.RepoURL, req.ScheduledWeekend,
-		req.BuilderID, time.Now(), req.CompletedAt, req.ID,
-	)
-	return err
+		req.DeliveryURL, req.RepoURL, nullableTime(req.ScheduledWeekend),
+		req.BuilderID, time.Now(), req.CompletedAt,
+		nonNilStrings(req.AddOns), req.PricingVersion, currencyOrDefault(req.Currency),
+		nonNilStrings(req.Labels), req.ID, req.Version,
+	}
 }
 
-func (r *requestRepo) List(ctx context.Context, filter domain.RequestFilter) ([]domain.BuildRequest, int, error) {
-	baseQuery := `FROM build_requests WHERE 1=1`
+func (r *requestRepo) Update(ctx context.Context, req *domain.BuildRequest) error {
+	result, err := conn(ctx, r.db).Exec(ctx, updateRequestQuery, updateRequestArgs(req)...)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrVersionConflict
+	}
+	req.Version++
+	return nil
+}
+
+func (r *requestRepo) UpdateMany(ctx context.Context, reqs []*domain.BuildRequest) error {
+	tx, err := conn(ctx, r.db).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, req := range reqs {
+		batch.Queue(updateRequestQuery, updateRequestArgs(req)...)
+	}
+	results := tx.SendBatch(ctx, batch)
+	for range reqs {
+		result, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			results.Close()
+			return domain.ErrVersionConflict
+		}
+	}
+	if err := results.Close(); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		req.Version++
+	}
+	return nil
+}
+
+// requestSortColumns maps public sort fields to SQL expressions
+var requestSortColumns = map[domain.RequestSortField]string{
+	domain.SortByCreatedAt:        "created_at",
+	domain.SortByUpdatedAt:        "updated_at",
+	domain.SortByScheduledWeekend: "scheduled_weekend",
+	domain.SortByEstimatedCost:    "estimated_cost",
+	domain.SortByTitle:            "title",
+	domain.SortByStatus:           "status",
+}
+
+// buildRequestWhere turns a filter into a WHERE clause and its positional args
+func buildRequestWhere(filter domain.RequestFilter) (string, []interface{}) {
+	where := `WHERE 1=1`
 	args := []interface{}{}
-	argIdx := 1
+	add := func(cond string, val interface{}) {
+		args = append(args, val)
+		where += fmt.Sprintf(" AND "+cond, len(args))
+	}
 
 	if filter.UserID != nil {
-		baseQuery += fmt.Sprintf(` AND user_id = $%d`, argIdx)
-		args = append(args, *filter.UserID)
-		argIdx++
+		add(`user_id = $%d`, *filter.UserID)
 	}
 	if filter.Status != nil {
-		baseQuery += fmt.Sprintf(` AND status = $%d`, argIdx)
-		args = append(args, *filter.Status)
-		argIdx++
+		add(`status = $%d`, *filter.Status)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, st := range filter.Statuses {
+			statuses[i] = string(st)
+		}
+		add(`status = ANY($%d)`, statuses)
 	}
 	if filter.RequestType != nil {
-		baseQuery += fmt.Sprintf(` AND request_type = $%d`, argIdx)
-		args = append(args, *filter.RequestType)
-		argIdx++
+		add(`request_type = $%d`, *filter.RequestType)
+	}
+	if filter.BuilderID != nil {
+		add(`builder_id = $%d`, *filter.BuilderID)
+	}
+	if filter.HostingType != nil {
+		add(`hosting_type = $%d`, *filter.HostingType)
+	}
+	if filter.Complexity != nil {
+		add(`complexity = $%d`, *filter.Complexity)
+	}
+	if filter.IsFree != nil {
+		add(`is_free = $%d`, *filter.IsFree)
 	}
+	if filter.Label != "" {
+		add(`$%d = ANY(labels)`, filter.Label)
+	}
+	if filter.Query != "" {
+		add(`search_vector @@ websearch_to_tsquery('english', $%d)`, filter.Query)
+	}
+	if filter.CreatedFrom != nil {
+		add(`created_at >= $%d`, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		add(`created_at < $%d`, *filter.CreatedTo)
+	}
+	if filter.ScheduledFrom != nil {
+		add(`scheduled_weekend >= $%d`, *filter.ScheduledFrom)
+	}
+	if filter.ScheduledTo != nil {
+		add(`scheduled_weekend < $%d`, *filter.ScheduledTo)
+	}
+	if filter.StatusChangedBefore != nil {
+		add(`status_changed_at < $%d`, *filter.StatusChangedBefore)
+	}
+	return where, args
+}
 
-	// Count
+// appendCursor adds the keyset condition for filter.After, matching the
+// direction of the (created_at, id) ordering
+func appendCursor(where string, args []interface{}, filter domain.RequestFilter) (string, []interface{}) {
+	if filter.After == nil {
+		return where, args
+	}
+	op := "<"
+	if filter.SortBy != "" && !filter.SortDesc {
+		op = ">"
+	}
+	args = append(args, filter.After.CreatedAt, filter.After.ID)
+	where += fmt.Sprintf(` AND (created_at, id) %s ($%d, $%d)`, op, len(args)-1, len(args))
+	return where, args
+}
+
+// buildRequestOrder returns the ORDER BY clause for a filter. Relevance
+// ordering ranks against the search query, which is appended to args.
+func buildRequestOrder(filter domain.RequestFilter, args []interface{}) (string, []interface{}) {
+	sortBy := filter.SortBy
+	desc := filter.SortDesc
+	if sortBy == "" {
+		sortBy, desc = domain.SortByCreatedAt, true
+		if filter.Query != "" {
+			sortBy = domain.SortByRelevance
+		}
+	}
+
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+
+	if sortBy == domain.SortByRelevance {
+		if filter.Query == "" {
+			return `ORDER BY created_at DESC, id DESC`, args
+		}
+		args = append(args, filter.Query)
+		return fmt.Sprintf(`ORDER BY ts_rank_cd(search_vector, websearch_to_tsquery('english', $%d)) %s, created_at DESC, id DESC`,
+			len(args), dir), args
+	}
+
+	col, ok := requestSortColumns[sortBy]
+	if !ok {
+		col = "created_at"
+	}
+	return fmt.Sprintf(`ORDER BY %s %s NULLS LAST, id %s`, col, dir, dir), args
+}
+
+func (r *requestRepo) List(ctx context.Context, filter domain.RequestFilter) ([]domain.BuildRequest, int, error) {
+	where, args := buildRequestWhere(filter)
+
+	// Count (optional — it scans every matching row)
 	var total int
-	countQuery := `SELECT COUNT(*) ` + baseQuery
-	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, err
+	if filter.IncludeTotal {
+		countQuery := `SELECT COUNT(*) FROM build_requests ` + where
+		if err := conn(ctx, r.db).QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	// Data with pagination
+	// Data with keyset or (deprecated) offset pagination
+	where, args = appendCursor(where, args, filter)
+	orderBy, args := buildRequestOrder(filter, args)
+	offset := filter.Offset
+	if filter.After != nil {
+		offset = 0
+	}
 	dataQuery := fmt.Sprintf(`SELECT id, user_id, title, description, request_type, status, complexity,
-		hosting_type, estimated_cost, is_free, delivery_url, created_at, updated_at %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
-		baseQuery, argIdx, argIdx+1)
-	args = append(args, filter.Limit, filter.Offset)
+		hosting_type, estimated_cost, currency, is_free, add_ons, pricing_version, labels, COALESCE(delivery_url, ''),
+		scheduled_weekend, builder_id, created_at, updated_at, version FROM build_requests %s %s LIMIT $%d OFFSET $%d`,
+		where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, offset)
 
-	rows, err := r.db.Query(ctx, dataQuery, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, dataQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -130,22 +313,30 @@ func (r *requestRepo) List(ctx context.Context, filter domain.RequestFilter) ([]
 	var requests []domain.BuildRequest
 	for rows.Next() {
 		var req domain.BuildRequest
+		var scheduled *time.Time
 		if err := rows.Scan(
 			&req.ID, &req.UserID, &req.Title, &req.Description,
 			&req.RequestType, &req.Status, &req.Complexity,
-			&req.HostingType, &req.EstimatedCost, &req.IsFree,
-			&req.DeliveryURL, &req.CreatedAt, &req.UpdatedAt,
+			&req.HostingType, &req.EstimatedCost, &req.Currency, &req.IsFree, &req.AddOns, &req.PricingVersion,
+			&req.Labels, &req.DeliveryURL, &scheduled, &req.BuilderID, &req.CreatedAt, &req.UpdatedAt, &req.Version,
 		); err != nil {
 			return nil, 0, err
 		}
+		if scheduled != nil {
+			req.ScheduledWeekend = *scheduled
+		}
+		req.EstimatedCost = req.EstimatedCost.In(req.Currency)
 		requests = append(requests, req)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
 	return requests, total, nil
 }
 
 func (r *requestRepo) CountByStatus(ctx context.Context, status domain.RequestStatus) (int, error) {
 	var count int
-	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM build_requests WHERE status = $1`, status).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT COUNT(*) FROM build_requests WHERE status = $1`, status).Scan(&count)
 	return count, err
 }
 
@@ -153,12 +344,12 @@ func (r *requestRepo) GetWeekendRequests(ctx context.Context, weekendStart time.
 	weekendEnd := weekendStart.AddDate(0, 0, 2) // Saturday + Sunday
 	query := `
 		SELECT id, user_id, title, description, request_type, status, complexity,
-		       hosting_type, estimated_cost, is_free, builder_id, created_at
+		       hosting_type, estimated_cost, currency, is_free, builder_id, created_at
 		FROM build_requests
 		WHERE scheduled_weekend >= $1 AND scheduled_weekend < $2
 		ORDER BY created_at ASC
 	`
-	rows, err := r.db.Query(ctx, query, weekendStart, weekendEnd)
+	rows, err := conn(ctx, r.db).Query(ctx, query, weekendStart, weekendEnd)
 	if err != nil {
 		return nil, err
 	}
@@ -170,12 +361,37 @@ func (r *requestRepo) GetWeekendRequests(ctx context.Context, weekendStart time.
 		if err := rows.Scan(
 			&req.ID, &req.UserID, &req.Title, &req.Description,
 			&req.RequestType, &req.Status, &req.Complexity,
-			&req.HostingType, &req.EstimatedCost, &req.IsFree,
+			&req.HostingType, &req.EstimatedCost, &req.Currency, &req.IsFree,
 			&req.BuilderID, &req.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
+		req.EstimatedCost = req.EstimatedCost.In(req.Currency)
 		requests = append(requests, req)
 	}
 	return requests, nil
 }
+
+// currencyOrDefault keeps rows created before a currency was known in INR
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return domain.DefaultCurrency
+	}
+	return currency
+}
+
+// nullableTime maps the zero time to NULL
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nonNilStrings keeps NOT NULL array columns from receiving NULL
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
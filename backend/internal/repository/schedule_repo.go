@@ -26,7 +26,7 @@ func (r *scheduleRepo) CreateSlot(ctx context.Context, slot *domain.WeekendSlot)
 		       max_projects, booked_projects, status, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
-	_, err := r.db.Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		slot.ID, slot.Date, slot.DayOfWeek, slot.TotalHours,
 		slot.BookedHours, slot.MaxProjects, slot.BookedProjects,
 		slot.Status, slot.CreatedAt,
@@ -37,14 +37,14 @@ func (r *scheduleRepo) CreateSlot(ctx context.Context, slot *domain.WeekendSlot)
 func (r *scheduleRepo) FindSlotByDate(ctx context.Context, date time.Time) (*domain.WeekendSlot, error) {
 	query := `
 		SELECT id, date, day_of_week, total_hours, booked_hours, 
-		       max_projects, booked_projects, status, created_at
+		       max_projects, booked_projects, status, version, created_at
 		FROM weekend_slots WHERE date = $1
 	`
 	slot := &domain.WeekendSlot{}
-	err := r.db.QueryRow(ctx, query, date).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, date).Scan(
 		&slot.ID, &slot.Date, &slot.DayOfWeek, &slot.TotalHours,
 		&slot.BookedHours, &slot.MaxProjects, &slot.BookedProjects,
-		&slot.Status, &slot.CreatedAt,
+		&slot.Status, &slot.Version, &slot.CreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -56,16 +56,24 @@ func (r *scheduleRepo) FindSlotByDate(ctx context.Context, date time.Time) (*dom
 }
 
 func (r *scheduleRepo) FindSlotByID(ctx context.Context, id uuid.UUID) (*domain.WeekendSlot, error) {
+	return r.findSlotByID(ctx, id, "")
+}
+
+func (r *scheduleRepo) LockSlot(ctx context.Context, id uuid.UUID) (*domain.WeekendSlot, error) {
+	return r.findSlotByID(ctx, id, "FOR UPDATE")
+}
+
+func (r *scheduleRepo) findSlotByID(ctx context.Context, id uuid.UUID, lock string) (*domain.WeekendSlot, error) {
 	query := `
 		SELECT id, date, day_of_week, total_hours, booked_hours, 
-		       max_projects, booked_projects, status, created_at
+		       max_projects, booked_projects, status, version, created_at
 		FROM weekend_slots WHERE id = $1
-	`
+	` + lock
 	slot := &domain.WeekendSlot{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&slot.ID, &slot.Date, &slot.DayOfWeek, &slot.TotalHours,
 		&slot.BookedHours, &slot.MaxProjects, &slot.BookedProjects,
-		&slot.Status, &slot.CreatedAt,
+		&slot.Status, &slot.Version, &slot.CreatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -76,25 +84,34 @@ func (r *scheduleRepo) FindSlotByID(ctx context.Context, id uuid.UUID) (*domain.
 	return slot, nil
 }
 
+// UpdateSlot saves the slot's capacity and bookings unless someone else
+// changed the slot since it was read
 func (r *scheduleRepo) UpdateSlot(ctx context.Context, slot *domain.WeekendSlot) error {
 	query := `
-		UPDATE weekend_slots SET booked_hours=$1, booked_projects=$2, status=$3
-		WHERE id=$4
+		UPDATE weekend_slots SET total_hours=$1, booked_hours=$2, booked_projects=$3, status=$4, version=version+1
+		WHERE id=$5 AND version=$6
 	`
-	_, err := r.db.Exec(ctx, query, slot.BookedHours, slot.BookedProjects, slot.Status, slot.ID)
-	return err
+	result, err := conn(ctx, r.db).Exec(ctx, query, slot.TotalHours, slot.BookedHours, slot.BookedProjects, slot.Status, slot.ID, slot.Version)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return domain.ErrVersionConflict
+	}
+	slot.Version++
+	return nil
 }
 
 func (r *scheduleRepo) ListUpcomingSlots(ctx context.Context, limit int) ([]domain.WeekendSlot, error) {
 	query := `
 		SELECT id, date, day_of_week, total_hours, booked_hours, 
-		       max_projects, booked_projects, status, created_at
+		       max_projects, booked_projects, status, version, created_at
 		FROM weekend_slots
 		WHERE date >= CURRENT_DATE
 		ORDER BY date ASC
 		LIMIT $1
 	`
-	rows, err := r.db.Query(ctx, query, limit)
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +123,7 @@ func (r *scheduleRepo) ListUpcomingSlots(ctx context.Context, limit int) ([]doma
 		if err := rows.Scan(
 			&s.ID, &s.Date, &s.DayOfWeek, &s.TotalHours,
 			&s.BookedHours, &s.MaxProjects, &s.BookedProjects,
-			&s.Status, &s.CreatedAt,
+			&s.Status, &s.Version, &s.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -121,7 +138,7 @@ func (r *scheduleRepo) CreateEntry(ctx context.Context, entry *domain.ScheduleEn
 		       status, notes, start_time, end_time, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
-	_, err := r.db.Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		entry.ID, entry.RequestID, entry.SlotID, entry.BuilderID,
 		entry.Hours, entry.Status, entry.Notes,
 		entry.StartTime, entry.EndTime, entry.CreatedAt, entry.UpdatedAt,
@@ -136,7 +153,7 @@ func (r *scheduleRepo) FindEntriesBySlot(ctx context.Context, slotID uuid.UUID)
 		FROM schedule_entries WHERE slot_id = $1
 		ORDER BY start_time ASC
 	`
-	rows, err := r.db.Query(ctx, query, slotID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, slotID)
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +180,7 @@ func (r *scheduleRepo) FindEntriesByRequest(ctx context.Context, requestID uuid.
 		       status, notes, start_time, end_time, created_at, updated_at
 		FROM schedule_entries WHERE request_id = $1
 	`
-	rows, err := r.db.Query(ctx, query, requestID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, requestID)
 	if err != nil {
 		return nil, err
 	}
@@ -184,11 +201,32 @@ func (r *scheduleRepo) FindEntriesByRequest(ctx context.Context, requestID uuid.
 	return entries, nil
 }
 
+func (r *scheduleRepo) FindEntryByID(ctx context.Context, id uuid.UUID) (*domain.ScheduleEntry, error) {
+	query := `
+		SELECT id, request_id, slot_id, builder_id, estimated_hours,
+		       status, notes, start_time, end_time, created_at, updated_at
+		FROM schedule_entries WHERE id = $1
+	`
+	e := &domain.ScheduleEntry{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
+		&e.ID, &e.RequestID, &e.SlotID, &e.BuilderID,
+		&e.Hours, &e.Status, &e.Notes,
+		&e.StartTime, &e.EndTime, &e.CreatedAt, &e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
 func (r *scheduleRepo) UpdateEntry(ctx context.Context, entry *domain.ScheduleEntry) error {
 	query := `
 		UPDATE schedule_entries SET builder_id=$1, status=$2, notes=$3, updated_at=$4
 		WHERE id=$5
 	`
-	_, err := r.db.Exec(ctx, query, entry.BuilderID, entry.Status, entry.Notes, time.Now(), entry.ID)
+	_, err := conn(ctx, r.db).Exec(ctx, query, entry.BuilderID, entry.Status, entry.Notes, time.Now(), entry.ID)
 	return err
 }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type standbyRepo struct {
+	db *pgxpool.Pool
+}
+
+// NewStandbyRepository creates a new standby (waitlist) repository
+func NewStandbyRepository(db *pgxpool.Pool) domain.StandbyRepository {
+	return &standbyRepo{db: db}
+}
+
+const standbyColumns = `id, request_id, slot_id, estimated_hours, status, promoted_slot_id, created_at, updated_at, promoted_at`
+
+func scanStandby(row pgx.Row) (*domain.StandbyEntry, error) {
+	e := &domain.StandbyEntry{}
+	err := row.Scan(&e.ID, &e.RequestID, &e.SlotID, &e.Hours, &e.Status, &e.PromotedSlotID, &e.CreatedAt, &e.UpdatedAt, &e.PromotedAt)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *standbyRepo) Create(ctx context.Context, e *domain.StandbyEntry) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO standby_entries (id, request_id, slot_id, estimated_hours, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		e.ID, e.RequestID, e.SlotID, e.Hours, e.Status, e.CreatedAt, e.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrAlreadyOnStandby
+	}
+	return err
+}
+
+func (r *standbyRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.StandbyEntry, error) {
+	e, err := scanStandby(conn(ctx, r.db).QueryRow(ctx, `SELECT `+standbyColumns+` FROM standby_entries WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+func (r *standbyRepo) FindWaitingByRequest(ctx context.Context, requestID uuid.UUID) (*domain.StandbyEntry, error) {
+	e, err := scanStandby(conn(ctx, r.db).QueryRow(ctx,
+		`SELECT `+standbyColumns+` FROM standby_entries WHERE request_id = $1 AND status = $2`,
+		requestID, domain.StandbyWaiting))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+func (r *standbyRepo) ListWaiting(ctx context.Context, slotID *uuid.UUID) ([]domain.StandbyEntry, error) {
+	query := `SELECT ` + standbyColumns + ` FROM standby_entries WHERE status = $1`
+	args := []interface{}{domain.StandbyWaiting}
+	if slotID != nil {
+		query += ` AND (slot_id = $2 OR slot_id IS NULL)`
+		args = append(args, *slotID)
+	}
+	rows, err := conn(ctx, r.db).Query(ctx, query+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.StandbyEntry{}
+	for rows.Next() {
+		e, err := scanStandby(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *standbyRepo) Update(ctx context.Context, e *domain.StandbyEntry) error {
+	e.UpdatedAt = time.Now()
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE standby_entries SET status=$1, promoted_slot_id=$2, promoted_at=$3, updated_at=$4
+		WHERE id=$5`,
+		e.Status, e.PromotedSlotID, e.PromotedAt, e.UpdatedAt, e.ID,
+	)
+	return err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// querier is what pgxpool.Pool and pgx.Tx have in common
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+type txKey struct{}
+
+// txState is the transaction carried by a context and the work waiting for
+// it to commit
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func(ctx context.Context)
+}
+
+type transactor struct {
+	db *pgxpool.Pool
+}
+
+// NewTransactor creates a transactor over the pool
+func NewTransactor(db *pgxpool.Pool) domain.Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for _, after := range state.afterCommit {
+		after(ctx)
+	}
+	return nil
+}
+
+func (t *transactor) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn(ctx)
+}
+
+// conn returns the transaction carried by ctx, or the pool outside one
+func conn(ctx context.Context, db *pgxpool.Pool) querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
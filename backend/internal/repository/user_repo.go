@@ -63,13 +63,21 @@ func (r *userRepo) FindByEmail(ctx context.Context, email string) (*domain.User,
 }
 
 func (r *userRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.findByID(ctx, id, "")
+}
+
+func (r *userRepo) LockByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.findByID(ctx, id, "FOR UPDATE")
+}
+
+func (r *userRepo) findByID(ctx context.Context, id uuid.UUID, lock string) (*domain.User, error) {
 	query := `
 		SELECT id, email, password_hash, full_name, student_id, role, 
 		       is_verified, provider, provider_id, created_at, updated_at
 		FROM users WHERE id = $1
-	`
+	` + lock
 	user := &domain.User{}
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.FullName,
 		&user.StudentID, &user.Role, &user.IsVerified,
 		&user.Provider, &user.ProviderID,
@@ -123,18 +131,31 @@ func (r *userRepo) VerifyOTP(ctx context.Context, email, otp string) error {
 	return nil
 }
 
-func (r *userRepo) List(ctx context.Context, limit, offset int) ([]domain.User, int, error) {
-	countQuery := `SELECT COUNT(*) FROM users`
+func (r *userRepo) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int, error) {
 	var total int
-	if err := r.db.QueryRow(ctx, countQuery).Scan(&total); err != nil {
-		return nil, 0, err
+	if filter.IncludeTotal {
+		countQuery := `SELECT COUNT(*) FROM users`
+		if err := r.db.QueryRow(ctx, countQuery).Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
-	query := `
-		SELECT id, email, full_name, student_id, role, is_verified, created_at, updated_at
-		FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2
-	`
-	rows, err := r.db.Query(ctx, query, limit, offset)
+	var rows pgx.Rows
+	var err error
+	if filter.After != nil {
+		query := `
+			SELECT id, email, full_name, student_id, role, is_verified, created_at, updated_at
+			FROM users WHERE (created_at, id) < ($1, $2)
+			ORDER BY created_at DESC, id DESC LIMIT $3
+		`
+		rows, err = r.db.Query(ctx, query, filter.After.CreatedAt, filter.After.ID, filter.Limit)
+	} else {
+		query := `
+			SELECT id, email, full_name, student_id, role, is_verified, created_at, updated_at
+			FROM users ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2
+		`
+		rows, err = r.db.Query(ctx, query, filter.Limit, filter.Offset)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
 	"github.com/makeitexist/backend/internal/handler"
 	"github.com/makeitexist/backend/internal/middleware"
 )
@@ -16,10 +17,28 @@ import (
 // Setup configures all routes for the application
 func Setup(
 	cfg *config.Config,
+	idempotencyRepo domain.IdempotencyRepository,
 	authHandler *handler.AuthHandler,
 	requestHandler *handler.RequestHandler,
 	scheduleHandler *handler.ScheduleHandler,
 	adminHandler *handler.AdminHandler,
+	pricingHandler *handler.PricingHandler,
+	quoteHandler *handler.QuoteHandler,
+	paymentHandler *handler.PaymentHandler,
+	invoiceHandler *handler.InvoiceHandler,
+	promotionHandler *handler.PromotionHandler,
+	intakeHandler *handler.IntakeHandler,
+	deliverableHandler *handler.DeliverableHandler,
+	acceptanceHandler *handler.AcceptanceHandler,
+	exportHandler *handler.ExportHandler,
+	importHandler *handler.ImportHandler,
+	automationHandler *handler.AutomationHandler,
+	notificationHandler *handler.NotificationHandler,
+	domainHandler *handler.DomainVerificationHandler,
+	certificateHandler *handler.CertificateHandler,
+	queueHandler *handler.QueueHandler,
+	plannerHandler *handler.PlannerHandler,
+	availabilityHandler *handler.AvailabilityHandler,
 ) *gin.Engine {
 	// Set Gin mode based on environment
 	if cfg.Server.Env == "production" {
@@ -43,6 +62,9 @@ func Setup(
 		})
 	})
 
+	// ACME HTTP-01 challenges, fetched by the CA on whitelabel domains
+	r.GET("/.well-known/acme-challenge/:token", certificateHandler.Challenge)
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 
@@ -54,9 +76,13 @@ func Setup(
 		auth.POST("/login", authHandler.Login)            // admin password fallback
 	}
 
+	// Payment provider webhooks (verified by signature, not JWT)
+	v1.POST("/webhooks/payments/:provider", paymentHandler.Webhook)
+
 	// === Protected Routes (Auth Required) ===
 	protected := v1.Group("")
 	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.IdempotencyMiddleware(idempotencyRepo, cfg.Idempotency.TTL))
 	{
 		// Profile
 		protected.GET("/auth/profile", authHandler.GetProfile)
@@ -66,7 +92,34 @@ func Setup(
 		{
 			requests.POST("", requestHandler.Create)
 			requests.GET("", requestHandler.ListMyRequests)
+			requests.POST("/check", intakeHandler.Check)
 			requests.GET("/:id", requestHandler.GetByID)
+			requests.GET("/:id/pricing", pricingHandler.GetRequestBreakdown)
+			requests.GET("/:id/quotes", quoteHandler.ListForRequest)
+			requests.POST("/:id/quotes/:quoteId/accept", quoteHandler.Accept)
+			requests.POST("/:id/quotes/:quoteId/decline", quoteHandler.Decline)
+			requests.GET("/:id/payments", paymentHandler.GetSummary)
+			requests.POST("/:id/payments", paymentHandler.CreateIntent)
+			requests.GET("/:id/invoices", invoiceHandler.ListForRequest)
+			requests.GET("/:id/invoices/:invoiceId/pdf", invoiceHandler.Download)
+			requests.GET("/:id/discounts", promotionHandler.ListForRequest)
+			requests.POST("/:id/discounts", promotionHandler.Redeem)
+			requests.GET("/:id/deliverables", deliverableHandler.ListForRequest)
+			requests.GET("/:id/acceptance", acceptanceHandler.GetStatus)
+			requests.POST("/:id/acceptance", acceptanceHandler.Accept)
+			requests.POST("/:id/revisions", acceptanceHandler.RequestRevision)
+			requests.PUT("/:id/rating", acceptanceHandler.Rate)
+			requests.GET("/:id/domain", domainHandler.Get)
+			requests.POST("/:id/domain/verify", domainHandler.Verify)
+			requests.GET("/:id/certificate", certificateHandler.Get)
+			requests.GET("/:id/queue-position", queueHandler.Position)
+		}
+
+		// Pricing
+		pricing := protected.Group("/pricing")
+		{
+			pricing.GET("", pricingHandler.GetActiveRules)
+			pricing.POST("/estimate", pricingHandler.Estimate)
 		}
 
 		// Schedule
@@ -76,20 +129,89 @@ func Setup(
 			schedule.GET("/slots", scheduleHandler.GetUpcomingSlots)
 		}
 
+		// Notifications
+		protected.GET("/notifications", notificationHandler.List)
+		protected.POST("/notifications/:id/read", notificationHandler.MarkRead)
+
 	}
 
 	// === Admin Routes (Admin Auth Required) ===
 	admin := v1.Group("/admin")
 	admin.Use(middleware.AuthMiddleware(cfg))
 	admin.Use(middleware.AdminOnly())
+	admin.Use(middleware.IdempotencyMiddleware(idempotencyRepo, cfg.Idempotency.TTL))
 	{
 		admin.GET("/dashboard", adminHandler.Dashboard)
+		admin.GET("/builders/quality", acceptanceHandler.BuilderQuality)
 		admin.GET("/requests", requestHandler.ListAll)
+		admin.POST("/requests/bulk", requestHandler.BulkUpdate)
+		admin.GET("/exports/:dataset", exportHandler.Export)
+		admin.GET("/exports/:dataset/columns", exportHandler.Columns)
+		admin.POST("/imports", importHandler.Upload)
+		admin.GET("/imports/:id", importHandler.Get)
+		admin.PUT("/imports/:id/mapping", importHandler.SetMapping)
+		admin.POST("/imports/:id/dry-run", importHandler.DryRun)
+		admin.POST("/imports/:id/commit", importHandler.Commit)
 		admin.PUT("/requests/:id", requestHandler.Update)
+		admin.GET("/requests/:id/quotes", quoteHandler.ListForRequest)
+		admin.POST("/requests/:id/quotes", quoteHandler.Create)
+		admin.POST("/quotes/:quoteId/send", quoteHandler.Send)
+		admin.POST("/payments/:intentId/refund", paymentHandler.Refund)
+		admin.POST("/requests/:id/invoices", invoiceHandler.Issue)
+		admin.POST("/requests/:id/waivers", promotionHandler.GrantWaiver)
+		admin.DELETE("/requests/:id/discounts/:discountId", promotionHandler.RemoveDiscount)
+		admin.POST("/requests/:id/certificate", certificateHandler.Provision)
+		admin.GET("/requests/:id/certificate/pem", certificateHandler.Download)
+		admin.POST("/requests/:id/deliverables", deliverableHandler.Create)
+		admin.PATCH("/requests/:id/deliverables/:deliverableId", deliverableHandler.Update)
+		admin.POST("/requests/:id/deliverables/:deliverableId/artifacts", deliverableHandler.AddArtifact)
 		admin.POST("/schedule/generate", scheduleHandler.GenerateSlots)
+		admin.POST("/schedule/entries", scheduleHandler.CreateEntry)
+		admin.PUT("/schedule/entries/:id", scheduleHandler.MoveEntry)
+		admin.DELETE("/schedule/entries/:id", scheduleHandler.DeleteEntry)
+		admin.GET("/schedule/plan", plannerHandler.Propose)
+		admin.POST("/schedule/plan/apply", plannerHandler.Apply)
+		admin.POST("/schedule/standby", scheduleHandler.CreateStandby)
+		admin.GET("/schedule/standby", scheduleHandler.ListStandby)
+		admin.DELETE("/schedule/standby/:id", scheduleHandler.CancelStandby)
 		admin.GET("/users", adminHandler.ListUsers)
 		admin.PUT("/users/:id/reset-password", adminHandler.ResetPassword)
 		admin.POST("/create-admin", adminHandler.CreateOrUpdateAdmin)
+		admin.GET("/users/:id/quota-override", intakeHandler.GetOverride)
+		admin.PUT("/users/:id/quota-override", intakeHandler.GrantOverride)
+		admin.DELETE("/users/:id/quota-override", intakeHandler.RevokeOverride)
+		admin.PUT("/users/:id/graduation-year", queueHandler.SetGraduationYear)
+		admin.GET("/queue", queueHandler.List)
+
+		// Builder availability (the caller's own)
+		admin.GET("/availability", availabilityHandler.Calendar)
+		admin.POST("/availability/patterns", availabilityHandler.CreatePattern)
+		admin.DELETE("/availability/patterns/:id", availabilityHandler.DeletePattern)
+		admin.PUT("/availability/days/:date", availabilityHandler.SetDay)
+		admin.DELETE("/availability/days/:date", availabilityHandler.ClearDay)
+
+		// Pricing rules
+		admin.GET("/pricing/rules", pricingHandler.ListRuleSets)
+		admin.POST("/pricing/rules", pricingHandler.CreateRuleSet)
+		admin.GET("/pricing/rules/:version", pricingHandler.GetRuleSet)
+		admin.GET("/pricing/rules/:version/impact", pricingHandler.PreviewImpact)
+		admin.POST("/pricing/rules/:version/activate", pricingHandler.ActivateRuleSet)
+
+		// Promotions
+		admin.GET("/promotions/codes", promotionHandler.ListCodes)
+		admin.POST("/promotions/codes", promotionHandler.CreateCode)
+		admin.PATCH("/promotions/codes/:codeId", promotionHandler.UpdateCode)
+		admin.GET("/promotions/report", promotionHandler.Report)
+
+		// Automation rules
+		admin.GET("/automation/rules", automationHandler.ListRules)
+		admin.POST("/automation/rules", automationHandler.CreateRule)
+		admin.GET("/automation/rules/:ruleId", automationHandler.GetRule)
+		admin.PUT("/automation/rules/:ruleId", automationHandler.UpdateRule)
+		admin.DELETE("/automation/rules/:ruleId", automationHandler.DeleteRule)
+		admin.GET("/automation/rules/:ruleId/history", automationHandler.History)
+		admin.POST("/automation/rules/:ruleId/preview", automationHandler.Preview)
+		admin.POST("/automation/rules/:ruleId/run", automationHandler.Run)
 	}
 
 	// ── Serve Flutter Web Frontend (SPA) ─────────────────────────
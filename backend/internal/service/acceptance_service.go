@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type acceptanceService struct {
+	transactor         domain.Transactor
+	acceptanceRepo     domain.AcceptanceRepository
+	requestRepo        domain.BuildRequestRepository
+	deliverableService domain.DeliverableService
+	lifecycle          *domain.Lifecycle
+	cfg                config.DeliveryConfig
+}
+
+// NewAcceptanceService creates a new acceptance service. Sign-offs and
+// revisions move the request through the lifecycle, so its guards and
+// observers apply as for any other status change. The review and the status
+// change are written in one transaction.
+func NewAcceptanceService(
+	transactor domain.Transactor,
+	acceptanceRepo domain.AcceptanceRepository,
+	requestRepo domain.BuildRequestRepository,
+	deliverableService domain.DeliverableService,
+	lifecycle *domain.Lifecycle,
+	cfg config.DeliveryConfig,
+) domain.AcceptanceService {
+	return &acceptanceService{
+		transactor:         transactor,
+		acceptanceRepo:     acceptanceRepo,
+		requestRepo:        requestRepo,
+		deliverableService: deliverableService,
+		lifecycle:          lifecycle,
+		cfg:                cfg,
+	}
+}
+
+// CheckTransition refuses to complete a request the student has not accepted
+func (s *acceptanceService) CheckTransition(ctx context.Context, req *domain.BuildRequest, to domain.RequestStatus) error {
+	if !s.cfg.RequireAcceptance || to != domain.StatusCompleted {
+		return nil
+	}
+	reviews, err := s.acceptanceRepo.ListReviews(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list delivery reviews: %w", err)
+	}
+	if len(reviews) == 0 || reviews[len(reviews)-1].Outcome != domain.ReviewAccepted {
+		return domain.ErrAcceptanceRequired
+	}
+	return nil
+}
+
+func (s *acceptanceService) Status(ctx context.Context, requestID uuid.UUID) (*domain.AcceptanceStatus, error) {
+	req, err := s.loadRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(ctx, req)
+}
+
+func (s *acceptanceService) Accept(ctx context.Context, userID, requestID uuid.UUID, in *domain.AcceptDeliveryRequest) (*domain.AcceptanceStatus, error) {
+	req, err := s.reviewableRequest(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.status(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Our own guard would refuse until the review is saved; the others run first
+	if err := s.lifecycle.CheckExcept(ctx, req, domain.StatusCompleted, s); err != nil {
+		return nil, err
+	}
+	review := &domain.DeliveryReview{
+		ID:         uuid.New(),
+		RequestID:  req.ID,
+		Round:      len(status.Reviews) + 1,
+		Outcome:    domain.ReviewAccepted,
+		Notes:      in.Notes,
+		ReviewedBy: userID,
+		CreatedAt:  time.Now(),
+	}
+	previousStatus := req.Status
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.acceptanceRepo.AddReview(ctx, review); err != nil {
+			return fmt.Errorf("failed to record acceptance: %w", err)
+		}
+		now := time.Now()
+		req.Status = domain.StatusCompleted
+		req.CompletedAt = &now
+		req.UpdatedAt = now
+		if err := s.requestRepo.Update(ctx, req); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.lifecycle.Notify(ctx, req, previousStatus)
+
+	status.Reviews = append(status.Reviews, *review)
+	status.Status = req.Status
+	status.Summarize()
+	return status, nil
+}
+
+func (s *acceptanceService) RequestRevision(ctx context.Context, userID, requestID uuid.UUID, in *domain.RequestRevisionRequest) (*domain.AcceptanceStatus, error) {
+	req, err := s.reviewableRequest(ctx, userID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	status, err := s.status(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if status.RevisionsLeft == 0 {
+		return nil, fmt.Errorf("%w (%d included); contact your builder to arrange more",
+			domain.ErrRevisionLimitReached, status.IncludedRevisions)
+	}
+
+	review := &domain.DeliveryReview{
+		ID:         uuid.New(),
+		RequestID:  req.ID,
+		Round:      len(status.Reviews) + 1,
+		Outcome:    domain.ReviewRevisionRequested,
+		Notes:      in.Notes,
+		ReviewedBy: userID,
+		CreatedAt:  time.Now(),
+	}
+	previousStatus := req.Status
+	handled := false
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Back to building: through the deliverables when there are any, so
+		// they stay consistent with the request
+		var err error
+		if handled, err = s.deliverableService.Reopen(ctx, req); err != nil {
+			return err
+		}
+		if !handled {
+			if err := s.lifecycle.Check(ctx, req, domain.StatusBuilding); err != nil {
+				return err
+			}
+			req.Status = domain.StatusBuilding
+			req.UpdatedAt = time.Now()
+			if err := s.requestRepo.Update(ctx, req); err != nil {
+				return fmt.Errorf("failed to update request: %w", err)
+			}
+		}
+		if err := s.acceptanceRepo.AddReview(ctx, review); err != nil {
+			return fmt.Errorf("failed to record revision request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !handled {
+		s.lifecycle.Notify(ctx, req, previousStatus)
+	}
+
+	status.Reviews = append(status.Reviews, *review)
+	status.Status = req.Status
+	status.Summarize()
+	return status, nil
+}
+
+func (s *acceptanceService) Rate(ctx context.Context, userID, requestID uuid.UUID, in *domain.RateRequest) (*domain.Rating, error) {
+	req, err := s.loadRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	status, err := s.status(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Accepted || req.Status != domain.StatusCompleted {
+		return nil, domain.ErrNotAccepted
+	}
+
+	now := time.Now()
+	rating := &domain.Rating{
+		ID:           uuid.New(),
+		RequestID:    req.ID,
+		UserID:       userID,
+		BuilderID:    req.BuilderID,
+		BuilderScore: in.BuilderScore,
+		RequestScore: in.RequestScore,
+		Feedback:     in.Feedback,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.acceptanceRepo.UpsertRating(ctx, rating); err != nil {
+		return nil, fmt.Errorf("failed to save rating: %w", err)
+	}
+	return rating, nil
+}
+
+func (s *acceptanceService) BuilderQuality(ctx context.Context) ([]domain.BuilderQuality, error) {
+	quality, err := s.acceptanceRepo.BuilderQuality(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute builder quality: %w", err)
+	}
+	if quality == nil {
+		quality = []domain.BuilderQuality{}
+	}
+	for i := range quality {
+		q := &quality[i]
+		q.AvgBuilderScore = round2(q.AvgBuilderScore)
+		q.AvgRequestScore = round2(q.AvgRequestScore)
+		q.AvgRevisions = round2(q.AvgRevisions)
+		q.FirstTimeAcceptance = round2(q.FirstTimeAcceptance)
+	}
+	return quality, nil
+}
+
+func (s *acceptanceService) loadRequest(ctx context.Context, requestID uuid.UUID) (*domain.BuildRequest, error) {
+	req, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	return req, nil
+}
+
+// reviewableRequest loads a request the given student can sign off now
+func (s *acceptanceService) reviewableRequest(ctx context.Context, userID, requestID uuid.UUID) (*domain.BuildRequest, error) {
+	req, err := s.loadRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	if req.Status != domain.StatusReview {
+		return nil, domain.ErrNotInReview
+	}
+	return req, nil
+}
+
+func (s *acceptanceService) status(ctx context.Context, req *domain.BuildRequest) (*domain.AcceptanceStatus, error) {
+	reviews, err := s.acceptanceRepo.ListReviews(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delivery reviews: %w", err)
+	}
+	if reviews == nil {
+		reviews = []domain.DeliveryReview{}
+	}
+	rating, err := s.acceptanceRepo.FindRating(ctx, req.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find rating: %w", err)
+	}
+
+	status := &domain.AcceptanceStatus{
+		RequestID:         req.ID,
+		Status:            req.Status,
+		IncludedRevisions: s.cfg.IncludedRevisions,
+		Reviews:           reviews,
+		Rating:            rating,
+	}
+	status.Summarize()
+	return status, nil
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -354,8 +354,9 @@ func (s *authService) AdminResetPassword(ctx context.Context, targetUserID uuid.
 	return nil
 }
 
-func (s *authService) ListUsers(ctx context.Context, limit, offset int) ([]domain.User, int, error) {
-	return s.userRepo.List(ctx, limit, offset)
+func (s *authService) ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, int, error) {
+	filter.Limit = domain.ClampPageSize(filter.Limit, domain.MaxPageSize)
+	return s.userRepo.List(ctx, filter)
 }
 
 func (s *authService) CreateOrUpdateAdmin(ctx context.Context, user *domain.User) error {
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// maxAutomationHits caps the history returned for a rule
+const maxAutomationHits = 200
+
+type automationService struct {
+	automationRepo   domain.AutomationRepository
+	notificationRepo domain.NotificationRepository
+	requestService   domain.BuildRequestService
+}
+
+// NewAutomationService creates a new automation service. Transitions and
+// labels go through the request service, so rules pass the same lifecycle
+// guards as admin edits.
+func NewAutomationService(
+	automationRepo domain.AutomationRepository,
+	notificationRepo domain.NotificationRepository,
+	requestService domain.BuildRequestService,
+) domain.AutomationService {
+	return &automationService{
+		automationRepo:   automationRepo,
+		notificationRepo: notificationRepo,
+		requestService:   requestService,
+	}
+}
+
+func (s *automationService) CreateRule(ctx context.Context, createdBy uuid.UUID, req *domain.SaveAutomationRuleRequest) (*domain.AutomationRule, error) {
+	now := time.Now()
+	rule := &domain.AutomationRule{
+		ID:        uuid.New(),
+		CreatedBy: &createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyAutomationRule(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.automationRepo.CreateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create automation rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *automationService) GetRule(ctx context.Context, id uuid.UUID) (*domain.AutomationRule, error) {
+	rule, err := s.automationRepo.FindRule(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find automation rule: %w", err)
+	}
+	if rule == nil {
+		return nil, domain.ErrAutomationRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *automationService) ListRules(ctx context.Context) ([]domain.AutomationRule, error) {
+	rules, err := s.automationRepo.ListRules(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automation rules: %w", err)
+	}
+	if rules == nil {
+		rules = []domain.AutomationRule{}
+	}
+	return rules, nil
+}
+
+func (s *automationService) UpdateRule(ctx context.Context, id uuid.UUID, req *domain.SaveAutomationRuleRequest) (*domain.AutomationRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled == nil {
+		enabled := rule.Enabled
+		req.Enabled = &enabled
+	}
+	if err := applyAutomationRule(rule, req); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = time.Now()
+	if err := s.automationRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update automation rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *automationService) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if err := s.automationRepo.DeleteRule(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrAutomationRuleNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to delete automation rule: %w", err)
+	}
+	return nil
+}
+
+func (s *automationService) ListHits(ctx context.Context, id uuid.UUID) ([]domain.AutomationHit, error) {
+	if _, err := s.GetRule(ctx, id); err != nil {
+		return nil, err
+	}
+	hits, err := s.automationRepo.ListHits(ctx, id, maxAutomationHits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automation history: %w", err)
+	}
+	if hits == nil {
+		hits = []domain.AutomationHit{}
+	}
+	return hits, nil
+}
+
+func (s *automationService) Preview(ctx context.Context, id uuid.UUID) (*domain.AutomationRun, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, rule, true)
+}
+
+func (s *automationService) Run(ctx context.Context, id uuid.UUID) (*domain.AutomationRun, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, rule, false)
+}
+
+// RunAll runs every enabled rule in creation order. A failing rule is logged
+// and does not stop the others.
+func (s *automationService) RunAll(ctx context.Context) ([]domain.AutomationRun, error) {
+	rules, err := s.automationRepo.ListRules(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automation rules: %w", err)
+	}
+	runs := make([]domain.AutomationRun, 0, len(rules))
+	for i := range rules {
+		run, err := s.run(ctx, &rules[i], false)
+		if err != nil {
+			log.Error().Err(err).Str("rule_id", rules[i].ID.String()).Msg("Automation rule failed")
+			continue
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+// run finds the requests the rule applies to and, unless dryRun, claims each
+// one and acts on it. A claimed request is not retried for the same stay in
+// its status even when the action fails; the failure is kept in the history.
+func (s *automationService) run(ctx context.Context, rule *domain.AutomationRule, dryRun bool) (*domain.AutomationRun, error) {
+	now := time.Now()
+	matches, err := s.automationRepo.FindMatches(ctx, rule, now, domain.MaxAutomationMatches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find matching requests: %w", err)
+	}
+	run := &domain.AutomationRun{
+		RuleID:  rule.ID,
+		Name:    rule.Name,
+		Action:  rule.Action,
+		DryRun:  dryRun,
+		Matched: len(matches),
+		Items:   make([]domain.AutomationRunItem, len(matches)),
+	}
+	for i, m := range matches {
+		run.Items[i].AutomationMatch = m
+	}
+	if dryRun {
+		return run, nil
+	}
+
+	// Claim first so a concurrent run on another instance cannot act twice
+	hits := make([]*domain.AutomationHit, len(matches))
+	var claimed []int
+	for i, m := range matches {
+		hit := &domain.AutomationHit{
+			ID:              uuid.New(),
+			RuleID:          rule.ID,
+			RequestID:       m.RequestID,
+			StatusChangedAt: m.StatusChangedAt,
+			CreatedAt:       now,
+		}
+		ok, err := s.automationRepo.ClaimHit(ctx, hit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record automation run: %w", err)
+		}
+		if !ok {
+			run.Items[i].Error = "already handled by another run"
+			run.Failed++
+			continue
+		}
+		hits[i] = hit
+		claimed = append(claimed, i)
+	}
+
+	failures := s.act(ctx, rule, run.Items, claimed)
+	for _, i := range claimed {
+		hit := hits[i]
+		if failure, failed := failures[i]; failed {
+			hit.Error = failure
+			run.Items[i].Error = failure
+			run.Failed++
+		} else {
+			hit.OK = true
+			run.Items[i].OK = true
+			run.Succeeded++
+		}
+		if err := s.automationRepo.FinishHit(ctx, hit); err != nil {
+			log.Error().Err(err).Str("rule_id", rule.ID.String()).Str("request_id", hit.RequestID.String()).
+				Msg("Failed to record automation result")
+		}
+	}
+
+	if err := s.automationRepo.MarkRun(ctx, rule.ID, now); err != nil {
+		log.Error().Err(err).Str("rule_id", rule.ID.String()).Msg("Failed to record automation run time")
+	}
+	return run, nil
+}
+
+// act applies the rule to the claimed items and returns the failures by item
+// index
+func (s *automationService) act(ctx context.Context, rule *domain.AutomationRule, items []domain.AutomationRunItem, claimed []int) map[int]string {
+	failures := map[int]string{}
+	if len(claimed) == 0 {
+		return failures
+	}
+
+	if rule.Action == domain.AutomationNotify {
+		for _, i := range claimed {
+			if err := s.notify(ctx, rule, &items[i].AutomationMatch); err != nil {
+				failures[i] = err.Error()
+			}
+		}
+		return failures
+	}
+
+	bulk := &domain.BulkUpdateRequest{Mode: domain.BulkBestEffort}
+	switch {
+	case rule.Action == domain.AutomationAddLabel:
+		bulk.Operation = domain.BulkAddLabel
+		bulk.Label = rule.Label
+	case *rule.TargetStatus == domain.StatusCancelled:
+		bulk.Operation = domain.BulkCancel
+	default:
+		bulk.Operation = domain.BulkSetStatus
+		bulk.Status = rule.TargetStatus
+	}
+	for _, i := range claimed {
+		bulk.IDs = append(bulk.IDs, items[i].RequestID)
+	}
+
+	result, err := s.requestService.BulkUpdate(ctx, bulk)
+	if err != nil {
+		for _, i := range claimed {
+			failures[i] = err.Error()
+		}
+		return failures
+	}
+	outcome := make(map[uuid.UUID]domain.BulkItemResult, len(result.Items))
+	for _, item := range result.Items {
+		outcome[item.RequestID] = item
+	}
+	for _, i := range claimed {
+		item, found := outcome[items[i].RequestID]
+		switch {
+		case !found:
+			failures[i] = "request was not updated"
+		case !item.OK:
+			failures[i] = item.Error
+		}
+	}
+	return failures
+}
+
+func (s *automationService) notify(ctx context.Context, rule *domain.AutomationRule, m *domain.AutomationMatch) error {
+	requestID := m.RequestID
+	n := &domain.Notification{
+		ID:        uuid.New(),
+		RequestID: &requestID,
+		Message:   rule.RenderMessage(m),
+		CreatedAt: time.Now(),
+	}
+	switch rule.Recipient {
+	case domain.NotifyAdmins:
+		_, err := s.notificationRepo.CreateForRole(ctx, domain.RoleAdmin, n)
+		return err
+	case domain.NotifyBuilder:
+		if m.BuilderID == nil {
+			return errors.New("no builder is assigned")
+		}
+		n.UserID = *m.BuilderID
+	default:
+		n.UserID = m.UserID
+	}
+	return s.notificationRepo.Create(ctx, n)
+}
+
+// applyAutomationRule copies the input onto the rule and validates it
+func applyAutomationRule(rule *domain.AutomationRule, req *domain.SaveAutomationRuleRequest) error {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Description = strings.TrimSpace(req.Description)
+	rule.Enabled = req.Enabled != nil && *req.Enabled
+	rule.Status = req.Status
+	rule.Days = req.Days
+	rule.Conditions = req.Conditions
+	rule.Conditions.Label = domain.NormalizeLabel(rule.Conditions.Label)
+	rule.Action = req.Action
+	rule.TargetStatus = nil
+	rule.Label = ""
+	rule.Recipient = ""
+	rule.Message = ""
+	switch req.Action {
+	case domain.AutomationTransition:
+		rule.TargetStatus = req.TargetStatus
+	case domain.AutomationAddLabel:
+		rule.Label = domain.NormalizeLabel(req.Label)
+	case domain.AutomationNotify:
+		rule.Recipient = req.Recipient
+		rule.Message = strings.TrimSpace(req.Message)
+	}
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidAutomationRule)
+	}
+	return rule.Validate()
+}
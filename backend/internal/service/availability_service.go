@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type availabilityService struct {
+	availabilityRepo domain.AvailabilityRepository
+	scheduleService  domain.ScheduleService
+}
+
+// NewAvailabilityService creates a new builder availability service
+func NewAvailabilityService(availabilityRepo domain.AvailabilityRepository, scheduleService domain.ScheduleService) domain.AvailabilityService {
+	return &availabilityService{
+		availabilityRepo: availabilityRepo,
+		scheduleService:  scheduleService,
+	}
+}
+
+func (s *availabilityService) Calendar(ctx context.Context, builderID uuid.UUID, from, to time.Time) (*domain.AvailabilityCalendar, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", domain.ErrInvalidAvailability)
+	}
+	patterns, err := s.availabilityRepo.ListPatterns(ctx, builderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list patterns: %w", err)
+	}
+	days, err := s.availabilityRepo.ListDays(ctx, &builderID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list days: %w", err)
+	}
+	return domain.NewAvailabilityCalendar(builderID, from, to, patterns, days), nil
+}
+
+func (s *availabilityService) CreatePattern(ctx context.Context, builderID uuid.UUID, req *domain.CreateAvailabilityPatternRequest) (*domain.AvailabilityPattern, error) {
+	p, err := req.Pattern(builderID, today())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.availabilityRepo.CreatePattern(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to create pattern: %w", err)
+	}
+	// A pattern can reach every upcoming slot
+	if err := s.scheduleService.RefreshCapacity(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *availabilityService) DeletePattern(ctx context.Context, builderID, id uuid.UUID) error {
+	p, err := s.availabilityRepo.FindPatternByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to find pattern: %w", err)
+	}
+	if p == nil || p.BuilderID != builderID {
+		return domain.ErrAvailabilityNotFound
+	}
+	if err := s.availabilityRepo.DeletePattern(ctx, id); err != nil {
+		return err
+	}
+	return s.scheduleService.RefreshCapacity(ctx)
+}
+
+func (s *availabilityService) SetDay(ctx context.Context, builderID uuid.UUID, date time.Time, req *domain.SetAvailabilityDayRequest) (*domain.AvailabilityDay, error) {
+	if date.Format("2006-01-02") < today().Format("2006-01-02") {
+		return nil, fmt.Errorf("%w: the date has passed", domain.ErrInvalidAvailability)
+	}
+	d, err := req.Day(builderID, date)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.availabilityRepo.UpsertDay(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to save day: %w", err)
+	}
+	if err := s.scheduleService.RefreshCapacity(ctx, date); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *availabilityService) ClearDay(ctx context.Context, builderID uuid.UUID, date time.Time) error {
+	if err := s.availabilityRepo.DeleteDay(ctx, builderID, date); err != nil {
+		return err
+	}
+	return s.scheduleService.RefreshCapacity(ctx, date)
+}
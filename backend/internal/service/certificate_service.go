@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// Renewal tuning: how many certificates one pass issues, how long a claimed
+// certificate is left alone, and the longest wait between failed attempts
+const (
+	certificateBatch    = 20
+	certificateLease    = 15 * time.Minute
+	certificateMaxRetry = 24 * time.Hour
+)
+
+type certificateService struct {
+	certRepo         domain.CertificateRepository
+	verificationRepo domain.DomainVerificationRepository
+	issuer           domain.CertificateIssuer // nil when ACME is not configured
+}
+
+// NewCertificateService creates a new certificate service. A nil issuer
+// disables provisioning; existing certificates can still be read.
+func NewCertificateService(certRepo domain.CertificateRepository, verificationRepo domain.DomainVerificationRepository, issuer domain.CertificateIssuer) domain.CertificateService {
+	return &certificateService{
+		certRepo:         certRepo,
+		verificationRepo: verificationRepo,
+		issuer:           issuer,
+	}
+}
+
+func (s *certificateService) Provision(ctx context.Context, requestID uuid.UUID) (*domain.Certificate, error) {
+	if s.issuer == nil {
+		return nil, domain.ErrCertificatesDisabled
+	}
+	v, err := s.verificationRepo.Find(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain verification: %w", err)
+	}
+	if v == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if v.HostingType != domain.HostingWhitelabel || v.Domain == "" {
+		return nil, domain.ErrNoCustomDomain
+	}
+	if v.Status != domain.DomainVerified {
+		return nil, domain.ErrDomainNotVerified
+	}
+
+	cert := &domain.Certificate{
+		ID:         uuid.New(),
+		RequestID:  requestID,
+		Domain:     v.Domain,
+		Status:     domain.CertificatePending,
+		RenewAfter: time.Now(),
+	}
+	if err := s.certRepo.Upsert(ctx, cert); err != nil {
+		return nil, fmt.Errorf("failed to save certificate: %w", err)
+	}
+
+	// Issuance waits on the CA validating the challenge, so it runs in the
+	// background; the periodic job picks it up if this instance stops
+	go func() {
+		if _, err := s.RenewDue(context.Background()); err != nil {
+			log.Error().Err(err).Str("request_id", requestID.String()).Msg("Failed to issue certificates")
+		}
+	}()
+	return cert, nil
+}
+
+func (s *certificateService) Get(ctx context.Context, requestID uuid.UUID) (*domain.Certificate, error) {
+	cert, err := s.certRepo.FindByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificate: %w", err)
+	}
+	if cert == nil {
+		return nil, domain.ErrCertificateNotFound
+	}
+	return cert, nil
+}
+
+func (s *certificateService) ChallengeResponse(ctx context.Context, token string) (string, error) {
+	keyAuth, err := s.certRepo.Challenge(ctx, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to find challenge: %w", err)
+	}
+	if keyAuth == "" {
+		return "", domain.ErrChallengeNotFound
+	}
+	return keyAuth, nil
+}
+
+// RenewDue issues every claimed certificate in turn. Failures are recorded on
+// the certificate and retried with backoff rather than returned.
+func (s *certificateService) RenewDue(ctx context.Context) (int, error) {
+	if s.issuer == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	certs, err := s.certRepo.ClaimDue(ctx, now, now.Add(certificateLease), certificateBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim due certificates: %w", err)
+	}
+
+	issued := 0
+	for i := range certs {
+		cert := &certs[i]
+		if err := s.issue(ctx, cert); err != nil {
+			cert.Attempts++
+			cert.LastError = err.Error()
+			cert.RenewAfter = time.Now().Add(certificateBackoff(cert.Attempts))
+			if cert.Status != domain.CertificateIssued {
+				// A failed renewal keeps serving the previous certificate
+				cert.Status = domain.CertificateFailed
+			}
+			log.Warn().Err(err).Str("request_id", cert.RequestID.String()).Str("domain", cert.Domain).
+				Msg("Certificate issuance failed")
+		} else {
+			issued++
+		}
+		if err := s.certRepo.Update(ctx, cert); err != nil {
+			log.Error().Err(err).Str("request_id", cert.RequestID.String()).Msg("Failed to save certificate")
+		}
+	}
+	return issued, nil
+}
+
+// issue obtains a certificate for the domain, provided it is still the
+// request's verified domain
+func (s *certificateService) issue(ctx context.Context, cert *domain.Certificate) error {
+	v, err := s.verificationRepo.Find(ctx, cert.RequestID)
+	if err != nil {
+		return fmt.Errorf("failed to find domain verification: %w", err)
+	}
+	if v == nil || v.Domain != cert.Domain || v.Status != domain.DomainVerified {
+		return fmt.Errorf("%s is no longer the request's verified domain", cert.Domain)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	result, err := s.issuer.Issue(ctx, cert.Domain)
+	if err != nil {
+		return err
+	}
+
+	cert.Status = domain.CertificateIssued
+	cert.CertPEM = result.CertPEM
+	cert.KeyPEM = result.KeyPEM
+	cert.Issuer = result.Issuer
+	cert.NotBefore = &result.NotBefore
+	cert.NotAfter = &result.NotAfter
+	cert.RenewAfter = result.RenewAt()
+	cert.Attempts = 0
+	cert.LastError = ""
+	return nil
+}
+
+// certificateBackoff doubles the wait after each failure: 1h, 2h, 4h, ... up to a day
+func certificateBackoff(attempts int) time.Duration {
+	if attempts > 5 {
+		return certificateMaxRetry
+	}
+	return min(time.Hour<<(attempts-1), certificateMaxRetry)
+}
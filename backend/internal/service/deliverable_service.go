@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type deliverableService struct {
+	transactor      domain.Transactor
+	deliverableRepo domain.DeliverableRepository
+	requestRepo     domain.BuildRequestRepository
+	lifecycle       *domain.Lifecycle
+}
+
+// NewDeliverableService creates a new deliverable service. Request status
+// changes derived from deliverables go through the lifecycle, so payment and
+// quote guards still apply and observers (invoicing) still fire. Deliverables
+// and the request status derived from them are written in one transaction.
+func NewDeliverableService(transactor domain.Transactor, deliverableRepo domain.DeliverableRepository, requestRepo domain.BuildRequestRepository, lifecycle *domain.Lifecycle) domain.DeliverableService {
+	return &deliverableService{
+		transactor:      transactor,
+		deliverableRepo: deliverableRepo,
+		requestRepo:     requestRepo,
+		lifecycle:       lifecycle,
+	}
+}
+
+// CheckTransition refuses to set a build status by hand when the request's
+// deliverables say otherwise
+func (s *deliverableService) CheckTransition(ctx context.Context, req *domain.BuildRequest, to domain.RequestStatus) error {
+	if !domain.IsDerivedStatus(to) {
+		return nil
+	}
+	deliverables, err := s.deliverableRepo.ListByRequest(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list deliverables: %w", err)
+	}
+	if !hasActiveDeliverables(deliverables) {
+		return nil
+	}
+	if derived, ok := domain.DeriveRequestStatus(req.Status, deliverables); !ok || derived != to {
+		return domain.ErrStatusDerived
+	}
+	return nil
+}
+
+func (s *deliverableService) Create(ctx context.Context, requestID uuid.UUID, in *domain.CreateDeliverableRequest) ([]domain.Deliverable, error) {
+	created := []domain.Deliverable{}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		req, err := s.lockRequest(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		existing, err := s.deliverableRepo.ListByRequest(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to list deliverables: %w", err)
+		}
+
+		kinds := []domain.DeliverableKind{in.Kind}
+		if in.Kind == "" {
+			// Add whichever of the usual deliverables are missing
+			kinds = nil
+			for _, kind := range domain.DefaultDeliverableKinds(req.RequestType) {
+				if !hasDeliverableKind(existing, kind) {
+					kinds = append(kinds, kind)
+				}
+			}
+		}
+
+		now := time.Now()
+		for _, kind := range kinds {
+			label := strings.TrimSpace(in.Label)
+			if label == "" || len(kinds) > 1 {
+				label = domain.DefaultDeliverableLabel(kind)
+			}
+			d := domain.Deliverable{
+				ID:        uuid.New(),
+				RequestID: requestID,
+				Kind:      kind,
+				Label:     label,
+				Status:    domain.DeliverablePlanned,
+				Artifacts: []domain.BuildArtifact{},
+				Checklist: domain.DefaultHandoverChecklist(kind),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			created = append(created, d)
+		}
+
+		// A new deliverable can reopen a request whose other parts were finished
+		changed, err := s.checkDerived(ctx, req, append(existing, created...))
+		if err != nil {
+			return err
+		}
+		for i := range created {
+			if err := s.deliverableRepo.Create(ctx, &created[i]); err != nil {
+				return fmt.Errorf("failed to create deliverable: %w", err)
+			}
+		}
+		return s.syncRequest(ctx, req, changed, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *deliverableService) ListForRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Deliverable, error) {
+	deliverables, err := s.deliverableRepo.ListByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliverables: %w", err)
+	}
+	if deliverables == nil {
+		deliverables = []domain.Deliverable{}
+	}
+	return deliverables, nil
+}
+
+func (s *deliverableService) Update(ctx context.Context, actorID, requestID, deliverableID uuid.UUID, in *domain.UpdateDeliverableRequest) (*domain.Deliverable, error) {
+	var d *domain.Deliverable
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		req, err := s.lockRequest(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		all, err := s.deliverableRepo.ListByRequest(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to list deliverables: %w", err)
+		}
+		for i := range all {
+			if all[i].ID == deliverableID {
+				d = &all[i]
+			}
+		}
+		if d == nil {
+			return domain.ErrDeliverableNotFound
+		}
+
+		now := time.Now()
+		if in.Label != nil && strings.TrimSpace(*in.Label) != "" {
+			d.Label = strings.TrimSpace(*in.Label)
+		}
+		if in.DeliveryURL != nil {
+			d.DeliveryURL = *in.DeliveryURL
+		}
+		if in.RepoURL != nil {
+			d.RepoURL = *in.RepoURL
+		}
+		if in.StoreURL != nil {
+			d.StoreURL = *in.StoreURL
+		}
+		for _, u := range in.Checklist {
+			applyChecklistUpdate(d, u, actorID, now)
+		}
+
+		if in.Status != nil && *in.Status != d.Status {
+			to := *in.Status
+			if to != domain.DeliverablePlanned && to != domain.DeliverableDropped && !domain.DeliverablesStarted(req.Status) {
+				return domain.ErrDeliverablesNotStarted
+			}
+			d.Status = to
+			d.DeliveredAt = nil
+			if to == domain.DeliverableDelivered {
+				d.DeliveredAt = &now
+			}
+		}
+		if d.Status == domain.DeliverableDelivered && !d.ChecklistComplete() {
+			return domain.ErrChecklistIncomplete
+		}
+		d.UpdatedAt = now
+
+		// The request's lock keeps the other deliverables as read here, so
+		// the status derived from them is the one being saved
+		changed, err := s.checkDerived(ctx, req, all)
+		if err != nil {
+			return err
+		}
+		if err := s.deliverableRepo.Update(ctx, d); err != nil {
+			return fmt.Errorf("failed to update deliverable: %w", err)
+		}
+		return s.syncRequest(ctx, req, changed, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *deliverableService) AddArtifact(ctx context.Context, requestID, deliverableID uuid.UUID, in *domain.AddBuildArtifactRequest) (*domain.Deliverable, error) {
+	var d *domain.Deliverable
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Under the request's lock, so a concurrent update cannot drop the artifact
+		if _, err := s.lockRequest(ctx, requestID); err != nil {
+			return err
+		}
+		var err error
+		d, err = s.loadDeliverable(ctx, requestID, deliverableID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		d.Artifacts = append(d.Artifacts, domain.BuildArtifact{
+			Name:    in.Name,
+			URL:     in.URL,
+			Version: in.Version,
+			SHA256:  strings.ToLower(in.SHA256),
+			AddedAt: now,
+		})
+		d.UpdatedAt = now
+
+		if err := s.deliverableRepo.Update(ctx, d); err != nil {
+			return fmt.Errorf("failed to update deliverable: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *deliverableService) Reopen(ctx context.Context, req *domain.BuildRequest) (bool, error) {
+	handled := false
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		locked, err := s.lockRequest(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Version != req.Version {
+			return domain.ErrVersionConflict
+		}
+		deliverables, err := s.deliverableRepo.ListByRequest(ctx, req.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list deliverables: %w", err)
+		}
+		if !hasActiveDeliverables(deliverables) {
+			return nil
+		}
+
+		now := time.Now()
+		var reopened []*domain.Deliverable
+		for i := range deliverables {
+			d := &deliverables[i]
+			if d.Status != domain.DeliverableDelivered {
+				continue
+			}
+			d.Status = domain.DeliverableBuilding
+			d.DeliveredAt = nil
+			d.UpdatedAt = now
+			reopened = append(reopened, d)
+		}
+
+		changed, err := s.checkDerived(ctx, req, deliverables)
+		if err != nil {
+			return err
+		}
+		for _, d := range reopened {
+			if err := s.deliverableRepo.Update(ctx, d); err != nil {
+				return fmt.Errorf("failed to update deliverable: %w", err)
+			}
+		}
+		handled = true
+		return s.syncRequest(ctx, req, changed, nil)
+	})
+	return handled, err
+}
+
+// lockRequest reads the request with a row lock. Every deliverable change
+// takes it first, so each one derives the request's status from what the
+// others saved.
+func (s *deliverableService) lockRequest(ctx context.Context, requestID uuid.UUID) (*domain.BuildRequest, error) {
+	req, err := s.requestRepo.LockByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	return req, nil
+}
+
+func (s *deliverableService) loadDeliverable(ctx context.Context, requestID, deliverableID uuid.UUID) (*domain.Deliverable, error) {
+	d, err := s.deliverableRepo.FindByID(ctx, deliverableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deliverable: %w", err)
+	}
+	if d == nil || d.RequestID != requestID {
+		return nil, domain.ErrDeliverableNotFound
+	}
+	return d, nil
+}
+
+// checkDerived returns the request status the deliverables imply, if it
+// differs from the current one, after the lifecycle guards have allowed it
+func (s *deliverableService) checkDerived(ctx context.Context, req *domain.BuildRequest, deliverables []domain.Deliverable) (*domain.RequestStatus, error) {
+	derived, ok := domain.DeriveRequestStatus(req.Status, deliverables)
+	if !ok || derived == req.Status {
+		return nil, nil
+	}
+	// Our own guard compares with the saved deliverables, which these replace
+	if err := s.lifecycle.CheckExcept(ctx, req, derived, s); err != nil {
+		return nil, err
+	}
+	return &derived, nil
+}
+
+// syncRequest moves the request to its derived status and mirrors the
+// website's URLs onto it, for clients that only read the request. Call it in
+// the transaction that saves the deliverables; observers hear of the change
+// once it commits.
+func (s *deliverableService) syncRequest(ctx context.Context, req *domain.BuildRequest, status *domain.RequestStatus, d *domain.Deliverable) error {
+	previousStatus := req.Status
+	dirty := false
+	if status != nil {
+		req.Status = *status
+		dirty = true
+	}
+	if d != nil && d.Kind == domain.DeliverableWeb {
+		if d.DeliveryURL != "" && req.DeliveryURL == "" {
+			req.DeliveryURL = d.DeliveryURL
+			dirty = true
+		}
+		if d.RepoURL != "" && req.RepoURL == "" {
+			req.RepoURL = d.RepoURL
+			dirty = true
+		}
+	}
+	if !dirty {
+		return nil
+	}
+
+	req.UpdatedAt = time.Now()
+	if err := s.requestRepo.Update(ctx, req); err != nil {
+		return fmt.Errorf("failed to update request: %w", err)
+	}
+	if req.Status != previousStatus {
+		s.transactor.AfterCommit(ctx, func(ctx context.Context) {
+			s.lifecycle.Notify(ctx, req, previousStatus)
+		})
+	}
+	return nil
+}
+
+// applyChecklistUpdate ticks a handover step, adding it if it is new
+func applyChecklistUpdate(d *domain.Deliverable, u domain.ChecklistUpdate, actorID uuid.UUID, now time.Time) {
+	item := strings.TrimSpace(u.Item)
+	i := 0
+	for ; i < len(d.Checklist); i++ {
+		if strings.EqualFold(d.Checklist[i].Item, item) {
+			break
+		}
+	}
+	if i == len(d.Checklist) {
+		d.Checklist = append(d.Checklist, domain.ChecklistItem{Item: item})
+	}
+
+	entry := &d.Checklist[i]
+	if entry.Done == u.Done {
+		return
+	}
+	entry.Done = u.Done
+	entry.DoneAt, entry.DoneBy = nil, nil
+	if u.Done {
+		entry.DoneAt = &now
+		entry.DoneBy = &actorID
+	}
+}
+
+func hasActiveDeliverables(deliverables []domain.Deliverable) bool {
+	for _, d := range deliverables {
+		if d.Status != domain.DeliverableDropped {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDeliverableKind(deliverables []domain.Deliverable, kind domain.DeliverableKind) bool {
+	for _, d := range deliverables {
+		if d.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
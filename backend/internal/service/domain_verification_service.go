@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type domainVerificationService struct {
+	verificationRepo domain.DomainVerificationRepository
+	resolver         domain.TXTResolver
+}
+
+// NewDomainVerificationService creates a new domain verification service
+func NewDomainVerificationService(verificationRepo domain.DomainVerificationRepository, resolver domain.TXTResolver) domain.DomainVerificationService {
+	return &domainVerificationService{
+		verificationRepo: verificationRepo,
+		resolver:         resolver,
+	}
+}
+
+// CheckTransition refuses to schedule or start a whitelabel build until the
+// student has proven they control the domain
+func (s *domainVerificationService) CheckTransition(ctx context.Context, req *domain.BuildRequest, to domain.RequestStatus) error {
+	if req.HostingType != domain.HostingWhitelabel || (to != domain.StatusScheduled && to != domain.StatusBuilding) {
+		return nil
+	}
+	v, err := s.verificationRepo.Find(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check domain verification: %w", err)
+	}
+	if v == nil || v.Status != domain.DomainVerified {
+		return domain.ErrDomainNotVerified
+	}
+	return nil
+}
+
+func (s *domainVerificationService) Get(ctx context.Context, requestID uuid.UUID) (*domain.DomainVerification, error) {
+	v, err := s.verificationRepo.Find(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find domain verification: %w", err)
+	}
+	if v == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if v.HostingType != domain.HostingWhitelabel || v.Domain == "" {
+		return nil, domain.ErrNoCustomDomain
+	}
+
+	if v.Token == "" {
+		token, err := newDomainToken()
+		if err != nil {
+			return nil, err
+		}
+		v.Token = token
+		v.Status = domain.DomainPending
+		if err := s.verificationRepo.Save(ctx, v); err != nil {
+			return nil, fmt.Errorf("failed to save domain verification: %w", err)
+		}
+	}
+	v.SetRecord()
+	return v, nil
+}
+
+// Verify checks DNS for the token. A verified domain stays verified, so the
+// student can remove the record afterwards.
+func (s *domainVerificationService) Verify(ctx context.Context, requestID uuid.UUID) (*domain.DomainVerification, error) {
+	v, err := s.Get(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if v.Status == domain.DomainVerified {
+		return v, nil
+	}
+
+	now := time.Now()
+	v.CheckedAt = &now
+	records, err := s.resolver.LookupTXT(ctx, v.RecordName)
+	switch {
+	case err != nil:
+		v.Status = domain.DomainFailed
+		v.LastError = fmt.Sprintf("DNS lookup of %s failed: %v", v.RecordName, err)
+	case containsTXT(records, v.RecordValue):
+		v.Status = domain.DomainVerified
+		v.LastError = ""
+		v.VerifiedAt = &now
+	case len(records) == 0:
+		v.Status = domain.DomainFailed
+		v.LastError = fmt.Sprintf("no TXT record found at %s; DNS changes can take a while to propagate", v.RecordName)
+	default:
+		v.Status = domain.DomainFailed
+		v.LastError = fmt.Sprintf("the TXT records at %s do not contain %s", v.RecordName, v.RecordValue)
+	}
+
+	if err := s.verificationRepo.Save(ctx, v); err != nil {
+		return nil, fmt.Errorf("failed to save domain verification: %w", err)
+	}
+	return v, nil
+}
+
+// containsTXT reports whether one of the records is the expected value.
+// Some DNS panels wrap values in quotes, so those are ignored.
+func containsTXT(records []string, want string) bool {
+	for _, r := range records {
+		if strings.Trim(strings.TrimSpace(r), `"`) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func newDomainToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate domain token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type exportService struct {
+	exportRepo domain.ExportRepository
+	newWriter  domain.TableWriterFactory
+}
+
+// NewExportService creates a new export service. Rows are streamed from the
+// repository straight into a writer created by newWriter.
+func NewExportService(exportRepo domain.ExportRepository, newWriter domain.TableWriterFactory) domain.ExportService {
+	return &exportService{exportRepo: exportRepo, newWriter: newWriter}
+}
+
+func (s *exportService) Prepare(q *domain.ExportQuery) ([]domain.ExportColumn, error) {
+	if q.Format != domain.ExportCSV && q.Format != domain.ExportXLSX {
+		return nil, fmt.Errorf("%w: format must be csv or xlsx", domain.ErrInvalidExport)
+	}
+	if q.Dataset == domain.ExportRequests && q.Requests.After != nil {
+		return nil, fmt.Errorf("%w: exports are not paginated", domain.ErrInvalidExport)
+	}
+	return domain.SelectExportColumns(q.Dataset, q.Columns)
+}
+
+func (s *exportService) Export(ctx context.Context, q *domain.ExportQuery, w io.Writer) error {
+	columns, err := s.Prepare(q)
+	if err != nil {
+		return err
+	}
+	writer, err := s.newWriter(q.Format, w)
+	if err != nil {
+		return err
+	}
+	// Nothing is written until the query has produced its first row, so a
+	// failing query can still be reported as an error response
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		return writer.WriteHeader(columns)
+	}
+	err = s.exportRepo.Stream(ctx, *q, columns, func(values []interface{}) error {
+		if err := start(); err != nil {
+			return err
+		}
+		return writer.WriteRow(values)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", q.Dataset, err)
+	}
+	if err := start(); err != nil {
+		return err
+	}
+	return writer.Close()
+}
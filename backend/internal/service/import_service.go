@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// importSampleRows is how many rows an upload echoes back for mapping
+const importSampleRows = 5
+
+// maxImportErrors caps the per-row errors kept in a report
+const maxImportErrors = 1000
+
+// importDateLayouts are the date formats accepted in the legacy sheet.
+// Slashed dates are day first, as the sheet was kept in India.
+var importDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006",
+	"2/1/2006",
+	"02-Jan-2006",
+	"2 Jan 2006",
+}
+
+type importService struct {
+	importRepo domain.ImportRepository
+}
+
+// NewImportService creates a new legacy CSV import service. Imported
+// requests are history: they bypass pricing, quotas and lifecycle guards.
+func NewImportService(importRepo domain.ImportRepository) domain.ImportService {
+	return &importService{importRepo: importRepo}
+}
+
+func (s *importService) Upload(ctx context.Context, createdBy uuid.UUID, filename string, content []byte) (*domain.ImportBatch, error) {
+	if len(content) > domain.MaxImportBytes {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", domain.ErrInvalidImport, domain.MaxImportBytes>>20)
+	}
+	headers, rows, err := parseImportCSV(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := &domain.ImportBatch{
+		ID:        uuid.New(),
+		Filename:  filename,
+		Headers:   headers,
+		Mapping:   domain.SuggestImportMapping(headers),
+		Status:    domain.ImportUploaded,
+		RowCount:  len(rows),
+		Content:   string(content),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.importRepo.CreateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save import: %w", err)
+	}
+	batch.Sample = sampleRows(rows)
+	return batch, nil
+}
+
+func (s *importService) Get(ctx context.Context, id uuid.UUID) (*domain.ImportBatch, error) {
+	batch, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, rows, err := parseImportCSV(batch.Content); err == nil {
+		batch.Sample = sampleRows(rows)
+	}
+	return batch, nil
+}
+
+func (s *importService) SetMapping(ctx context.Context, id uuid.UUID, mapping domain.ImportMapping) (*domain.ImportBatch, error) {
+	batch, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == domain.ImportCommitted {
+		return nil, domain.ErrImportCommitted
+	}
+	for field, header := range mapping {
+		if !field.IsValid() {
+			return nil, fmt.Errorf("%w: unknown field %q", domain.ErrInvalidImport, field)
+		}
+		if header == "" {
+			delete(mapping, field)
+			continue
+		}
+		if !containsString(batch.Headers, header) {
+			return nil, fmt.Errorf("%w: the file has no column %q", domain.ErrInvalidImport, header)
+		}
+	}
+
+	// A new mapping needs a new dry run
+	batch.Mapping = mapping
+	batch.Status = domain.ImportUploaded
+	batch.Report = nil
+	if err := s.importRepo.UpdateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save import: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *importService) DryRun(ctx context.Context, id uuid.UUID) (*domain.ImportBatch, error) {
+	batch, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status == domain.ImportCommitted {
+		return nil, domain.ErrImportCommitted
+	}
+	if _, err := s.validate(ctx, batch); err != nil {
+		return nil, err
+	}
+	if err := s.importRepo.UpdateBatch(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to save import: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *importService) Commit(ctx context.Context, id uuid.UUID) (*domain.ImportBatch, error) {
+	batch, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch batch.Status {
+	case domain.ImportCommitted:
+		return nil, domain.ErrImportCommitted
+	case domain.ImportValidated:
+	default:
+		return nil, domain.ErrImportNotValidated
+	}
+
+	// Validate again: other imports may have claimed keys since the dry run
+	records, err := s.validate(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != domain.ImportValidated {
+		if err := s.importRepo.UpdateBatch(ctx, batch); err != nil {
+			return nil, fmt.Errorf("failed to save import: %w", err)
+		}
+		return nil, domain.ErrImportNotValidated
+	}
+
+	if _, _, err := s.importRepo.Commit(ctx, batch, records); err != nil {
+		if errors.Is(err, domain.ErrImportCommitted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *importService) load(ctx context.Context, id uuid.UUID) (*domain.ImportBatch, error) {
+	batch, err := s.importRepo.FindBatch(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find import: %w", err)
+	}
+	if batch == nil {
+		return nil, domain.ErrImportNotFound
+	}
+	return batch, nil
+}
+
+// validate checks every row against the batch's mapping, records the report
+// and status on the batch, and returns the records still to be created
+func (s *importService) validate(ctx context.Context, batch *domain.ImportBatch) ([]domain.ImportRecord, error) {
+	var missing []string
+	for _, field := range domain.RequiredImportFields {
+		if batch.Mapping[field] == "" {
+			missing = append(missing, string(field))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: map the required fields %s", domain.ErrInvalidImport, strings.Join(missing, ", "))
+	}
+
+	headers, rows, err := parseImportCSV(batch.Content)
+	if err != nil {
+		return nil, err
+	}
+	index := map[domain.ImportField]int{}
+	for field, header := range batch.Mapping {
+		for i, h := range headers {
+			if h == header {
+				index[field] = i
+			}
+		}
+	}
+
+	report := &domain.ImportReport{Rows: len(rows), Errors: []domain.ImportRowError{}}
+	addError := func(row int, field domain.ImportField, format string, args ...interface{}) {
+		if len(report.Errors) < maxImportErrors {
+			report.Errors = append(report.Errors, domain.ImportRowError{Row: row, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	var records []domain.ImportRecord
+	seenKeys := map[string]int{}
+	for i, row := range rows {
+		line := i + 2
+		get := func(field domain.ImportField) string {
+			col, ok := index[field]
+			if !ok || col >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[col])
+		}
+
+		rec, problems := parseImportRow(get, batch.CreatedAt)
+		for _, p := range problems {
+			addError(line, p.Field, "%s", p.Message)
+		}
+		bad := len(problems) > 0
+		if rec.ExternalKey != "" {
+			if first, dup := seenKeys[rec.ExternalKey]; dup {
+				addError(line, domain.ImportExternalKey, "external key %q repeats row %d", rec.ExternalKey, first)
+				bad = true
+			} else {
+				seenKeys[rec.ExternalKey] = line
+			}
+		}
+		if bad {
+			continue
+		}
+		rec.Row = line
+		records = append(records, rec)
+	}
+	report.Valid = len(records)
+
+	// Rows already imported are skipped, which makes re-running a no-op
+	keys := make([]string, len(records))
+	for i := range records {
+		keys[i] = records[i].ExternalKey
+	}
+	existing, err := s.importRepo.ExistingExternalKeys(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check external keys: %w", err)
+	}
+	toCreate := records[:0]
+	emails := []string{}
+	for _, rec := range records {
+		if existing[rec.ExternalKey] {
+			report.Skip++
+			continue
+		}
+		toCreate = append(toCreate, rec)
+		if !containsString(emails, rec.StudentEmail) {
+			emails = append(emails, rec.StudentEmail)
+		}
+	}
+	report.Create = len(toCreate)
+
+	known, err := s.importRepo.ExistingEmails(ctx, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match students: %w", err)
+	}
+	report.NewStudents = len(emails) - len(known)
+
+	batch.Report = report
+	batch.Status = domain.ImportValidated
+	if report.Valid < report.Rows {
+		batch.Status = domain.ImportInvalid
+	}
+	return toCreate, nil
+}
+
+// parseImportRow turns one row into a record, returning every problem found
+func parseImportRow(get func(domain.ImportField) string, importedAt time.Time) (domain.ImportRecord, []domain.ImportRowError) {
+	var problems []domain.ImportRowError
+	fail := func(field domain.ImportField, format string, args ...interface{}) {
+		problems = append(problems, domain.ImportRowError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	rec := domain.ImportRecord{
+		ExternalKey: get(domain.ImportExternalKey),
+		StudentName: get(domain.ImportStudentName),
+		StudentID:   get(domain.ImportStudentID),
+	}
+	req := &rec.Request
+	req.ID = uuid.New()
+	req.Title = get(domain.ImportTitle)
+	req.Description = get(domain.ImportDescription)
+	req.DeliveryURL = get(domain.ImportDeliveryURL)
+	req.RepoURL = get(domain.ImportRepoURL)
+	req.Currency = domain.DefaultCurrency
+
+	switch {
+	case rec.ExternalKey == "":
+		fail(domain.ImportExternalKey, "external key is required")
+	case len(rec.ExternalKey) > 200:
+		fail(domain.ImportExternalKey, "external key is longer than 200 characters")
+	}
+	switch {
+	case req.Title == "":
+		fail(domain.ImportTitle, "title is required")
+	case len(req.Title) > 500:
+		fail(domain.ImportTitle, "title is longer than 500 characters")
+	}
+
+	if addr, err := mail.ParseAddress(get(domain.ImportStudentEmail)); err != nil {
+		fail(domain.ImportStudentEmail, "student email %q is not valid", get(domain.ImportStudentEmail))
+	} else {
+		rec.StudentEmail = strings.ToLower(addr.Address)
+	}
+
+	req.RequestType = domain.RequestType(enumValue(get(domain.ImportRequestType)))
+	switch req.RequestType {
+	case domain.RequestTypeWebsite, domain.RequestTypeMobileApp, domain.RequestTypeBoth:
+	default:
+		fail(domain.ImportRequestType, "request type %q must be website, mobile_app or both", get(domain.ImportRequestType))
+	}
+
+	req.Status = domain.RequestStatus(enumValue(get(domain.ImportStatus)))
+	switch req.Status {
+	case "":
+		req.Status = domain.StatusCompleted
+	case domain.StatusPending, domain.StatusQueued, domain.StatusScheduled, domain.StatusBuilding,
+		domain.StatusReview, domain.StatusDeploying, domain.StatusCompleted, domain.StatusCancelled, domain.StatusRejected:
+	default:
+		fail(domain.ImportStatus, "unknown status %q", get(domain.ImportStatus))
+	}
+
+	req.Complexity = domain.ComplexityLevel(enumValue(get(domain.ImportComplexity)))
+	switch req.Complexity {
+	case "":
+		req.Complexity = domain.ComplexityBasic
+	case domain.ComplexityBasic, domain.ComplexityStandard, domain.ComplexityAdvanced:
+	default:
+		fail(domain.ImportComplexity, "complexity %q must be basic, standard or advanced", get(domain.ImportComplexity))
+	}
+
+	req.HostingType = domain.HostingType(enumValue(get(domain.ImportHostingType)))
+	switch req.HostingType {
+	case "":
+		req.HostingType = domain.HostingFreeVercel
+	case domain.HostingFreeVercel, domain.HostingFreeReplit, domain.HostingFreeHeroku, domain.HostingWhitelabel:
+	default:
+		fail(domain.ImportHostingType, "unknown hosting type %q", get(domain.ImportHostingType))
+	}
+
+	req.EstimatedCost = domain.NewMoney(0, domain.DefaultCurrency)
+	if cost := get(domain.ImportCost); cost != "" {
+		m, err := domain.ParseMoney(stripCurrency(cost), domain.DefaultCurrency)
+		switch {
+		case err != nil:
+			fail(domain.ImportCost, "estimated cost %q is not an amount", cost)
+		case m.IsNegative():
+			fail(domain.ImportCost, "estimated cost cannot be negative")
+		default:
+			req.EstimatedCost = m
+		}
+	}
+	req.IsFree = req.EstimatedCost.IsZero()
+
+	created, createdOK := parseImportDate(get(domain.ImportCreatedAt))
+	if !createdOK {
+		fail(domain.ImportCreatedAt, "created date %q is not a recognised date", get(domain.ImportCreatedAt))
+	}
+	completed, completedOK := parseImportDate(get(domain.ImportCompletedAt))
+	if !completedOK {
+		fail(domain.ImportCompletedAt, "completed date %q is not a recognised date", get(domain.ImportCompletedAt))
+	}
+	switch {
+	case created != nil:
+		req.CreatedAt = *created
+	case completed != nil:
+		req.CreatedAt = *completed
+	default:
+		req.CreatedAt = importedAt
+	}
+	if completed == nil && req.Status == domain.StatusCompleted {
+		completed = &req.CreatedAt
+	}
+	if completed != nil && completed.Before(req.CreatedAt) {
+		fail(domain.ImportCompletedAt, "completed date is before the created date")
+	}
+	req.CompletedAt = completed
+	req.UpdatedAt = req.CreatedAt
+
+	req.Labels = []string{domain.ImportedLabel}
+	for _, label := range strings.Split(get(domain.ImportLabels), ",") {
+		if label = domain.NormalizeLabel(label); label != "" && !containsString(req.Labels, label) {
+			req.Labels = append(req.Labels, label)
+		}
+	}
+	return rec, problems
+}
+
+// parseImportCSV reads the header and data rows of an uploaded CSV. Blank
+// lines are dropped and ragged rows allowed, as exported sheets often have both.
+func parseImportCSV(content string) ([]string, [][]string, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	headers, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("%w: the file is empty", domain.ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+	}
+	seen := map[string]bool{}
+	for i, h := range headers {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			return nil, nil, fmt.Errorf("%w: column %d has no header", domain.ErrInvalidImport, i+1)
+		}
+		if seen[h] {
+			return nil, nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidImport, h)
+		}
+		seen[h] = true
+		headers[i] = h
+	}
+
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		if len(rows) == domain.MaxImportRows {
+			return nil, nil, fmt.Errorf("%w: more than %d rows", domain.ErrInvalidImport, domain.MaxImportRows)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("%w: the file has no data rows", domain.ErrInvalidImport)
+	}
+	return headers, rows, nil
+}
+
+func sampleRows(rows [][]string) [][]string {
+	if len(rows) > importSampleRows {
+		return rows[:importSampleRows]
+	}
+	return rows
+}
+
+// enumValue normalises a sheet value to an enum spelling: "Mobile App" is mobile_app
+func enumValue(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "_"), "-", "_")
+}
+
+// stripCurrency removes the currency marks and thousands separators a sheet
+// adds to amounts ("₹3,499.00", "Rs. 3499", "INR 3499")
+func stripCurrency(s string) string {
+	s = strings.ToLower(s)
+	for _, mark := range []string{"₹", "inr", "rs.", "rs", ",", " "} {
+		s = strings.ReplaceAll(s, mark, "")
+	}
+	return s
+}
+
+// parseImportDate parses a date in any accepted layout. Empty is fine and
+// returns nil; ok is false only for an unrecognised value.
+func parseImportDate(s string) (*time.Time, bool) {
+	if s == "" {
+		return nil, true
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
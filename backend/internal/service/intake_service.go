@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// maxDuplicateMatches caps how many earlier requests are reported as look-alikes
+const maxDuplicateMatches = 3
+
+type intakeService struct {
+	overrideRepo   domain.QuotaOverrideRepository
+	requestRepo    domain.BuildRequestRepository
+	userRepo       domain.UserRepository
+	pricingService domain.PricingService
+	cfg            config.IntakeConfig
+}
+
+// NewIntakeService creates a new intake service
+func NewIntakeService(
+	overrideRepo domain.QuotaOverrideRepository,
+	requestRepo domain.BuildRequestRepository,
+	userRepo domain.UserRepository,
+	pricingService domain.PricingService,
+	cfg config.IntakeConfig,
+) domain.IntakeService {
+	return &intakeService{
+		overrideRepo:   overrideRepo,
+		requestRepo:    requestRepo,
+		userRepo:       userRepo,
+		pricingService: pricingService,
+		cfg:            cfg,
+	}
+}
+
+// Check reports the student's quota usage and near-duplicates of the new
+// request. Staff and students with an active override are never refused, but
+// still see possible duplicates.
+func (s *intakeService) Check(ctx context.Context, user *domain.User, in *domain.CreateBuildRequest) (*domain.IntakeReport, error) {
+	now := time.Now()
+	semester, semesterStart := domain.Semester(now)
+	report := &domain.IntakeReport{
+		Quota: domain.QuotaUsage{
+			MaxOpenFree:    s.cfg.MaxOpenFreeRequests,
+			Semester:       semester,
+			SemesterStart:  semesterStart,
+			MaxPerSemester: s.cfg.MaxRequestsPerSemester,
+		},
+		Duplicates: []domain.DuplicateMatch{},
+		Allowed:    true,
+	}
+
+	exempt := user.Role != domain.RoleStudent
+	if !exempt {
+		override, err := s.overrideRepo.FindByUser(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find quota override: %w", err)
+		}
+		exempt = override != nil && override.ActiveAt(now)
+	}
+	report.Quota.Exempt = exempt
+
+	isFree := true
+	openFree, err := s.count(ctx, domain.RequestFilter{UserID: &user.ID, IsFree: &isFree, Statuses: openStatuses})
+	if err != nil {
+		return nil, err
+	}
+	report.Quota.OpenFree = openFree
+
+	semesterCount, err := s.count(ctx, domain.RequestFilter{UserID: &user.ID, CreatedFrom: &semesterStart, Statuses: liveStatuses()})
+	if err != nil {
+		return nil, err
+	}
+	report.Quota.SemesterCount = semesterCount
+
+	if err := s.findDuplicates(ctx, user.ID, in, now, report); err != nil {
+		return nil, err
+	}
+
+	if exempt {
+		return report, nil
+	}
+	if s.cfg.MaxRequestsPerSemester > 0 && semesterCount >= s.cfg.MaxRequestsPerSemester {
+		report.Refuse(fmt.Errorf("%w: at most %d requests per semester (%s)",
+			domain.ErrQuotaExceeded, s.cfg.MaxRequestsPerSemester, semester))
+	}
+	if s.cfg.MaxOpenFreeRequests > 0 && openFree >= s.cfg.MaxOpenFreeRequests {
+		// The free limit only matters if the new request would be free
+		quote, err := s.pricingService.Quote(ctx, domain.PricingInput{
+			RequestType: in.RequestType,
+			Complexity:  domain.ComplexityBasic,
+			HostingType: in.HostingType,
+			AddOns:      in.AddOns,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if quote.Free {
+			report.Refuse(fmt.Errorf("%w: at most %d free requests can be open at once",
+				domain.ErrQuotaExceeded, s.cfg.MaxOpenFreeRequests))
+		}
+	}
+	if s.cfg.BlockDuplicates && len(report.Duplicates) > 0 {
+		first := report.Duplicates[0]
+		report.Refuse(fmt.Errorf("%w: %q from %s", domain.ErrDuplicateRequest, first.Title, first.CreatedAt.Format("2 Jan 2006")))
+	}
+	return report, nil
+}
+
+func (s *intakeService) Preview(ctx context.Context, userID uuid.UUID, in *domain.CreateBuildRequest) (*domain.IntakeReport, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return s.Check(ctx, user, in)
+}
+
+func (s *intakeService) GetOverride(ctx context.Context, userID uuid.UUID) (*domain.QuotaOverride, error) {
+	override, err := s.overrideRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quota override: %w", err)
+	}
+	if override == nil {
+		return nil, domain.ErrQuotaOverrideNotFound
+	}
+	return override, nil
+}
+
+func (s *intakeService) GrantOverride(ctx context.Context, grantedBy, userID uuid.UUID, req *domain.GrantQuotaOverrideRequest) (*domain.QuotaOverride, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	override := &domain.QuotaOverride{
+		UserID:    userID,
+		Reason:    req.Reason,
+		GrantedBy: grantedBy,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if override.ExpiresAt != nil && !override.ActiveAt(override.CreatedAt) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", domain.ErrInvalidQuotaOverride)
+	}
+	if err := s.overrideRepo.Upsert(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to save quota override: %w", err)
+	}
+	return override, nil
+}
+
+func (s *intakeService) RevokeOverride(ctx context.Context, userID uuid.UUID) error {
+	if err := s.overrideRepo.Delete(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrQuotaOverrideNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to revoke quota override: %w", err)
+	}
+	return nil
+}
+
+// count returns how many requests match the filter
+func (s *intakeService) count(ctx context.Context, filter domain.RequestFilter) (int, error) {
+	filter.Limit = 1
+	filter.IncludeTotal = true
+	_, total, err := s.requestRepo.List(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count requests: %w", err)
+	}
+	return total, nil
+}
+
+// findDuplicates compares the new request with the student's recent ones
+func (s *intakeService) findDuplicates(ctx context.Context, userID uuid.UUID, in *domain.CreateBuildRequest, now time.Time, report *domain.IntakeReport) error {
+	if s.cfg.DuplicateThreshold <= 0 {
+		return nil
+	}
+	since := now.Add(-s.cfg.DuplicateLookback)
+	filter := domain.RequestFilter{
+		UserID:      &userID,
+		CreatedFrom: &since,
+		Statuses:    liveStatuses(),
+		SortDesc:    true,
+		Limit:       domain.MaxPageSize,
+	}
+	recent, _, err := s.requestRepo.List(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list recent requests: %w", err)
+	}
+
+	threshold := float64(s.cfg.DuplicateThreshold) / 100
+	for _, r := range recent {
+		score := domain.RequestSimilarity(in.Title, in.Description, r.Title, r.Description)
+		if score < threshold {
+			continue
+		}
+		report.Duplicates = append(report.Duplicates, domain.DuplicateMatch{
+			RequestID:  r.ID,
+			Title:      r.Title,
+			Status:     r.Status,
+			CreatedAt:  r.CreatedAt,
+			Similarity: float64(int(score*100+0.5)) / 100,
+		})
+	}
+	sort.SliceStable(report.Duplicates, func(i, j int) bool {
+		return report.Duplicates[i].Similarity > report.Duplicates[j].Similarity
+	})
+	if len(report.Duplicates) > maxDuplicateMatches {
+		report.Duplicates = report.Duplicates[:maxDuplicateMatches]
+	}
+	return nil
+}
+
+// liveStatuses are every request status except cancelled: withdrawn requests
+// neither count against quotas nor as duplicates
+func liveStatuses() []domain.RequestStatus {
+	return append(append([]domain.RequestStatus{}, openStatuses...),
+		domain.StatusCompleted, domain.StatusRejected)
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+type invoiceService struct {
+	invoiceRepo domain.InvoiceRepository
+	requestRepo domain.BuildRequestRepository
+	userRepo    domain.UserRepository
+	quoteRepo   domain.QuoteRepository
+	paymentRepo domain.PaymentRepository
+	renderer    domain.InvoiceRenderer
+	cfg         *config.Config
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(
+	invoiceRepo domain.InvoiceRepository,
+	requestRepo domain.BuildRequestRepository,
+	userRepo domain.UserRepository,
+	quoteRepo domain.QuoteRepository,
+	paymentRepo domain.PaymentRepository,
+	renderer domain.InvoiceRenderer,
+	cfg *config.Config,
+) domain.InvoiceService {
+	return &invoiceService{
+		invoiceRepo: invoiceRepo,
+		requestRepo: requestRepo,
+		userRepo:    userRepo,
+		quoteRepo:   quoteRepo,
+		paymentRepo: paymentRepo,
+		renderer:    renderer,
+		cfg:         cfg,
+	}
+}
+
+// AfterTransition issues the tax invoice and any outstanding receipts once a
+// paid request is completed. Failures are logged; staff can reissue manually.
+func (s *invoiceService) AfterTransition(ctx context.Context, req *domain.BuildRequest, from domain.RequestStatus) {
+	if req.IsFree || req.Status != domain.StatusCompleted {
+		return
+	}
+	if _, err := s.IssueForRequest(ctx, req.ID); err != nil {
+		log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to issue invoices")
+	}
+}
+
+// IssueForRequest issues a receipt for every captured payment and, once the
+// request is completed, the tax invoice for its accepted quote. Documents that
+// were already issued are left alone, so it is safe to call repeatedly.
+func (s *invoiceService) IssueForRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Invoice, error) {
+	req, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if req.IsFree {
+		return nil, domain.ErrInvoiceNotRequired
+	}
+
+	buyer, err := s.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if buyer == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// The accepted quote is what the payments are settling
+	quote, err := s.quoteRepo.FindAccepted(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accepted quote: %w", err)
+	}
+	due := req.EstimatedCost
+	if quote != nil {
+		due = quote.Total
+	}
+
+	ledger, err := s.paymentRepo.ListLedgerByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payments: %w", err)
+	}
+	paid := domain.NewMoney(0, due.Currency)
+	for _, entry := range ledger {
+		before := paid
+		switch entry.Kind {
+		case domain.LedgerPayment:
+			paid, err = paid.CheckedAdd(entry.Amount)
+		case domain.LedgerRefund:
+			paid, err = paid.CheckedSub(entry.Amount)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to total payments: %w", err)
+		}
+		if entry.Kind != domain.LedgerPayment {
+			continue
+		}
+		receipt := s.newInvoice(domain.InvoiceReceipt, req, buyer, entry.ID.String(), entry.Currency,
+			[]domain.QuoteLineItem{{Label: receiptLabel(before, paid, due) + req.Title, Amount: entry.Amount}})
+		if _, err := s.invoiceRepo.Issue(ctx, receipt, s.finalize); err != nil {
+			return nil, fmt.Errorf("failed to issue receipt: %w", err)
+		}
+	}
+
+	if req.Status == domain.StatusCompleted {
+		if quote == nil {
+			return nil, domain.ErrQuoteNotAccepted
+		}
+		inv := s.newInvoice(domain.InvoiceTax, req, buyer, quote.ID.String(), quote.Currency, quote.Lines)
+		if _, err := s.invoiceRepo.Issue(ctx, inv, s.finalize); err != nil {
+			return nil, fmt.Errorf("failed to issue invoice: %w", err)
+		}
+	}
+
+	return s.ListForRequest(ctx, requestID)
+}
+
+// receiptLabel describes a payment by what it did to the amount due: paid it
+// all at once, settled what was left, or went towards it in advance
+func receiptLabel(paidBefore, paidAfter, due domain.Money) string {
+	switch {
+	case !due.IsPositive() || paidAfter.Cmp(due) < 0:
+		return "Advance: "
+	case paidBefore.IsPositive():
+		return "Balance payment: "
+	default:
+		return "Payment in full: "
+	}
+}
+
+func (s *invoiceService) ListForRequest(ctx context.Context, requestID uuid.UUID) ([]domain.Invoice, error) {
+	invoices, err := s.invoiceRepo.ListByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+func (s *invoiceService) GetDocument(ctx context.Context, requestID, invoiceID uuid.UUID) (*domain.Artifact, error) {
+	invoices, err := s.ListForRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, inv := range invoices {
+		if inv.ID != invoiceID {
+			continue
+		}
+		artifact, err := s.invoiceRepo.FindArtifact(ctx, inv.ArtifactID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load document: %w", err)
+		}
+		if artifact == nil {
+			return nil, domain.ErrInvoiceNotFound
+		}
+		return artifact, nil
+	}
+	return nil, domain.ErrInvoiceNotFound
+}
+
+// newInvoice prices GST-inclusive items into taxable lines. Supply is
+// inter-state (IGST) when the place of supply differs from the seller's state.
+func (s *invoiceService) newInvoice(kind domain.InvoiceKind, req *domain.BuildRequest, buyer *domain.User, sourceRef, currency string, items []domain.QuoteLineItem) *domain.Invoice {
+	ic := s.cfg.Invoice
+	interState := ic.PlaceOfSupply != "" && ic.SellerState != "" && ic.PlaceOfSupply != ic.SellerState
+	now := time.Now()
+
+	inv := &domain.Invoice{
+		ID:            uuid.New(),
+		RequestID:     req.ID,
+		Kind:          kind,
+		FinancialYear: domain.FinancialYear(now),
+		SourceRef:     sourceRef,
+		IssuedAt:      now,
+		Currency:      currency,
+		BuyerName:     buyer.FullName,
+		BuyerEmail:    buyer.Email,
+		PlaceOfSupply: ic.PlaceOfSupply,
+	}
+	if inv.Currency == "" {
+		inv.Currency = domain.DefaultCurrency
+	}
+
+	zero := domain.NewMoney(0, inv.Currency)
+	inv.TaxableAmount, inv.CGST, inv.SGST, inv.IGST, inv.Total = zero, zero, zero, zero, zero
+	for _, item := range items {
+		amount := item.Amount.In(inv.Currency)
+		t, c, sg, ig := domain.SplitGST(amount, ic.GSTRate, interState)
+		inv.Lines = append(inv.Lines, domain.InvoiceLine{
+			Description: item.Label,
+			SACCode:     ic.SACCode,
+			Taxable:     t,
+			CGST:        c,
+			SGST:        sg,
+			IGST:        ig,
+			Total:       amount,
+		})
+		inv.TaxableAmount = inv.TaxableAmount.Add(t)
+		inv.CGST = inv.CGST.Add(c)
+		inv.SGST = inv.SGST.Add(sg)
+		inv.IGST = inv.IGST.Add(ig)
+		inv.Total = inv.Total.Add(amount)
+	}
+	return inv
+}
+
+// finalize numbers an invoice from its allocated sequence, e.g. MIE/25-26/00042
+// for invoices and MIER/25-26/00007 for receipts, then renders it
+func (s *invoiceService) finalize(inv *domain.Invoice) (*domain.Artifact, error) {
+	prefix := s.cfg.Invoice.NumberPrefix
+	if inv.Kind == domain.InvoiceReceipt {
+		prefix += "R"
+	}
+	inv.Number = fmt.Sprintf("%s/%s/%05d", prefix, inv.FinancialYear[2:], inv.Sequence)
+	return s.renderer.Render(inv)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// maxNotifications caps how many notifications are listed at once
+const maxNotifications = 100
+
+type notificationService struct {
+	notificationRepo domain.NotificationRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo domain.NotificationRepository) domain.NotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+func (s *notificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]domain.Notification, error) {
+	notifications, err := s.notificationRepo.ListForUser(ctx, userID, unreadOnly, maxNotifications)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+	return notifications, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.notificationRepo.MarkRead(ctx, userID, id); err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+type paymentService struct {
+	transactor    domain.Transactor
+	paymentRepo   domain.PaymentRepository
+	quoteRepo     domain.QuoteRepository
+	requestRepo   domain.BuildRequestRepository
+	promotionRepo domain.PromotionRepository
+	provider      domain.PaymentProvider
+	requirePaid   bool
+}
+
+// NewPaymentService creates a new payment service. When requirePaid is set,
+// paid requests cannot move to deploying until they are paid in full.
+// Refunds hold their intent's lock from the refundable check to the ledger
+// entry, so two of them cannot both spend the same capture.
+func NewPaymentService(
+	transactor domain.Transactor,
+	paymentRepo domain.PaymentRepository,
+	quoteRepo domain.QuoteRepository,
+	requestRepo domain.BuildRequestRepository,
+	promotionRepo domain.PromotionRepository,
+	provider domain.PaymentProvider,
+	requirePaid bool,
+) domain.PaymentService {
+	return &paymentService{
+		transactor:    transactor,
+		paymentRepo:   paymentRepo,
+		quoteRepo:     quoteRepo,
+		requestRepo:   requestRepo,
+		promotionRepo: promotionRepo,
+		provider:      provider,
+		requirePaid:   requirePaid,
+	}
+}
+
+// CheckTransition refuses to deploy or complete a paid request that has an
+// outstanding balance
+func (s *paymentService) CheckTransition(ctx context.Context, req *domain.BuildRequest, to domain.RequestStatus) error {
+	if !s.requirePaid || req.IsFree || (to != domain.StatusDeploying && to != domain.StatusCompleted) {
+		return nil
+	}
+	summary, err := s.Summary(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if summary.Standing != domain.PaymentPaidInFull {
+		return domain.ErrPaymentRequired
+	}
+	return nil
+}
+
+func (s *paymentService) CreateIntent(ctx context.Context, userID, requestID uuid.UUID, in *domain.CreatePaymentIntentRequest) (*domain.PaymentIntent, error) {
+	req, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if req.IsFree {
+		return nil, domain.ErrPaymentNotRequired
+	}
+
+	quote, err := s.quoteRepo.FindAccepted(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accepted quote: %w", err)
+	}
+	if quote == nil {
+		return nil, domain.ErrQuoteNotAccepted
+	}
+
+	summary, err := s.Summary(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !summary.Balance.IsPositive() {
+		return nil, fmt.Errorf("%w: nothing left to pay", domain.ErrInvalidPaymentAmount)
+	}
+
+	amount := summary.Balance
+	if in.Amount != nil {
+		amount = in.Amount.In(summary.Currency)
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("%w: amount must be positive", domain.ErrInvalidPaymentAmount)
+	}
+	if amount.Cmp(summary.Balance) > 0 {
+		return nil, fmt.Errorf("%w: %s exceeds the outstanding balance of %s",
+			domain.ErrInvalidPaymentAmount, amount.Decimal(), summary.Balance.Decimal())
+	}
+
+	// Reuse an unpaid intent for the same amount rather than opening another order
+	for i := range summary.Intents {
+		open := summary.Intents[i]
+		if open.QuoteID == quote.ID && open.Status == domain.IntentCreated && open.Amount.Cmp(amount) == 0 {
+			return &open, nil
+		}
+	}
+
+	now := time.Now()
+	intent := &domain.PaymentIntent{
+		ID:        uuid.New(),
+		RequestID: requestID,
+		QuoteID:   quote.ID,
+		Provider:  s.provider.Name(),
+		Amount:    amount,
+		Currency:  quote.Currency,
+		Status:    domain.IntentCreated,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	orderID, err := s.provider.CreateOrder(ctx, intent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment order: %w", err)
+	}
+	intent.ProviderOrderID = orderID
+
+	if err := s.paymentRepo.CreateIntent(ctx, intent); err != nil {
+		return nil, fmt.Errorf("failed to save payment intent: %w", err)
+	}
+	return intent, nil
+}
+
+func (s *paymentService) Summary(ctx context.Context, requestID uuid.UUID) (*domain.PaymentSummary, error) {
+	req, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+
+	intents, err := s.paymentRepo.ListIntentsByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment intents: %w", err)
+	}
+	ledger, err := s.paymentRepo.ListLedgerByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	summary := &domain.PaymentSummary{
+		RequestID: requestID,
+		Currency:  domain.DefaultPricingRules().Currency,
+		AmountDue: req.EstimatedCost,
+		Intents:   intents,
+		Ledger:    ledger,
+	}
+	if summary.Intents == nil {
+		summary.Intents = []domain.PaymentIntent{}
+	}
+	if summary.Ledger == nil {
+		summary.Ledger = []domain.LedgerEntry{}
+	}
+
+	// The accepted quote is what the student agreed to pay
+	quote, err := s.quoteRepo.FindAccepted(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accepted quote: %w", err)
+	}
+	if quote != nil {
+		summary.AmountDue = quote.Total
+		summary.Currency = quote.Currency
+	}
+
+	summary.AmountDue = summary.AmountDue.In(summary.Currency)
+	summary.AmountPaid = domain.NewMoney(0, summary.Currency)
+	summary.AmountRefunded = domain.NewMoney(0, summary.Currency)
+	for _, e := range ledger {
+		switch e.Kind {
+		case domain.LedgerPayment:
+			summary.AmountPaid, err = summary.AmountPaid.CheckedAdd(e.Amount)
+		case domain.LedgerRefund:
+			summary.AmountRefunded, err = summary.AmountRefunded.CheckedAdd(e.Amount)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to total payments: %w", err)
+		}
+	}
+	summary.Balance = summary.AmountDue.Sub(summary.NetPaid())
+	if summary.Balance.IsNegative() {
+		summary.Balance = domain.NewMoney(0, summary.Currency)
+	}
+
+	// Nothing due counts as paid only when a discount says so; a paid
+	// request priced at zero is a pricing mistake, not a gift
+	waived := false
+	if !req.IsFree && !summary.AmountDue.IsPositive() {
+		discounts, err := s.promotionRepo.ListByRequest(ctx, requestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list discounts: %w", err)
+		}
+		waived = domain.WaivesInFull(discounts)
+	}
+
+	switch {
+	case req.IsFree:
+		summary.Standing = domain.PaymentNotRequired
+	case summary.AmountDue.IsPositive() && summary.NetPaid().Cmp(summary.AmountDue) >= 0:
+		summary.Standing = domain.PaymentPaidInFull
+	case waived:
+		summary.Standing = domain.PaymentPaidInFull
+	case summary.NetPaid().IsPositive():
+		summary.Standing = domain.PaymentPartial
+	default:
+		summary.Standing = domain.PaymentUnpaid
+	}
+	return summary, nil
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, provider, signature string, body []byte) (int, error) {
+	if provider != s.provider.Name() {
+		return 0, domain.ErrUnknownPaymentProvider
+	}
+	events, err := s.provider.ParseWebhook(signature, body)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, ev := range events {
+		var intent *domain.PaymentIntent
+		entry := &domain.LedgerEntry{
+			ID:         uuid.New(),
+			Kind:       ev.Kind,
+			Amount:     ev.Amount,
+			Currency:   ev.Currency,
+			Provider:   provider,
+			OccurredAt: ev.OccurredAt,
+			CreatedAt:  time.Now(),
+		}
+
+		switch ev.Kind {
+		case domain.LedgerPayment:
+			intent, err = s.paymentRepo.FindIntentByOrderID(ctx, provider, ev.OrderID)
+			entry.ProviderRef = ev.PaymentID
+		case domain.LedgerRefund:
+			intent, err = s.paymentRepo.FindIntentByPaymentRef(ctx, provider, ev.PaymentID)
+			entry.ProviderRef = ev.RefundID
+			entry.PaymentRef = ev.PaymentID
+		}
+		if err != nil {
+			return applied, fmt.Errorf("failed to match payment event: %w", err)
+		}
+		if intent == nil {
+			// Not ours (e.g. a payment link created outside the app)
+			log.Warn().Str("provider", provider).Str("order_id", ev.OrderID).
+				Str("payment_id", ev.PaymentID).Msg("Ignoring payment event for unknown order")
+			continue
+		}
+
+		entry.IntentID = intent.ID
+		entry.RequestID = intent.RequestID
+		if entry.Currency == "" {
+			entry.Currency = intent.Currency
+		}
+		if entry.Currency != intent.Currency {
+			// Retrying will not change the currency, so refuse rather than fail
+			return applied, fmt.Errorf("%w: %s event for a %s payment", domain.ErrCurrencyMismatch, entry.Currency, intent.Currency)
+		}
+		entry.Amount = entry.Amount.In(entry.Currency)
+
+		inserted, err := s.paymentRepo.ApplyLedgerEntry(ctx, entry)
+		if err != nil {
+			return applied, fmt.Errorf("failed to record payment event: %w", err)
+		}
+		if inserted {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+func (s *paymentService) Refund(ctx context.Context, intentID uuid.UUID, in *domain.RefundRequest) (*domain.LedgerEntry, error) {
+	var entry *domain.LedgerEntry
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		intent, err := s.paymentRepo.LockIntentByID(ctx, intentID)
+		if err != nil {
+			return fmt.Errorf("failed to find payment intent: %w", err)
+		}
+		if intent == nil {
+			return domain.ErrPaymentIntentNotFound
+		}
+
+		owner, err := s.paymentRepo.FindIntentByPaymentRef(ctx, intent.Provider, in.PaymentRef)
+		if err != nil {
+			return fmt.Errorf("failed to find payment: %w", err)
+		}
+		if owner == nil || owner.ID != intent.ID {
+			return fmt.Errorf("%w: payment %s does not belong to this intent", domain.ErrInvalidPaymentAmount, in.PaymentRef)
+		}
+		amount := in.Amount.In(intent.Currency)
+		if !amount.IsPositive() {
+			return fmt.Errorf("%w: amount must be positive", domain.ErrInvalidPaymentAmount)
+		}
+
+		// Each capture can only return what it took in, whatever else the
+		// intent collected. The intent's lock keeps other refunds of it out
+		// until this one is recorded.
+		ledger, err := s.paymentRepo.ListLedgerByRequest(ctx, intent.RequestID)
+		if err != nil {
+			return fmt.Errorf("failed to list payments: %w", err)
+		}
+		refundable, ok, err := domain.Refundable(ledger, intent.Provider, in.PaymentRef)
+		if err != nil {
+			return fmt.Errorf("failed to total refunds: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: payment %s does not belong to this intent", domain.ErrInvalidPaymentAmount, in.PaymentRef)
+		}
+		if amount.Cmp(refundable) > 0 {
+			return fmt.Errorf("%w: at most %s of payment %s can be refunded",
+				domain.ErrInvalidPaymentAmount, refundable.Decimal(), in.PaymentRef)
+		}
+
+		refundID, err := s.provider.Refund(ctx, in.PaymentRef, amount)
+		if err != nil {
+			return fmt.Errorf("failed to refund payment: %w", err)
+		}
+
+		// Record now; the provider's refund webhook will be deduplicated
+		now := time.Now()
+		entry = &domain.LedgerEntry{
+			ID:          uuid.New(),
+			RequestID:   intent.RequestID,
+			IntentID:    intent.ID,
+			Kind:        domain.LedgerRefund,
+			Amount:      amount,
+			Currency:    intent.Currency,
+			Provider:    intent.Provider,
+			ProviderRef: refundID,
+			PaymentRef:  in.PaymentRef,
+			Note:        in.Reason,
+			OccurredAt:  now,
+			CreatedAt:   now,
+		}
+		if _, err := s.paymentRepo.ApplyLedgerEntry(ctx, entry); err != nil {
+			return fmt.Errorf("failed to record refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type plannerService struct {
+	tx              domain.Transactor
+	queueService    domain.QueueService
+	scheduleService domain.ScheduleService
+	scheduleRepo    domain.ScheduleRepository
+	requestRepo     domain.BuildRequestRepository
+	policy          domain.QueuePolicy
+	lifecycle       *domain.Lifecycle
+}
+
+// NewPlannerService creates a new weekend planner. Requests a lifecycle guard
+// would refuse to schedule are left out of proposals.
+func NewPlannerService(tx domain.Transactor, queueService domain.QueueService, scheduleService domain.ScheduleService, scheduleRepo domain.ScheduleRepository, requestRepo domain.BuildRequestRepository, cfg config.QueueConfig, lifecycle *domain.Lifecycle) domain.PlannerService {
+	return &plannerService{
+		tx:              tx,
+		queueService:    queueService,
+		scheduleService: scheduleService,
+		scheduleRepo:    scheduleRepo,
+		requestRepo:     requestRepo,
+		policy: domain.QueuePolicy{
+			OnePerStudent:  cfg.OnePerStudent,
+			FinalYearBoost: cfg.FinalYearBoost,
+		},
+		lifecycle: lifecycle,
+	}
+}
+
+func (s *plannerService) Propose(ctx context.Context, weekend time.Time) (*domain.WeekendPlan, error) {
+	var slots []domain.WeekendSlot
+	for _, date := range []time.Time{weekend, weekend.AddDate(0, 0, 1)} {
+		slot, err := s.scheduleRepo.FindSlotByDate(ctx, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find slot: %w", err)
+		}
+		if slot != nil {
+			slots = append(slots, *slot)
+		}
+	}
+	if len(slots) == 0 {
+		return nil, domain.ErrNoWeekendSlots
+	}
+	builders := make(map[uuid.UUID]domain.BuilderHours, len(slots))
+	for i := range slots {
+		left, err := s.scheduleService.BuilderHoursLeft(ctx, &slots[i])
+		if err != nil {
+			return nil, err
+		}
+		if left != nil {
+			builders[slots[i].ID] = left
+		}
+	}
+
+	ranked, err := s.queueService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Requests the lifecycle would refuse to schedule cannot be planned
+	eligible := make([]domain.QueueEntry, 0, len(ranked))
+	var refused []domain.PlanSkip
+	for _, e := range ranked {
+		req, err := s.requestRepo.FindByID(ctx, e.RequestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find request: %w", err)
+		}
+		if req == nil {
+			continue
+		}
+		if err := s.lifecycle.Check(ctx, req, domain.StatusScheduled); err != nil {
+			refused = append(refused, domain.PlanSkip{
+				RequestID: e.RequestID,
+				Title:     e.Title,
+				Position:  e.Position,
+				Hours:     domain.EstimatedHours(e.Complexity),
+				Reason:    err.Error(),
+			})
+			continue
+		}
+		eligible = append(eligible, e)
+	}
+
+	plan := domain.PlanWeekend(eligible, slots, builders, s.policy)
+	plan.Weekend = weekend
+	plan.Skipped = append(plan.Skipped, refused...)
+	sort.SliceStable(plan.Skipped, func(i, j int) bool { return plan.Skipped[i].Position < plan.Skipped[j].Position })
+	return plan, nil
+}
+
+func (s *plannerService) Apply(ctx context.Context, plan *domain.WeekendPlan) ([]domain.ScheduleEntry, error) {
+	if plan.Assignments() == 0 {
+		return nil, domain.ErrEmptyPlan
+	}
+
+	var entries []domain.ScheduleEntry
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		ids := make([]uuid.UUID, 0, len(plan.Slots))
+		for _, sp := range plan.Slots {
+			ids = append(ids, sp.SlotID)
+		}
+		slots, err := lockSlots(ctx, s.scheduleRepo, ids)
+		if err != nil {
+			return err
+		}
+		for _, sp := range plan.Slots {
+			slot := slots[sp.SlotID]
+			if slot == nil {
+				return domain.ErrSlotNotFound
+			}
+			if slot.Version != sp.Version {
+				return fmt.Errorf("the %s slot was booked meanwhile: %w", slot.DayOfWeek, domain.ErrPlanStale)
+			}
+		}
+
+		for _, sp := range plan.Slots {
+			for _, a := range sp.Add {
+				req, err := s.requestRepo.FindByID(ctx, a.RequestID)
+				if err != nil {
+					return fmt.Errorf("failed to find request: %w", err)
+				}
+				if req == nil {
+					return domain.ErrRequestNotFound
+				}
+				if req.Status != domain.StatusQueued {
+					return fmt.Errorf("%q is no longer queued: %w", req.Title, domain.ErrPlanStale)
+				}
+				entry, err := s.scheduleService.ScheduleRequest(ctx, a.RequestID, sp.SlotID, a.Hours)
+				if err != nil {
+					return fmt.Errorf("failed to book %q: %w", req.Title, err)
+				}
+				entries = append(entries, *entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// openStatuses are the request states whose estimate can still change
+var openStatuses = []domain.RequestStatus{
+	domain.StatusPending,
+	domain.StatusQueued,
+	domain.StatusScheduled,
+	domain.StatusBuilding,
+	domain.StatusReview,
+	domain.StatusDeploying,
+}
+
+type pricingService struct {
+	pricingRepo   domain.PricingRepository
+	requestRepo   domain.BuildRequestRepository
+	promotionRepo domain.PromotionRepository
+}
+
+// NewPricingService creates a new pricing service
+func NewPricingService(pricingRepo domain.PricingRepository, requestRepo domain.BuildRequestRepository, promotionRepo domain.PromotionRepository) domain.PricingService {
+	return &pricingService{
+		pricingRepo:   pricingRepo,
+		requestRepo:   requestRepo,
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *pricingService) ActiveRuleSet(ctx context.Context) (*domain.PricingRuleSet, error) {
+	set, err := s.pricingRepo.FindActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pricing rules: %w", err)
+	}
+	if set == nil {
+		// Nothing activated yet — fall back to the launch price list
+		return &domain.PricingRuleSet{
+			Name:   "Default pricing",
+			Status: domain.PricingActive,
+			Rules:  domain.DefaultPricingRules(),
+		}, nil
+	}
+	return set, nil
+}
+
+func (s *pricingService) Quote(ctx context.Context, in domain.PricingInput) (*domain.PriceBreakdown, error) {
+	set, err := s.ActiveRuleSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Rules.ValidateInput(in); err != nil {
+		return nil, err
+	}
+	b := set.Quote(in)
+	return &b, nil
+}
+
+func (s *pricingService) RequestBreakdown(ctx context.Context, req *domain.BuildRequest) (*domain.PriceBreakdown, error) {
+	// Price with the rule set the estimate was made under, if known
+	var set *domain.PricingRuleSet
+	var err error
+	if req.PricingVersion != nil {
+		set, err = s.GetRuleSet(ctx, *req.PricingVersion)
+	} else {
+		set, err = s.ActiveRuleSet(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	quote := set.Quote(req.PricingInput())
+	b := &quote
+	if err := s.applyDiscounts(ctx, req.ID, b); err != nil {
+		return nil, err
+	}
+
+	// Admins may have overridden the estimate; show the difference explicitly
+	if diff := req.EstimatedCost.In(b.Currency).Sub(b.Total); !diff.IsZero() {
+		b.Lines = append(b.Lines, domain.QuoteLineItem{
+			Code:   "manual_adjustment",
+			Label:  "Manual adjustment",
+			Amount: diff,
+		})
+		b.Total = b.Total.Add(diff)
+	}
+	return b, nil
+}
+
+func (s *pricingService) Reprice(ctx context.Context, req *domain.BuildRequest) (*domain.PriceBreakdown, error) {
+	set, err := s.ActiveRuleSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := set.Rules.ValidateInput(req.PricingInput()); err != nil {
+		return nil, err
+	}
+	quote := set.Quote(req.PricingInput())
+	if err := s.applyDiscounts(ctx, req.ID, &quote); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// applyDiscounts appends the request's discount lines to a rule breakdown
+func (s *pricingService) applyDiscounts(ctx context.Context, requestID uuid.UUID, b *domain.PriceBreakdown) error {
+	discounts, err := s.promotionRepo.ListByRequest(ctx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to list discounts: %w", err)
+	}
+	for _, line := range domain.DiscountLines(b.Subtotal, discounts) {
+		b.Lines = append(b.Lines, line)
+		b.Total = b.Total.Add(line.Amount)
+	}
+	return nil
+}
+
+func (s *pricingService) CreateDraft(ctx context.Context, createdBy uuid.UUID, req *domain.CreatePricingRuleSetRequest) (*domain.PricingRuleSet, error) {
+	if err := req.Rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	set := &domain.PricingRuleSet{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Status:    domain.PricingDraft,
+		Rules:     req.Rules,
+		CreatedAt: time.Now(),
+	}
+	if createdBy != uuid.Nil {
+		set.CreatedBy = &createdBy
+	}
+
+	if err := s.pricingRepo.Create(ctx, set); err != nil {
+		return nil, fmt.Errorf("failed to create pricing rule set: %w", err)
+	}
+	return set, nil
+}
+
+func (s *pricingService) ListRuleSets(ctx context.Context) ([]domain.PricingRuleSet, error) {
+	return s.pricingRepo.List(ctx)
+}
+
+func (s *pricingService) GetRuleSet(ctx context.Context, version int) (*domain.PricingRuleSet, error) {
+	set, err := s.pricingRepo.FindByVersion(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pricing rule set: %w", err)
+	}
+	if set == nil {
+		return nil, domain.ErrPricingRuleSetNotFound
+	}
+	return set, nil
+}
+
+func (s *pricingService) PreviewImpact(ctx context.Context, version int) (*domain.PricingImpact, error) {
+	candidate, err := s.GetRuleSet(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.ActiveRuleSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &domain.PricingImpact{
+		CurrentVersion: active.Version,
+		NewVersion:     candidate.Version,
+		CurrentTotal:   domain.NewMoney(0, candidate.Rules.Currency),
+		NewTotal:       domain.NewMoney(0, candidate.Rules.Currency),
+		Items:          []domain.PricingImpactItem{},
+	}
+
+	// Walk every open request, one page at a time
+	filter := domain.RequestFilter{Statuses: openStatuses, Limit: domain.MaxPageSize}
+	for {
+		requests, _, err := s.requestRepo.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open requests: %w", err)
+		}
+
+		for _, req := range requests {
+			priced := candidate.Quote(req.PricingInput())
+			if err := s.applyDiscounts(ctx, req.ID, &priced); err != nil {
+				return nil, err
+			}
+			newTotal := priced.Total
+			current := req.EstimatedCost.In(newTotal.Currency)
+			impact.Requests++
+			impact.CurrentTotal = impact.CurrentTotal.Add(current)
+			impact.NewTotal = impact.NewTotal.Add(newTotal)
+
+			delta := newTotal.Sub(current)
+			switch {
+			case delta.IsPositive():
+				impact.Increased++
+			case delta.IsNegative():
+				impact.Decreased++
+			default:
+				impact.Unchanged++
+				continue
+			}
+			impact.Items = append(impact.Items, domain.PricingImpactItem{
+				RequestID:    req.ID,
+				Title:        req.Title,
+				CurrentTotal: current,
+				NewTotal:     newTotal,
+				Delta:        delta,
+			})
+		}
+
+		if len(requests) < filter.Limit {
+			break
+		}
+		last := requests[len(requests)-1]
+		filter.After = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return impact, nil
+}
+
+func (s *pricingService) Activate(ctx context.Context, version int) (*domain.PricingRuleSet, error) {
+	set, err := s.GetRuleSet(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	if set.Status != domain.PricingDraft {
+		return nil, fmt.Errorf("only draft rule sets can be activated (version %d is %s)", version, set.Status)
+	}
+	if err := s.pricingRepo.Activate(ctx, version); err != nil {
+		return nil, fmt.Errorf("failed to activate pricing rule set: %w", err)
+	}
+	return s.GetRuleSet(ctx, version)
+}
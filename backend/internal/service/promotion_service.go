@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type promotionService struct {
+	transactor     domain.Transactor
+	promotionRepo  domain.PromotionRepository
+	requestRepo    domain.BuildRequestRepository
+	quoteRepo      domain.QuoteRepository
+	pricingService domain.PricingService
+}
+
+// NewPromotionService creates a new promotion service
+func NewPromotionService(
+	transactor domain.Transactor,
+	promotionRepo domain.PromotionRepository,
+	requestRepo domain.BuildRequestRepository,
+	quoteRepo domain.QuoteRepository,
+	pricingService domain.PricingService,
+) domain.PromotionService {
+	return &promotionService{
+		transactor:     transactor,
+		promotionRepo:  promotionRepo,
+		requestRepo:    requestRepo,
+		quoteRepo:      quoteRepo,
+		pricingService: pricingService,
+	}
+}
+
+func (s *promotionService) CreateCode(ctx context.Context, createdBy uuid.UUID, req *domain.CreateDiscountCodeRequest) (*domain.DiscountCode, error) {
+	code := &domain.DiscountCode{
+		ID:              uuid.New(),
+		Code:            strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:     req.Description,
+		Kind:            req.Kind,
+		MaxUses:         req.MaxUses,
+		PerStudentLimit: req.PerStudentLimit,
+		RequestTypes:    req.RequestTypes,
+		StartsAt:        req.StartsAt,
+		ExpiresAt:       req.ExpiresAt,
+		Active:          true,
+		CreatedBy:       createdBy,
+		CreatedAt:       time.Now(),
+	}
+	if code.Code == "" || strings.ContainsAny(code.Code, " \t") {
+		return nil, fmt.Errorf("%w: code must be a single word", domain.ErrInvalidDiscount)
+	}
+	if err := validateDiscount(req.Kind, req.Percent, req.Amount); err != nil {
+		return nil, err
+	}
+	code.Percent, code.Amount = discountValue(req.Kind, req.Percent, req.Amount)
+	if req.StartsAt != nil && req.ExpiresAt != nil && !req.ExpiresAt.After(*req.StartsAt) {
+		return nil, fmt.Errorf("%w: expires_at must be after starts_at", domain.ErrInvalidDiscount)
+	}
+
+	if err := s.promotionRepo.CreateCode(ctx, code); err != nil {
+		if errors.Is(err, domain.ErrDiscountCodeExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create discount code: %w", err)
+	}
+	return code, nil
+}
+
+func (s *promotionService) ListCodes(ctx context.Context) ([]domain.DiscountCode, error) {
+	codes, err := s.promotionRepo.ListCodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discount codes: %w", err)
+	}
+	if codes == nil {
+		codes = []domain.DiscountCode{}
+	}
+	return codes, nil
+}
+
+func (s *promotionService) UpdateCode(ctx context.Context, id uuid.UUID, req *domain.UpdateDiscountCodeRequest) (*domain.DiscountCode, error) {
+	code, err := s.promotionRepo.FindCodeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discount code: %w", err)
+	}
+	if code == nil {
+		return nil, domain.ErrDiscountCodeNotFound
+	}
+
+	if req.Active != nil {
+		code.Active = *req.Active
+	}
+	if req.MaxUses != nil {
+		code.MaxUses = req.MaxUses
+	}
+	if req.PerStudentLimit != nil {
+		code.PerStudentLimit = req.PerStudentLimit
+	}
+	if req.ExpiresAt != nil {
+		code.ExpiresAt = req.ExpiresAt
+	}
+
+	if err := s.promotionRepo.UpdateCode(ctx, code); err != nil {
+		return nil, fmt.Errorf("failed to update discount code: %w", err)
+	}
+	return code, nil
+}
+
+func (s *promotionService) Redeem(ctx context.Context, userID, requestID uuid.UUID, codeText string) (*domain.RequestDiscount, error) {
+	req, err := s.openRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.promotionRepo.FindCodeByCode(ctx, strings.TrimSpace(codeText))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find discount code: %w", err)
+	}
+	if code == nil {
+		return nil, domain.ErrDiscountCodeNotFound
+	}
+	if err := code.CheckRedeemable(req.RequestType, time.Now()); err != nil {
+		return nil, err
+	}
+
+	label := "Discount code " + code.Code
+	if code.Description != "" {
+		label = code.Description + " (" + code.Code + ")"
+	}
+	d := &domain.RequestDiscount{
+		ID:        uuid.New(),
+		RequestID: req.ID,
+		UserID:    req.UserID,
+		Source:    domain.DiscountFromCode,
+		CodeID:    &code.ID,
+		Code:      code.Code,
+		Label:     label,
+		Kind:      code.Kind,
+		Percent:   code.Percent,
+		Amount:    code.Amount,
+		CreatedAt: time.Now(),
+	}
+	if err := s.attach(ctx, req, d, s.promotionRepo.Redeem); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *promotionService) GrantWaiver(ctx context.Context, grantedBy, requestID uuid.UUID, in *domain.GrantWaiverRequest) (*domain.RequestDiscount, error) {
+	req, err := s.openRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDiscount(in.Kind, in.Percent, in.Amount); err != nil {
+		return nil, err
+	}
+	percent, amount := discountValue(in.Kind, in.Percent, in.Amount)
+
+	label := strings.TrimSpace(in.Label)
+	if label == "" {
+		label = "Fee waiver"
+	}
+	d := &domain.RequestDiscount{
+		ID:        uuid.New(),
+		RequestID: req.ID,
+		UserID:    req.UserID,
+		Source:    domain.DiscountFromWaiver,
+		Label:     label,
+		Kind:      in.Kind,
+		Percent:   percent,
+		Amount:    amount.In(req.Currency),
+		Reason:    in.Reason,
+		GrantedBy: &grantedBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.attach(ctx, req, d, s.promotionRepo.AddWaiver); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *promotionService) RemoveDiscount(ctx context.Context, requestID, discountID uuid.UUID) error {
+	d, err := s.promotionRepo.FindDiscount(ctx, discountID)
+	if err != nil {
+		return fmt.Errorf("failed to find discount: %w", err)
+	}
+	if d == nil || d.RequestID != requestID {
+		return domain.ErrDiscountNotFound
+	}
+	req, err := s.openRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.discountTotal(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err := s.promotionRepo.RemoveDiscount(ctx, d); err != nil {
+			if errors.Is(err, domain.ErrDiscountNotFound) {
+				return err
+			}
+			return fmt.Errorf("failed to remove discount: %w", err)
+		}
+		after, err := s.discountTotal(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.adjustEstimate(ctx, req, before, after)
+	})
+}
+
+func (s *promotionService) ListForRequest(ctx context.Context, requestID uuid.UUID) ([]domain.RequestDiscount, error) {
+	discounts, err := s.promotionRepo.ListByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list discounts: %w", err)
+	}
+	if discounts == nil {
+		discounts = []domain.RequestDiscount{}
+	}
+	return discounts, nil
+}
+
+func (s *promotionService) Report(ctx context.Context, from, to *time.Time) (*domain.PromotionReport, error) {
+	items, err := s.promotionRepo.Report(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build promotion report: %w", err)
+	}
+
+	report := &domain.PromotionReport{
+		From:       from,
+		To:         to,
+		Currency:   domain.DefaultPricingRules().Currency,
+		Discounted: domain.NewMoney(0, domain.DefaultPricingRules().Currency),
+		Items:      []domain.PromotionReportItem{},
+	}
+	for _, item := range items {
+		report.Discounted = report.Discounted.Add(item.Discounted)
+		report.Items = append(report.Items, item)
+	}
+	return report, nil
+}
+
+// openRequest loads a request whose price can still change: it must be paid
+// work without an accepted quote
+func (s *promotionService) openRequest(ctx context.Context, requestID uuid.UUID) (*domain.BuildRequest, error) {
+	req, err := s.requestRepo.FindByID(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if req.IsFree {
+		return nil, fmt.Errorf("%w: free requests have nothing to discount", domain.ErrDiscountNotApplicable)
+	}
+	quote, err := s.quoteRepo.FindAccepted(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accepted quote: %w", err)
+	}
+	if quote != nil {
+		return nil, domain.ErrPriceLocked
+	}
+	return req, nil
+}
+
+// attach stores a discount with the given repository call and takes its value
+// off the request's estimate, both in one transaction
+func (s *promotionService) attach(ctx context.Context, req *domain.BuildRequest, d *domain.RequestDiscount,
+	store func(context.Context, *domain.RequestDiscount) error) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.discountTotal(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		// Value it as the last discount on the current breakdown
+		b, err := s.pricingService.RequestBreakdown(ctx, req)
+		if err != nil {
+			return err
+		}
+		existing, err := s.promotionRepo.ListByRequest(ctx, req.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list discounts: %w", err)
+		}
+		d.Amount = d.Amount.In(b.Currency)
+		d.AppliedAmount = domain.NewMoney(0, b.Currency)
+		if lines := domain.DiscountLines(b.Subtotal, append(existing, *d)); len(lines) > len(domain.DiscountLines(b.Subtotal, existing)) {
+			d.AppliedAmount = lines[len(lines)-1].Amount.Neg()
+		}
+
+		if err := store(ctx, d); err != nil {
+			return err
+		}
+		after, err := s.discountTotal(ctx, req)
+		if err != nil {
+			return err
+		}
+		return s.adjustEstimate(ctx, req, before, after)
+	})
+}
+
+// discountTotal is the value of the request's current discounts
+func (s *promotionService) discountTotal(ctx context.Context, req *domain.BuildRequest) (domain.Money, error) {
+	b, err := s.pricingService.RequestBreakdown(ctx, req)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	total := domain.NewMoney(0, b.Currency)
+	for _, line := range b.Lines {
+		if line.Code == "waiver" || strings.HasPrefix(line.Code, "discount_") {
+			total = total.Sub(line.Amount)
+		}
+	}
+	return total, nil
+}
+
+// adjustEstimate moves the estimate by the change in discounts, keeping any
+// manual adjustment staff have made
+func (s *promotionService) adjustEstimate(ctx context.Context, req *domain.BuildRequest, before, after domain.Money) error {
+	estimate := req.EstimatedCost.Sub(after.Sub(before))
+	if estimate.IsNegative() {
+		estimate = domain.NewMoney(0, estimate.Currency)
+	}
+	req.EstimatedCost = estimate
+	req.UpdatedAt = time.Now()
+	if err := s.requestRepo.Update(ctx, req); err != nil {
+		return fmt.Errorf("failed to update request estimate: %w", err)
+	}
+	return nil
+}
+
+func validateDiscount(kind domain.DiscountKind, percent float64, amount domain.Money) error {
+	switch kind {
+	case domain.DiscountPercent:
+		if percent <= 0 || percent > 100 {
+			return fmt.Errorf("%w: percent must be between 0 and 100", domain.ErrInvalidDiscount)
+		}
+	case domain.DiscountFixed:
+		if !amount.IsPositive() {
+			return fmt.Errorf("%w: amount must be positive", domain.ErrInvalidDiscount)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", domain.ErrInvalidDiscount, kind)
+	}
+	return nil
+}
+
+// discountValue keeps only the field that matters for the kind
+func discountValue(kind domain.DiscountKind, percent float64, amount domain.Money) (float64, domain.Money) {
+	if kind == domain.DiscountPercent {
+		return percent, domain.NewMoney(0, amount.Currency)
+	}
+	return 0, amount
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/config"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+// queueEstimateSlots is how many upcoming slots (days) the weekend estimate
+// looks at
+const queueEstimateSlots = 20
+
+type queueService struct {
+	queueRepo    domain.QueueRepository
+	scheduleRepo domain.ScheduleRepository
+	policy       domain.QueuePolicy
+}
+
+// NewQueueService creates a new build queue service
+func NewQueueService(queueRepo domain.QueueRepository, scheduleRepo domain.ScheduleRepository, cfg config.QueueConfig) domain.QueueService {
+	return &queueService{
+		queueRepo:    queueRepo,
+		scheduleRepo: scheduleRepo,
+		policy: domain.QueuePolicy{
+			OnePerStudent:  cfg.OnePerStudent,
+			FinalYearBoost: cfg.FinalYearBoost,
+		},
+	}
+}
+
+func (s *queueService) List(ctx context.Context) ([]domain.QueueEntry, error) {
+	entries, err := s.queueRepo.ListQueued(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue: %w", err)
+	}
+	return domain.RankQueue(entries, s.policy, time.Now()), nil
+}
+
+func (s *queueService) Position(ctx context.Context, requestID uuid.UUID) (*domain.QueuePosition, error) {
+	ranked, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var entry *domain.QueueEntry
+	for i := range ranked {
+		if ranked[i].RequestID == requestID {
+			entry = &ranked[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, domain.ErrNotQueued
+	}
+
+	slots, err := s.scheduleRepo.ListUpcomingSlots(ctx, queueEstimateSlots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list slots: %w", err)
+	}
+	// Only the requests ahead, and this one, compete for the slots
+	estimates := domain.EstimateWeekends(ranked[:entry.Position], slots)
+
+	pos := &domain.QueuePosition{
+		RequestID:      requestID,
+		Position:       entry.Position,
+		QueueLength:    len(ranked),
+		EstimatedHours: domain.EstimatedHours(entry.Complexity),
+		FinalYear:      entry.FinalYear,
+		Deferred:       entry.Deferred,
+	}
+	if weekend, ok := estimates[requestID]; ok {
+		pos.EstimatedWeekend = &weekend
+	}
+	return pos, nil
+}
+
+func (s *queueService) SetGraduationYear(ctx context.Context, userID uuid.UUID, year *int) error {
+	return s.queueRepo.SetGraduationYear(ctx, userID, year)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/makeitexist/backend/internal/domain"
+)
+
+type quoteService struct {
+	transactor     domain.Transactor
+	quoteRepo      domain.QuoteRepository
+	requestRepo    domain.BuildRequestRepository
+	pricingService domain.PricingService
+}
+
+// NewQuoteService creates a new quote service
+func NewQuoteService(transactor domain.Transactor, quoteRepo domain.QuoteRepository, requestRepo domain.BuildRequestRepository, pricingService domain.PricingService) domain.QuoteService {
+	return &quoteService{
+		transactor:     transactor,
+		quoteRepo:      quoteRepo,
+		requestRepo:    requestRepo,
+		pricingService: pricingService,
+	}
+}
+
+// CheckTransition refuses to queue or schedule a paid request until the
+// student has accepted a quote
+func (s *quoteService) CheckTransition(ctx context.Context, req *domain.BuildRequest, to domain.RequestStatus) error {
+	if req.IsFree || (to != domain.StatusQueued && to != domain.StatusScheduled) {
+		return nil
+	}
+	accepted, err := s.quoteRepo.FindAccepted(ctx, req.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check quote: %w", err)
+	}
+	if accepted == nil {
+		return domain.ErrQuoteNotAccepted
+	}
+	return nil
+}
+
+func (s *quoteService) Create(ctx context.Context, builderID, requestID uuid.UUID, in *domain.CreateQuoteRequest) (*domain.Quote, error) {
+	req, err := s.loadRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsFree {
+		return nil, domain.ErrQuoteNotRequired
+	}
+	if !in.ValidUntil.After(time.Now()) {
+		return nil, fmt.Errorf("%w: valid_until must be in the future", domain.ErrInvalidQuote)
+	}
+
+	// Default to the request's current itemized estimate
+	currency := domain.DefaultPricingRules().Currency
+	lines := in.Lines
+	pricingVersion := req.PricingVersion
+	if len(lines) == 0 {
+		breakdown, err := s.pricingService.RequestBreakdown(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		lines = breakdown.Lines
+		currency = breakdown.Currency
+	} else {
+		pricingVersion = nil // hand-written lines are not tied to a rule set
+	}
+
+	total := domain.NewMoney(0, currency)
+	for i, line := range lines {
+		if line.Label == "" {
+			return nil, fmt.Errorf("%w: every line needs a label", domain.ErrInvalidQuote)
+		}
+		lines[i].Amount = line.Amount.In(currency)
+		total = total.Add(lines[i].Amount)
+	}
+	if total.IsNegative() {
+		return nil, fmt.Errorf("%w: total cannot be negative", domain.ErrInvalidQuote)
+	}
+
+	now := time.Now()
+	quote := &domain.Quote{
+		ID:             uuid.New(),
+		RequestID:      requestID,
+		Status:         domain.QuoteDraft,
+		Currency:       currency,
+		Lines:          lines,
+		Total:          total,
+		ValidUntil:     in.ValidUntil,
+		Notes:          in.Notes,
+		PricingVersion: pricingVersion,
+		CreatedBy:      builderID,
+		CreatedAt:      now,
+	}
+	if in.Send {
+		quote.Status = domain.QuoteSent
+		quote.SentAt = &now
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		revision, err := s.quoteRepo.NextRevision(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to number quote: %w", err)
+		}
+		quote.Revision = revision
+		if err := s.quoteRepo.Create(ctx, quote); err != nil {
+			return fmt.Errorf("failed to create quote: %w", err)
+		}
+
+		// A new revision replaces any quote still awaiting a decision
+		if err := s.quoteRepo.SupersedeOpen(ctx, requestID, quote.ID); err != nil {
+			return fmt.Errorf("failed to supersede previous quotes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (s *quoteService) Send(ctx context.Context, quoteID uuid.UUID) (*domain.Quote, error) {
+	quote, err := s.loadQuote(ctx, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != domain.QuoteDraft {
+		return nil, domain.ErrQuoteNotOpen
+	}
+
+	now := time.Now()
+	quote.Status = domain.QuoteSent
+	quote.SentAt = &now
+	if err := s.quoteRepo.UpdateStatus(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to send quote: %w", err)
+	}
+	return quote, nil
+}
+
+func (s *quoteService) ListForRequest(ctx context.Context, requestID uuid.UUID, includeDrafts bool) ([]domain.Quote, error) {
+	quotes, err := s.quoteRepo.ListByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quotes: %w", err)
+	}
+
+	visible := []domain.Quote{}
+	now := time.Now()
+	for _, q := range quotes {
+		if q.Status == domain.QuoteDraft && !includeDrafts {
+			continue
+		}
+		if q.IsOpen() && now.After(q.ValidUntil) {
+			q.Status = domain.QuoteExpired
+		}
+		visible = append(visible, q)
+	}
+	return visible, nil
+}
+
+func (s *quoteService) Accept(ctx context.Context, studentID, requestID, quoteID uuid.UUID, in *domain.RespondQuoteRequest) (*domain.Quote, error) {
+	req, quote, err := s.loadForResponse(ctx, studentID, requestID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote.Status = domain.QuoteAccepted
+	quote.RespondedAt = &now
+	quote.ResponseNote = in.Note
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.quoteRepo.MarkAccepted(ctx, quote); err != nil {
+			return fmt.Errorf("failed to accept quote: %w", err)
+		}
+
+		// The accepted quote becomes the agreed price
+		req.EstimatedCost = quote.Total
+		req.PricingVersion = quote.PricingVersion
+		req.UpdatedAt = now
+		if err := s.requestRepo.Update(ctx, req); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+func (s *quoteService) Decline(ctx context.Context, studentID, requestID, quoteID uuid.UUID, in *domain.RespondQuoteRequest) (*domain.Quote, error) {
+	_, quote, err := s.loadForResponse(ctx, studentID, requestID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote.Status = domain.QuoteDeclined
+	quote.RespondedAt = &now
+	quote.ResponseNote = in.Note
+	if err := s.quoteRepo.UpdateStatus(ctx, quote); err != nil {
+		return nil, fmt.Errorf("failed to decline quote: %w", err)
+	}
+	return quote, nil
+}
+
+// loadForResponse checks that the student owns the request and that the
+// quote was sent to them and is still open
+func (s *quoteService) loadForResponse(ctx context.Context, studentID, requestID, quoteID uuid.UUID) (*domain.BuildRequest, *domain.Quote, error) {
+	req, err := s.loadRequest(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.UserID != studentID {
+		return nil, nil, domain.ErrForbidden
+	}
+
+	quote, err := s.loadQuote(ctx, quoteID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if quote.RequestID != requestID || quote.Status == domain.QuoteDraft {
+		return nil, nil, domain.ErrQuoteNotFound
+	}
+	if quote.Status != domain.QuoteSent {
+		return nil, nil, domain.ErrQuoteNotOpen
+	}
+	if time.Now().After(quote.ValidUntil) {
+		quote.Status = domain.QuoteExpired
+		_ = s.quoteRepo.UpdateStatus(ctx, quote)
+		return nil, nil, domain.ErrQuoteExpired
+	}
+	return req, quote, nil
+}
+
+func (s *quoteService) loadRequest(ctx context.Context, id uuid.UUID) (*domain.BuildRequest, error) {
+	req, err := s.requestRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	return req, nil
+}
+
+func (s *quoteService) loadQuote(ctx context.Context, id uuid.UUID) (*domain.Quote, error) {
+	quote, err := s.quoteRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quote: %w", err)
+	}
+	if quote == nil {
+		return nil, domain.ErrQuoteNotFound
+	}
+	return quote, nil
+}
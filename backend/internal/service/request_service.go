@@ -10,63 +10,126 @@ import (
 	"github.com/makeitexist/backend/internal/domain"
 )
 
+// errBulkRejected rolls back an atomic bulk run that has failed items
+var errBulkRejected = errors.New("bulk run rejected")
+
 type requestService struct {
-	requestRepo domain.BuildRequestRepository
-	userRepo    domain.UserRepository
+	transactor      domain.Transactor
+	requestRepo     domain.BuildRequestRepository
+	userRepo        domain.UserRepository
+	pricingService  domain.PricingService
+	intakeService   domain.IntakeService
+	scheduleService domain.ScheduleService
+	lifecycle       *domain.Lifecycle
 }
 
-// NewRequestService creates a new build request service
-func NewRequestService(requestRepo domain.BuildRequestRepository, userRepo domain.UserRepository) domain.BuildRequestService {
+// NewRequestService creates a new build request service. New requests are
+// checked against the intake policy in the transaction that creates them.
+// The lifecycle's guards are consulted
+// before every status change and its observers are notified once the change
+// is saved. A booked request's slot hours move with it to a new builder.
+func NewRequestService(
+	transactor domain.Transactor,
+	requestRepo domain.BuildRequestRepository,
+	userRepo domain.UserRepository,
+	pricingService domain.PricingService,
+	intakeService domain.IntakeService,
+	scheduleService domain.ScheduleService,
+	lifecycle *domain.Lifecycle,
+) domain.BuildRequestService {
 	return &requestService{
-		requestRepo: requestRepo,
-		userRepo:    userRepo,
+		transactor:      transactor,
+		requestRepo:     requestRepo,
+		userRepo:        userRepo,
+		pricingService:  pricingService,
+		intakeService:   intakeService,
+		scheduleService: scheduleService,
+		lifecycle:       lifecycle,
 	}
 }
 
 func (s *requestService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateBuildRequest) (*domain.BuildRequest, error) {
-	// Verify user exists
-	user, err := s.userRepo.FindByID(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find user: %w", err)
-	}
-	if user == nil {
-		return nil, errors.New("user not found")
-	}
-
-	// Determine if request is free or paid
-	isFree := req.RequestType == domain.RequestTypeWebsite && req.HostingType != domain.HostingWhitelabel
-
-	// Calculate estimated cost
-	complexity := domain.ComplexityBasic // Default, admin can update
-	estimatedCost := domain.CalculateCost(req.RequestType, complexity, req.HostingType)
-
-	// All requests start as pending — pricing is discussed offline with the builder
-	buildReq := &domain.BuildRequest{
-		ID:                 uuid.New(),
-		UserID:             userID,
-		Title:              req.Title,
-		Description:        req.Description,
-		RequestType:        req.RequestType,
-		Status:             domain.StatusPending,
-		Complexity:         complexity,
-		HostingType:        req.HostingType,
-		WhitelabelDomain:   req.WhitelabelDomain,
-		WhitelabelBranding: req.WhitelabelBranding,
-		WhitelabelHosting:  req.WhitelabelHosting,
-		TechRequirements:   req.TechRequirements,
-		ReferenceLinks:     req.ReferenceLinks,
-		Figma:              req.Figma,
-		HostingEmail:       req.HostingEmail,
-		EstimatedCost:      estimatedCost,
-		IsFree:             isFree,
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
-	}
-
-	if err := s.requestRepo.Create(ctx, buildReq); err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Whitelabel domains are verified in DNS later, so store the bare host
+	if req.WhitelabelDomain != "" {
+		host, err := domain.NormalizeDomain(req.WhitelabelDomain)
+		if err != nil {
+			return nil, err
+		}
+		req.WhitelabelDomain = host
 	}
 
+	var buildReq *domain.BuildRequest
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		// Lock the user so their concurrent submissions are counted against
+		// the quota one at a time
+		user, err := s.userRepo.LockByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+		if user == nil {
+			return domain.ErrUserNotFound
+		}
+
+		// Enforce quotas and catch resubmissions
+		intake, err := s.intakeService.Check(ctx, user, req)
+		if err != nil {
+			return err
+		}
+		if err := intake.Err(); err != nil {
+			return err
+		}
+
+		// Price with the active rule set
+		complexity := domain.ComplexityBasic // Default, admin can update
+		quote, err := s.pricingService.Quote(ctx, domain.PricingInput{
+			RequestType: req.RequestType,
+			Complexity:  complexity,
+			HostingType: req.HostingType,
+			AddOns:      req.AddOns,
+		})
+		if err != nil {
+			return err
+		}
+		// Free is policy, not price: a paid request that prices to zero still
+		// needs its quote approved
+		isFree := quote.Free
+
+		// All requests start as pending — pricing is discussed offline with the builder
+		buildReq = &domain.BuildRequest{
+			ID:                 uuid.New(),
+			UserID:             userID,
+			Title:              req.Title,
+			Description:        req.Description,
+			RequestType:        req.RequestType,
+			Status:             domain.StatusPending,
+			Complexity:         complexity,
+			HostingType:        req.HostingType,
+			WhitelabelDomain:   req.WhitelabelDomain,
+			WhitelabelBranding: req.WhitelabelBranding,
+			WhitelabelHosting:  req.WhitelabelHosting,
+			TechRequirements:   req.TechRequirements,
+			ReferenceLinks:     req.ReferenceLinks,
+			Figma:              req.Figma,
+			HostingEmail:       req.HostingEmail,
+			EstimatedCost:      quote.Total,
+			Currency:           quote.Currency,
+			IsFree:             isFree,
+			AddOns:             req.AddOns,
+			PricingVersion:     ruleVersion(quote),
+			Version:            1,
+			CreatedAt:          time.Now(),
+			UpdatedAt:          time.Now(),
+			PossibleDuplicates: intake.Duplicates,
+		}
+
+		if err := s.requestRepo.Create(ctx, buildReq); err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return buildReq, nil
 }
 
@@ -76,36 +139,96 @@ func (s *requestService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Bui
 		return nil, fmt.Errorf("failed to find request: %w", err)
 	}
 	if req == nil {
-		return nil, errors.New("request not found")
+		return nil, domain.ErrRequestNotFound
 	}
 	return req, nil
 }
 
 func (s *requestService) Update(ctx context.Context, id uuid.UUID, updateReq *domain.UpdateBuildRequest) (*domain.BuildRequest, error) {
-	req, err := s.requestRepo.FindByID(ctx, id)
+	var req *domain.BuildRequest
+	var previousStatus domain.RequestStatus
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		req, err = s.requestRepo.FindByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to find request: %w", err)
+		}
+		if req == nil {
+			return domain.ErrRequestNotFound
+		}
+		if updateReq.IfVersion != nil && *updateReq.IfVersion != req.Version {
+			return domain.ErrVersionConflict
+		}
+
+		previousStatus = req.Status
+		if err := s.applyUpdate(ctx, req, updateReq); err != nil {
+			return err
+		}
+
+		if err := s.requestRepo.Update(ctx, req); err != nil {
+			return fmt.Errorf("failed to update request: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to find request: %w", err)
+		return nil, err
 	}
-	if req == nil {
-		return nil, errors.New("request not found")
+
+	if req.Status != previousStatus {
+		s.lifecycle.Notify(ctx, req, previousStatus)
 	}
 
-	// Apply updates
+	return req, nil
+}
+
+// applyUpdate checks and applies an admin update to req in memory; the
+// caller saves it. A new builder takes over the request's bookings straight
+// away, so call it in the transaction that saves req.
+func (s *requestService) applyUpdate(ctx context.Context, req *domain.BuildRequest, updateReq *domain.UpdateBuildRequest) error {
 	if updateReq.Status != nil {
+		if *updateReq.Status != req.Status {
+			// A scheduled request holds slot hours, which only a booking takes
+			if *updateReq.Status == domain.StatusScheduled {
+				return domain.ErrScheduleDerived
+			}
+			if err := s.lifecycle.Check(ctx, req, *updateReq.Status); err != nil {
+				return err
+			}
+		}
 		req.Status = *updateReq.Status
 	}
-	if updateReq.Complexity != nil {
-		req.Complexity = *updateReq.Complexity
-		// Recalculate cost
-		req.EstimatedCost = domain.CalculateCost(req.RequestType, req.Complexity, req.HostingType)
+	if updateReq.Complexity != nil || updateReq.AddOns != nil {
+		if updateReq.Complexity != nil {
+			req.Complexity = *updateReq.Complexity
+		}
+		if updateReq.AddOns != nil {
+			req.AddOns = updateReq.AddOns
+		}
+		// Requote with the active rule set, keeping any discounts
+		quote, err := s.pricingService.Reprice(ctx, req)
+		if err != nil {
+			return err
+		}
+		req.EstimatedCost = quote.Total
+		req.Currency = quote.Currency
+		req.PricingVersion = ruleVersion(quote)
+		// Add-ons can make a free website paid, and dropping them free again
+		req.IsFree = quote.Free
 	}
 	if updateReq.EstimatedCost != nil {
-		req.EstimatedCost = *updateReq.EstimatedCost
+		req.EstimatedCost = updateReq.EstimatedCost.In(req.Currency)
 	}
-	if updateReq.ScheduledWeekend != nil {
-		req.ScheduledWeekend = *updateReq.ScheduledWeekend
+	// The weekend follows the slot booking, so only the schedule endpoints move it
+	if updateReq.ScheduledWeekend != nil && !updateReq.ScheduledWeekend.Equal(req.ScheduledWeekend) {
+		return domain.ErrWeekendDerived
 	}
 	if updateReq.BuilderID != nil {
+		// Booked hours count against the builder, so they must have room
+		if req.BuilderID == nil || *req.BuilderID != *updateReq.BuilderID {
+			if err := s.scheduleService.AssignBuilder(ctx, req, updateReq.BuilderID); err != nil {
+				return err
+			}
+		}
 		req.BuilderID = updateReq.BuilderID
 	}
 	if updateReq.DeliveryURL != nil {
@@ -122,23 +245,231 @@ func (s *requestService) Update(ctx context.Context, id uuid.UUID, updateReq *do
 	}
 
 	req.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *requestService) ListByUser(ctx context.Context, userID uuid.UUID, filter domain.RequestFilter) ([]domain.BuildRequest, int, error) {
+	filter.UserID = &userID
+	filter.Limit = domain.ClampPageSize(filter.Limit, domain.DefaultPageSize)
+	return s.requestRepo.List(ctx, filter)
+}
 
-	if err := s.requestRepo.Update(ctx, req); err != nil {
-		return nil, fmt.Errorf("failed to update request: %w", err)
+func (s *requestService) ListAll(ctx context.Context, filter domain.RequestFilter) ([]domain.BuildRequest, int, error) {
+	filter.Limit = domain.ClampPageSize(filter.Limit, domain.DefaultPageSize)
+	return s.requestRepo.List(ctx, filter)
+}
+
+func (s *requestService) BulkUpdate(ctx context.Context, in *domain.BulkUpdateRequest) (*domain.BulkResult, error) {
+	change, label, err := s.bulkChange(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.bulkTargets(ctx, in)
+	if err != nil {
+		return nil, err
 	}
 
-	return req, nil
+	mode := in.Mode
+	if mode == "" {
+		mode = domain.BulkAtomic
+	}
+	result := &domain.BulkResult{
+		Operation: in.Operation,
+		Mode:      mode,
+		Matched:   len(ids),
+		Items:     make([]domain.BulkItemResult, len(ids)),
+	}
+
+	// Validate every item against the lifecycle before saving any of them.
+	// Staging can move a request's bookings to a new builder, so it runs in
+	// the transaction that saves the item.
+	staged := make([]*domain.BuildRequest, len(ids))
+	previous := make([]domain.RequestStatus, len(ids))
+	stage := func(ctx context.Context, i int) error {
+		req, err := s.requestRepo.FindByID(ctx, ids[i])
+		if err == nil && req == nil {
+			err = domain.ErrRequestNotFound
+		}
+		if err == nil {
+			previous[i] = req.Status
+			err = s.applyBulkChange(ctx, req, in.Operation, change, label)
+		}
+		if err != nil {
+			result.Items[i].Error = err.Error()
+			result.Failed++
+			return err
+		}
+		staged[i] = req
+		return nil
+	}
+
+	if mode == domain.BulkAtomic {
+		err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			for i, id := range ids {
+				result.Items[i].RequestID = id
+				stage(ctx, i)
+			}
+			if result.Failed > 0 {
+				return errBulkRejected
+			}
+			if err := s.requestRepo.UpdateMany(ctx, staged); err != nil {
+				return fmt.Errorf("failed to update requests: %w", err)
+			}
+			return nil
+		})
+		if errors.Is(err, errBulkRejected) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for i, id := range ids {
+			result.Items[i].RequestID = id
+			err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+				if err := stage(ctx, i); err != nil {
+					return err
+				}
+				if err := s.requestRepo.Update(ctx, staged[i]); err != nil {
+					result.Items[i].Error = "failed to update request"
+					result.Failed++
+					return err
+				}
+				return nil
+			})
+			if err != nil {
+				staged[i] = nil
+			}
+		}
+	}
+
+	for i, req := range staged {
+		if req == nil {
+			continue
+		}
+		result.Items[i].OK = true
+		result.Items[i].Status = req.Status
+		result.Succeeded++
+		if req.Status != previous[i] {
+			s.lifecycle.Notify(ctx, req, previous[i])
+		}
+	}
+	result.Applied = true
+	return result, nil
 }
 
-func (s *requestService) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.BuildRequest, int, error) {
-	filter := domain.RequestFilter{
-		UserID: &userID,
-		Limit:  limit,
-		Offset: offset,
+// bulkChange validates the operation's arguments and turns them into the
+// update applied to each request
+func (s *requestService) bulkChange(ctx context.Context, in *domain.BulkUpdateRequest) (*domain.UpdateBuildRequest, string, error) {
+	change := &domain.UpdateBuildRequest{}
+	switch in.Operation {
+	case domain.BulkSetStatus:
+		if in.Status == nil {
+			return nil, "", fmt.Errorf("%w: set_status needs a status", domain.ErrInvalidBulkOperation)
+		}
+		change.Status = in.Status
+	case domain.BulkCancel:
+		cancelled := domain.StatusCancelled
+		change.Status = &cancelled
+	case domain.BulkSetComplexity:
+		if in.Complexity == nil {
+			return nil, "", fmt.Errorf("%w: set_complexity needs a complexity", domain.ErrInvalidBulkOperation)
+		}
+		change.Complexity = in.Complexity
+	case domain.BulkAssignBuilder:
+		if in.BuilderID == nil {
+			return nil, "", fmt.Errorf("%w: assign_builder needs a builder_id", domain.ErrInvalidBulkOperation)
+		}
+		builder, err := s.userRepo.FindByID(ctx, *in.BuilderID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to find builder: %w", err)
+		}
+		if builder == nil {
+			return nil, "", domain.ErrUserNotFound
+		}
+		if builder.Role == domain.RoleStudent {
+			return nil, "", domain.ErrInvalidBuilder
+		}
+		change.BuilderID = in.BuilderID
+	case domain.BulkAddLabel:
+		label := domain.NormalizeLabel(in.Label)
+		if label == "" {
+			return nil, "", fmt.Errorf("%w: add_label needs a label", domain.ErrInvalidBulkOperation)
+		}
+		return change, label, nil
+	default:
+		return nil, "", fmt.Errorf("%w: unknown operation %q", domain.ErrInvalidBulkOperation, in.Operation)
 	}
-	return s.requestRepo.List(ctx, filter)
+	return change, "", nil
 }
 
-func (s *requestService) ListAll(ctx context.Context, filter domain.RequestFilter) ([]domain.BuildRequest, int, error) {
-	return s.requestRepo.List(ctx, filter)
+// bulkTargets resolves the requests a bulk operation applies to, either the
+// given IDs (deduplicated, in order) or the oldest matches of the filter
+func (s *requestService) bulkTargets(ctx context.Context, in *domain.BulkUpdateRequest) ([]uuid.UUID, error) {
+	if (len(in.IDs) > 0) == (in.Filter != nil) {
+		return nil, fmt.Errorf("%w: give either ids or a filter", domain.ErrInvalidBulkOperation)
+	}
+
+	if in.Filter == nil {
+		seen := make(map[uuid.UUID]bool, len(in.IDs))
+		ids := make([]uuid.UUID, 0, len(in.IDs))
+		for _, id := range in.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	filter, err := in.Filter.RequestFilter()
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = domain.MaxBulkItems + 1
+	requests, _, err := s.requestRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	if len(requests) > domain.MaxBulkItems {
+		return nil, fmt.Errorf("%w: the filter matches more than %d requests; narrow it down",
+			domain.ErrInvalidBulkOperation, domain.MaxBulkItems)
+	}
+	ids := make([]uuid.UUID, len(requests))
+	for i := range requests {
+		ids[i] = requests[i].ID
+	}
+	return ids, nil
+}
+
+// applyBulkChange applies one bulk operation to req in memory
+func (s *requestService) applyBulkChange(ctx context.Context, req *domain.BuildRequest, op domain.BulkOperation, change *domain.UpdateBuildRequest, label string) error {
+	// Closed requests stay closed under bulk and automation changes; reopening
+	// one is a deliberate single-request edit
+	if change.Status != nil && req.Status.IsClosed() && (*change.Status != req.Status || op == domain.BulkCancel) {
+		return fmt.Errorf("%w: it is %s", domain.ErrRequestClosed, req.Status)
+	}
+	if label != "" && !containsString(req.Labels, label) {
+		req.Labels = append(req.Labels, label)
+	}
+	return s.applyUpdate(ctx, req, change)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleVersion returns the rule set version a quote was priced with, or nil
+// for the built-in default price list
+func ruleVersion(quote *domain.PriceBreakdown) *int {
+	if quote.RuleVersion == 0 {
+		return nil
+	}
+	v := quote.RuleVersion
+	return &v
 }
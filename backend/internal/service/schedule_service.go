@@ -1,25 +1,42 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/makeitexist/backend/internal/domain"
+	"github.com/rs/zerolog/log"
 )
 
 type scheduleService struct {
-	scheduleRepo domain.ScheduleRepository
-	requestRepo  domain.BuildRequestRepository
+	tx               domain.Transactor
+	scheduleRepo     domain.ScheduleRepository
+	requestRepo      domain.BuildRequestRepository
+	standbyRepo      domain.StandbyRepository
+	availabilityRepo domain.AvailabilityRepository
+	notificationRepo domain.NotificationRepository
+	lifecycle        *domain.Lifecycle
 }
 
-// NewScheduleService creates a new schedule service
-func NewScheduleService(scheduleRepo domain.ScheduleRepository, requestRepo domain.BuildRequestRepository) domain.ScheduleService {
+// NewScheduleService creates a new schedule service. The lifecycle's guards
+// are consulted before a request is moved to scheduled, including when it is
+// promoted off standby, and its observers hear of every status change once it
+// commits. Bookings run in transactions with the slots locked. Slot hours
+// follow the builders' declared availability.
+func NewScheduleService(tx domain.Transactor, scheduleRepo domain.ScheduleRepository, requestRepo domain.BuildRequestRepository, standbyRepo domain.StandbyRepository, availabilityRepo domain.AvailabilityRepository, notificationRepo domain.NotificationRepository, lifecycle *domain.Lifecycle) domain.ScheduleService {
 	return &scheduleService{
-		scheduleRepo: scheduleRepo,
-		requestRepo:  requestRepo,
+		tx:               tx,
+		scheduleRepo:     scheduleRepo,
+		requestRepo:      requestRepo,
+		standbyRepo:      standbyRepo,
+		availabilityRepo: availabilityRepo,
+		notificationRepo: notificationRepo,
+		lifecycle:        lifecycle,
 	}
 }
 
@@ -61,36 +78,103 @@ func (s *scheduleService) GetScheduleForWeekend(ctx context.Context, date time.T
 }
 
 func (s *scheduleService) ScheduleRequest(ctx context.Context, requestID uuid.UUID, slotID uuid.UUID, hours int) (*domain.ScheduleEntry, error) {
-	// Verify slot exists and has capacity
-	slot, err := s.scheduleRepo.FindSlotByID(ctx, slotID)
+	return s.schedule(ctx, requestID, slotID, hours, nil)
+}
+
+func (s *scheduleService) Reschedule(ctx context.Context, entryID uuid.UUID, slotID uuid.UUID, hours int) (*domain.ScheduleEntry, error) {
+	entry, err := s.activeEntry(ctx, entryID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find slot: %w", err)
+		return nil, err
 	}
-	if slot == nil {
-		return nil, errors.New("slot not found")
+	if hours == 0 {
+		hours = entry.Hours
 	}
-	if slot.Status == domain.SlotFull {
-		return nil, errors.New("slot is full")
-	}
-	if slot.BookedHours+hours > slot.TotalHours {
-		return nil, errors.New("not enough hours available in this slot")
+	if slotID == entry.SlotID && hours == entry.Hours {
+		return entry, nil
 	}
+	return s.schedule(ctx, entry.RequestID, slotID, hours, &entry.ID)
+}
 
-	// Verify request exists
-	req, err := s.requestRepo.FindByID(ctx, requestID)
+// schedule books the request into the slot in one transaction, releasing its
+// previous bookings. With from set, that booking must still be active.
+func (s *scheduleService) schedule(ctx context.Context, requestID, slotID uuid.UUID, hours int, from *uuid.UUID) (*domain.ScheduleEntry, error) {
+	var entry *domain.ScheduleEntry
+	var freed []uuid.UUID
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		req, err := s.requestRepo.FindByID(ctx, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to find request: %w", err)
+		}
+		if req == nil {
+			return domain.ErrRequestNotFound
+		}
+		if !schedulable(req.Status) {
+			return domain.ErrRequestNotSchedulable
+		}
+
+		previous, slots, err := s.lockBookings(ctx, req.ID, slotID)
+		if err != nil {
+			return err
+		}
+		slot := slots[slotID]
+		if slot == nil {
+			return domain.ErrSlotNotFound
+		}
+		if from != nil && !containsEntry(previous, *from) {
+			return domain.ErrEntryReleased
+		}
+		if err := s.checkBuilder(ctx, req, slot, hours, previous); err != nil {
+			return err
+		}
+		// A booking being resized in the same slot gives its own hours back
+		room := *slot
+		for _, e := range previous {
+			if e.SlotID == slot.ID {
+				room.Release(e.Hours)
+			}
+		}
+		if !room.Fits(hours) {
+			return fmt.Errorf("not enough hours available in this slot: %w", domain.ErrSlotFull)
+		}
+		if err := s.lifecycle.Check(ctx, req, domain.StatusScheduled); err != nil {
+			return err
+		}
+
+		entry, freed, err = s.book(ctx, req, slots, slotID, hours, previous)
+		if err != nil {
+			return err
+		}
+		// Booked by hand: the request no longer needs its place on standby
+		if err := s.cancelWaiting(ctx, req.ID); err != nil {
+			return fmt.Errorf("failed to cancel standby: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to find request: %w", err)
+		return nil, err
 	}
-	if req == nil {
-		return nil, errors.New("request not found")
+
+	for _, id := range freed {
+		s.promote(ctx, id)
+	}
+	return entry, nil
+}
+
+// book takes the hours in a locked slot, releases the request's previous
+// bookings and marks it scheduled. It returns the slots that were freed.
+func (s *scheduleService) book(ctx context.Context, req *domain.BuildRequest, slots map[uuid.UUID]*domain.WeekendSlot, slotID uuid.UUID, hours int, previous []domain.ScheduleEntry) (*domain.ScheduleEntry, []uuid.UUID, error) {
+	slot := slots[slotID]
+	slot.Book(hours)
+	if err := s.scheduleRepo.UpdateSlot(ctx, slot); err != nil {
+		return nil, nil, fmt.Errorf("failed to update slot: %w", err)
 	}
 
-	// Create schedule entry
 	now := time.Now()
 	entry := &domain.ScheduleEntry{
 		ID:        uuid.New(),
-		RequestID: requestID,
-		SlotID:    slotID,
+		RequestID: req.ID,
+		SlotID:    slot.ID,
+		BuilderID: req.BuilderID,
 		Hours:     hours,
 		Status:    domain.StatusScheduled,
 		StartTime: slot.Date,
@@ -98,35 +182,321 @@ func (s *scheduleService) ScheduleRequest(ctx context.Context, requestID uuid.UU
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
-
 	if err := s.scheduleRepo.CreateEntry(ctx, entry); err != nil {
-		return nil, fmt.Errorf("failed to create entry: %w", err)
+		return nil, nil, fmt.Errorf("failed to create entry: %w", err)
 	}
 
-	// Update slot booked hours
-	slot.BookedHours += hours
-	slot.BookedProjects++
-	if slot.BookedProjects >= slot.MaxProjects || slot.BookedHours >= slot.TotalHours {
-		slot.Status = domain.SlotFull
-	} else {
-		slot.Status = domain.SlotBooked
-	}
-	if err := s.scheduleRepo.UpdateSlot(ctx, slot); err != nil {
-		return nil, fmt.Errorf("failed to update slot: %w", err)
+	freed, err := s.release(ctx, slots, previous)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Update request status to scheduled
+	from := req.Status
 	req.Status = domain.StatusScheduled
 	req.ScheduledWeekend = slot.Date
 	if err := s.requestRepo.Update(ctx, req); err != nil {
-		return nil, fmt.Errorf("failed to update request: %w", err)
+		return nil, nil, fmt.Errorf("failed to update request: %w", err)
+	}
+	s.notifyAfterCommit(ctx, req, from)
+	return entry, freed, nil
+}
+
+func (s *scheduleService) Unschedule(ctx context.Context, entryID uuid.UUID) (*domain.ScheduleEntry, error) {
+	entry, err := s.activeEntry(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var freed []uuid.UUID
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		req, err := s.requestRepo.FindByID(ctx, entry.RequestID)
+		if err != nil {
+			return fmt.Errorf("failed to find request: %w", err)
+		}
+		if req == nil {
+			return domain.ErrRequestNotFound
+		}
+		if !schedulable(req.Status) {
+			return domain.ErrRequestNotSchedulable
+		}
+
+		previous, slots, err := s.lockBookings(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if !containsEntry(previous, entry.ID) {
+			return domain.ErrEntryReleased
+		}
+		released := []domain.ScheduleEntry{*entry}
+		if freed, err = s.release(ctx, slots, released); err != nil {
+			return err
+		}
+		entry = &released[0]
+
+		// Back in the queue, unless the request still holds another booking
+		if len(previous) == 1 && req.Status == domain.StatusScheduled {
+			req.Status = domain.StatusQueued
+			req.ScheduledWeekend = time.Time{}
+			if err := s.requestRepo.Update(ctx, req); err != nil {
+				return fmt.Errorf("failed to update request: %w", err)
+			}
+			s.notifyAfterCommit(ctx, req, domain.StatusScheduled)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range freed {
+		s.promote(ctx, id)
+	}
+	return entry, nil
+}
+
+// notifyAfterCommit tells the lifecycle's observers that req moved from the
+// given status, once the transaction that saved it commits
+func (s *scheduleService) notifyAfterCommit(ctx context.Context, req *domain.BuildRequest, from domain.RequestStatus) {
+	if req.Status == from {
+		return
+	}
+	s.tx.AfterCommit(ctx, func(ctx context.Context) {
+		s.lifecycle.Notify(ctx, req, from)
+	})
+}
+
+// lockBookings locks the given slots and every slot the request is booked
+// into, then returns the request's active bookings as they stand under the
+// locks. Call it inside a transaction.
+func (s *scheduleService) lockBookings(ctx context.Context, requestID uuid.UUID, slotIDs ...uuid.UUID) ([]domain.ScheduleEntry, map[uuid.UUID]*domain.WeekendSlot, error) {
+	entries, err := s.activeEntries(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, e := range entries {
+		slotIDs = append(slotIDs, e.SlotID)
+	}
+	slots, err := lockSlots(ctx, s.scheduleRepo, slotIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A concurrent booking may have moved the request while we waited
+	entries, err = s.activeEntries(ctx, requestID)
+	if err != nil {
+		return nil, nil, err
 	}
+	return entries, slots, nil
+}
+
+// lockSlots locks the slots in ID order, so that bookings touching the same
+// slots cannot deadlock. Slots that do not exist are left out.
+func lockSlots(ctx context.Context, scheduleRepo domain.ScheduleRepository, ids []uuid.UUID) (map[uuid.UUID]*domain.WeekendSlot, error) {
+	ids = slices.Clone(ids)
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	ids = slices.Compact(ids)
+
+	slots := make(map[uuid.UUID]*domain.WeekendSlot, len(ids))
+	for _, id := range ids {
+		slot, err := scheduleRepo.LockSlot(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock slot: %w", err)
+		}
+		if slot != nil {
+			slots[id] = slot
+		}
+	}
+	return slots, nil
+}
 
+// activeEntry returns a booking that still holds capacity
+func (s *scheduleService) activeEntry(ctx context.Context, id uuid.UUID) (*domain.ScheduleEntry, error) {
+	entry, err := s.scheduleRepo.FindEntryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entry: %w", err)
+	}
+	if entry == nil {
+		return nil, domain.ErrEntryNotFound
+	}
+	if entry.Status != domain.StatusScheduled {
+		return nil, domain.ErrEntryReleased
+	}
 	return entry, nil
 }
 
+// activeEntries returns the request's bookings that still hold capacity
+func (s *scheduleService) activeEntries(ctx context.Context, requestID uuid.UUID) ([]domain.ScheduleEntry, error) {
+	entries, err := s.scheduleRepo.FindEntriesByRequest(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entries: %w", err)
+	}
+	active := entries[:0]
+	for _, e := range entries {
+		if e.Status == domain.StatusScheduled {
+			active = append(active, e)
+		}
+	}
+	return active, nil
+}
+
+// release cancels the entries and gives their hours back to their slots,
+// which must be locked. It returns the slots that were freed.
+func (s *scheduleService) release(ctx context.Context, slots map[uuid.UUID]*domain.WeekendSlot, entries []domain.ScheduleEntry) ([]uuid.UUID, error) {
+	var freed []uuid.UUID
+	for i := range entries {
+		e := &entries[i]
+		e.Status = domain.StatusCancelled
+		if err := s.scheduleRepo.UpdateEntry(ctx, e); err != nil {
+			return freed, fmt.Errorf("failed to update entry: %w", err)
+		}
+		slot := slots[e.SlotID]
+		if slot == nil {
+			continue
+		}
+		slot.Release(e.Hours)
+		if err := s.scheduleRepo.UpdateSlot(ctx, slot); err != nil {
+			return freed, fmt.Errorf("failed to update slot: %w", err)
+		}
+		freed = append(freed, slot.ID)
+	}
+	return freed, nil
+}
+
+// checkBuilder refuses a booking the request's builder has no hours left for
+// on the slot's day. Days nobody declared availability for are not checked.
+func (s *scheduleService) checkBuilder(ctx context.Context, req *domain.BuildRequest, slot *domain.WeekendSlot, hours int, previous []domain.ScheduleEntry) error {
+	if req.BuilderID == nil {
+		return nil
+	}
+	left, err := s.BuilderHoursLeft(ctx, slot)
+	if err != nil || left == nil {
+		return err
+	}
+	// Hours the builder already has for this request in the slot are moving
+	builder := *req.BuilderID
+	for _, e := range previous {
+		if e.SlotID == slot.ID && e.BuilderID != nil && *e.BuilderID == builder {
+			left[builder] += e.Hours
+		}
+	}
+	if left[builder] < hours {
+		return fmt.Errorf("%d hours needed, %d left on %s: %w", hours, max(left[builder], 0), slot.Date.Format("Monday 2 January"), domain.ErrBuilderUnavailable)
+	}
+	return nil
+}
+
+func (s *scheduleService) AssignBuilder(ctx context.Context, req *domain.BuildRequest, builderID *uuid.UUID) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		entries, slots, err := s.lockBookings(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		moved := *req
+		moved.BuilderID = builderID
+		for i := range entries {
+			e := &entries[i]
+			slot := slots[e.SlotID]
+			if slot == nil {
+				continue
+			}
+			if err := s.checkBuilder(ctx, &moved, slot, e.Hours, entries[i:i+1]); err != nil {
+				return err
+			}
+			e.BuilderID = builderID
+			if err := s.scheduleRepo.UpdateEntry(ctx, e); err != nil {
+				return fmt.Errorf("failed to update entry: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *scheduleService) BuilderHoursLeft(ctx context.Context, slot *domain.WeekendSlot) (domain.BuilderHours, error) {
+	left, err := s.availableOn(ctx, slot.Date)
+	if err != nil || len(left) == 0 {
+		return nil, err
+	}
+	entries, err := s.scheduleRepo.FindEntriesBySlot(ctx, slot.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entries: %w", err)
+	}
+	for _, e := range entries {
+		if e.Status == domain.StatusScheduled && e.BuilderID != nil {
+			left[*e.BuilderID] -= e.Hours
+		}
+	}
+	return left, nil
+}
+
+// availableOn returns each builder's declared hours on the date
+func (s *scheduleService) availableOn(ctx context.Context, date time.Time) (domain.BuilderHours, error) {
+	patterns, err := s.availabilityRepo.ListPatternsOn(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability: %w", err)
+	}
+	days, err := s.availabilityRepo.ListDays(ctx, nil, date, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability: %w", err)
+	}
+	return domain.ResolveAvailability(date, patterns, days), nil
+}
+
+func (s *scheduleService) RefreshCapacity(ctx context.Context, dates ...time.Time) error {
+	var slots []domain.WeekendSlot
+	if len(dates) == 0 {
+		upcoming, err := s.scheduleRepo.ListUpcomingSlots(ctx, 20)
+		if err != nil {
+			return fmt.Errorf("failed to list slots: %w", err)
+		}
+		slots = upcoming
+	}
+	for _, date := range dates {
+		slot, err := s.scheduleRepo.FindSlotByDate(ctx, date)
+		if err != nil {
+			return fmt.Errorf("failed to find slot: %w", err)
+		}
+		if slot != nil {
+			slots = append(slots, *slot)
+		}
+	}
+
+	for _, slot := range slots {
+		grew := false
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			locked, err := s.scheduleRepo.LockSlot(ctx, slot.ID)
+			if err != nil || locked == nil {
+				return err
+			}
+			available, err := s.availableOn(ctx, locked.Date)
+			if err != nil {
+				return err
+			}
+			hours := domain.SlotHours(available)
+			if hours == locked.TotalHours {
+				return nil
+			}
+			grew = hours > locked.TotalHours
+			locked.SetCapacity(hours)
+			return s.scheduleRepo.UpdateSlot(ctx, locked)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update slot capacity: %w", err)
+		}
+		// More hours may fit requests waiting on standby
+		if grew {
+			s.promote(ctx, slot.ID)
+		}
+	}
+	return nil
+}
+
+func containsEntry(entries []domain.ScheduleEntry, id uuid.UUID) bool {
+	return slices.ContainsFunc(entries, func(e domain.ScheduleEntry) bool { return e.ID == id })
+}
+
 func (s *scheduleService) AutoGenerateWeekendSlots(ctx context.Context, weeksAhead int) error {
 	now := time.Now()
+	var created []uuid.UUID
 
 	for i := 0; i < weeksAhead; i++ {
 		// Find next Saturday
@@ -141,37 +511,327 @@ func (s *scheduleService) AutoGenerateWeekendSlots(ctx context.Context, weeksAhe
 		// Create Saturday slot
 		satSlot, _ := s.scheduleRepo.FindSlotByDate(ctx, saturday)
 		if satSlot == nil {
+			available, err := s.availableOn(ctx, saturday)
+			if err != nil {
+				return err
+			}
 			satSlot = &domain.WeekendSlot{
 				ID:          uuid.New(),
 				Date:        saturday,
 				DayOfWeek:   "saturday",
-				TotalHours:  8,
-				MaxProjects: 5,
+				TotalHours:  domain.SlotHours(available),
+				MaxProjects: domain.DefaultSlotProjects,
 				Status:      domain.SlotAvailable,
+				Version:     1,
 				CreatedAt:   time.Now(),
 			}
 			if err := s.scheduleRepo.CreateSlot(ctx, satSlot); err != nil {
 				return fmt.Errorf("failed to create Saturday slot: %w", err)
 			}
+			created = append(created, satSlot.ID)
 		}
 
 		// Create Sunday slot
 		sunSlot, _ := s.scheduleRepo.FindSlotByDate(ctx, sunday)
 		if sunSlot == nil {
+			available, err := s.availableOn(ctx, sunday)
+			if err != nil {
+				return err
+			}
 			sunSlot = &domain.WeekendSlot{
 				ID:          uuid.New(),
 				Date:        sunday,
 				DayOfWeek:   "sunday",
-				TotalHours:  8,
-				MaxProjects: 5,
+				TotalHours:  domain.SlotHours(available),
+				MaxProjects: domain.DefaultSlotProjects,
 				Status:      domain.SlotAvailable,
+				Version:     1,
 				CreatedAt:   time.Now(),
 			}
 			if err := s.scheduleRepo.CreateSlot(ctx, sunSlot); err != nil {
 				return fmt.Errorf("failed to create Sunday slot: %w", err)
 			}
+			created = append(created, sunSlot.ID)
+		}
+	}
+
+	// New slots are the next available ones for requests on open standby
+	for _, id := range created {
+		s.promote(ctx, id)
+	}
+	return nil
+}
+
+// AfterTransition gives a request's slot back when it drops out of the
+// schedule, and takes it off standby once it is closed
+func (s *scheduleService) AfterTransition(ctx context.Context, req *domain.BuildRequest, from domain.RequestStatus) {
+	logger := log.With().Str("request_id", req.ID.String()).Logger()
+
+	if req.Status.IsClosed() {
+		if err := s.cancelWaiting(ctx, req.ID); err != nil {
+			logger.Error().Err(err).Msg("Failed to cancel standby")
+		}
+	}
+	switch req.Status {
+	case domain.StatusPending, domain.StatusQueued, domain.StatusCancelled, domain.StatusRejected:
+	default:
+		return
+	}
+
+	var freed []uuid.UUID
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		previous, slots, err := s.lockBookings(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		freed, err = s.release(ctx, slots, previous)
+		return err
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to release slot")
+		return
+	}
+	for _, id := range freed {
+		s.promote(ctx, id)
+	}
+}
+
+// promote books waiting requests into the slot, oldest first, while it has
+// room. Entries that do not fit or fail a guard keep waiting; entries whose
+// request can no longer be scheduled are cancelled.
+func (s *scheduleService) promote(ctx context.Context, slotID uuid.UUID) {
+	logger := log.With().Str("slot_id", slotID.String()).Logger()
+
+	slot, err := s.scheduleRepo.FindSlotByID(ctx, slotID)
+	if err != nil || slot == nil {
+		logger.Error().Err(err).Msg("Failed to load slot for standby")
+		return
+	}
+	if slot.Date.Before(today()) {
+		return
+	}
+	waiting, err := s.standbyRepo.ListWaiting(ctx, &slotID)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list standby")
+		return
+	}
+
+	for i := range waiting {
+		if err := s.tryPromote(ctx, &waiting[i], slotID); err != nil {
+			logger.Error().Err(err).Str("request_id", waiting[i].RequestID.String()).Msg("Failed to promote standby")
+			return
+		}
+	}
+}
+
+// tryPromote books a standby request into the slot if it still fits, marks
+// the entry promoted and lets the student and builder know. The entry is left
+// waiting when it does not fit, its builder is unavailable or a guard
+// refuses it.
+func (s *scheduleService) tryPromote(ctx context.Context, sb *domain.StandbyEntry, slotID uuid.UUID) error {
+	var req *domain.BuildRequest
+	var slot *domain.WeekendSlot
+	var freed []uuid.UUID
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if req, err = s.requestRepo.FindByID(ctx, sb.RequestID); err != nil {
+			return fmt.Errorf("failed to find request: %w", err)
+		}
+		if req == nil || !schedulable(req.Status) {
+			sb.Status = domain.StandbyCancelled
+			return s.standbyRepo.Update(ctx, sb)
+		}
+		if err := s.lifecycle.Check(ctx, req, domain.StatusScheduled); err != nil {
+			return nil
+		}
+
+		previous, slots, err := s.lockBookings(ctx, req.ID, slotID)
+		if err != nil {
+			return err
+		}
+		// Another promotion may have taken the room, or this entry, meanwhile
+		current, err := s.standbyRepo.FindByID(ctx, sb.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find standby: %w", err)
+		}
+		if slot = slots[slotID]; slot == nil || !slot.Fits(sb.Hours) || current == nil || current.Status != domain.StandbyWaiting {
+			slot = nil
+			return nil
+		}
+		// Nor can it go in while its builder is away
+		if err := s.checkBuilder(ctx, req, slot, sb.Hours, previous); err != nil {
+			slot = nil
+			if errors.Is(err, domain.ErrBuilderUnavailable) {
+				return nil
+			}
+			return err
+		}
+
+		if _, freed, err = s.book(ctx, req, slots, slotID, sb.Hours, previous); err != nil {
+			return err
 		}
+		now := time.Now()
+		sb.Status = domain.StandbyPromoted
+		sb.PromotedSlotID = &slot.ID
+		sb.PromotedAt = &now
+		if err := s.standbyRepo.Update(ctx, sb); err != nil {
+			return fmt.Errorf("failed to update standby: %w", err)
+		}
+		return nil
+	})
+	if err != nil || sb.Status != domain.StandbyPromoted {
+		return err
 	}
 
+	s.notifyPromoted(ctx, req, slot)
+	for _, id := range freed {
+		s.promote(ctx, id)
+	}
 	return nil
 }
+
+func (s *scheduleService) notifyPromoted(ctx context.Context, req *domain.BuildRequest, slot *domain.WeekendSlot) {
+	day := slot.Date.Format("Monday 2 January")
+	student := &domain.Notification{
+		ID:        uuid.New(),
+		UserID:    req.UserID,
+		RequestID: &req.ID,
+		Message:   fmt.Sprintf("A place opened up — %q is now scheduled for %s", req.Title, day),
+		CreatedAt: time.Now(),
+	}
+	if err := s.notificationRepo.Create(ctx, student); err != nil {
+		log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to notify student")
+	}
+
+	builder := &domain.Notification{
+		ID:        uuid.New(),
+		RequestID: &req.ID,
+		Message:   fmt.Sprintf("%q moved off standby and is scheduled for %s (%s)", req.Title, day, slot.DayOfWeek),
+		CreatedAt: time.Now(),
+	}
+	var err error
+	if req.BuilderID != nil {
+		builder.UserID = *req.BuilderID
+		err = s.notificationRepo.Create(ctx, builder)
+	} else {
+		_, err = s.notificationRepo.CreateForRole(ctx, domain.RoleBuilder, builder)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to notify builder")
+	}
+}
+
+func (s *scheduleService) Standby(ctx context.Context, in *domain.CreateStandbyRequest) (*domain.StandbyEntry, error) {
+	req, err := s.requestRepo.FindByID(ctx, in.RequestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find request: %w", err)
+	}
+	if req == nil {
+		return nil, domain.ErrRequestNotFound
+	}
+	if !schedulable(req.Status) {
+		return nil, domain.ErrRequestNotSchedulable
+	}
+	if err := s.lifecycle.Check(ctx, req, domain.StatusScheduled); err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.WeekendSlot
+	if in.SlotID != nil {
+		slot, err := s.scheduleRepo.FindSlotByID(ctx, *in.SlotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find slot: %w", err)
+		}
+		if slot == nil {
+			return nil, domain.ErrSlotNotFound
+		}
+		candidates = append(candidates, *slot)
+	} else {
+		candidates, err = s.scheduleRepo.ListUpcomingSlots(ctx, 20)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list slots: %w", err)
+		}
+	}
+
+	now := time.Now()
+	sb := &domain.StandbyEntry{
+		ID:        uuid.New(),
+		RequestID: req.ID,
+		SlotID:    in.SlotID,
+		Hours:     in.Hours,
+		Status:    domain.StandbyWaiting,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.standbyRepo.Create(ctx, sb); err != nil {
+		if errors.Is(err, domain.ErrAlreadyOnStandby) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create standby: %w", err)
+	}
+
+	// Nobody is ahead of a request that fits right away
+	for i := range candidates {
+		slot := &candidates[i]
+		if slot.Date.Before(today()) || !slot.Fits(sb.Hours) {
+			continue
+		}
+		if err := s.tryPromote(ctx, sb, slot.ID); err != nil {
+			log.Error().Err(err).Str("request_id", req.ID.String()).Msg("Failed to promote standby")
+			break
+		}
+		if sb.Status != domain.StandbyWaiting {
+			break
+		}
+	}
+	return sb, nil
+}
+
+func (s *scheduleService) ListStandby(ctx context.Context, slotID *uuid.UUID) ([]domain.StandbyEntry, error) {
+	entries, err := s.standbyRepo.ListWaiting(ctx, slotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list standby: %w", err)
+	}
+	if entries == nil {
+		entries = []domain.StandbyEntry{}
+	}
+	return entries, nil
+}
+
+func (s *scheduleService) CancelStandby(ctx context.Context, id uuid.UUID) (*domain.StandbyEntry, error) {
+	sb, err := s.standbyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find standby: %w", err)
+	}
+	if sb == nil {
+		return nil, domain.ErrStandbyNotFound
+	}
+	if sb.Status != domain.StandbyWaiting {
+		return nil, domain.ErrStandbyNotWaiting
+	}
+	sb.Status = domain.StandbyCancelled
+	if err := s.standbyRepo.Update(ctx, sb); err != nil {
+		return nil, fmt.Errorf("failed to update standby: %w", err)
+	}
+	return sb, nil
+}
+
+// cancelWaiting takes the request off standby, if it is waiting
+func (s *scheduleService) cancelWaiting(ctx context.Context, requestID uuid.UUID) error {
+	sb, err := s.standbyRepo.FindWaitingByRequest(ctx, requestID)
+	if err != nil || sb == nil {
+		return err
+	}
+	sb.Status = domain.StandbyCancelled
+	return s.standbyRepo.Update(ctx, sb)
+}
+
+// schedulable reports whether a request may be booked into a slot
+func schedulable(status domain.RequestStatus) bool {
+	return status == domain.StatusPending || status == domain.StatusQueued || status == domain.StatusScheduled
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
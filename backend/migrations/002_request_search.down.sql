@@ -0,0 +1,6 @@
+-- Rollback: Remove request search column and filter indexes
+DROP INDEX IF EXISTS idx_build_requests_created;
+DROP INDEX IF EXISTS idx_build_requests_complexity;
+DROP INDEX IF EXISTS idx_build_requests_hosting;
+DROP INDEX IF EXISTS idx_build_requests_search;
+ALTER TABLE build_requests DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,18 @@
+-- ============================================
+-- Make It Exist - Request search & filtering
+-- ============================================
+
+-- Weighted full-text document: title > description > tech requirements
+ALTER TABLE build_requests
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(tech_requirements, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_build_requests_search ON build_requests USING GIN (search_vector);
+
+-- Supporting indexes for the new admin filters and sort fields
+CREATE INDEX IF NOT EXISTS idx_build_requests_hosting ON build_requests(hosting_type);
+CREATE INDEX IF NOT EXISTS idx_build_requests_complexity ON build_requests(complexity);
+CREATE INDEX IF NOT EXISTS idx_build_requests_created ON build_requests(created_at);
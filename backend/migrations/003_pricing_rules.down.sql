@@ -0,0 +1,4 @@
+-- Rollback: Remove pricing rules
+ALTER TABLE build_requests DROP COLUMN IF EXISTS pricing_version;
+ALTER TABLE build_requests DROP COLUMN IF EXISTS add_ons;
+DROP TABLE IF EXISTS pricing_rule_sets;
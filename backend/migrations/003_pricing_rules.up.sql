@@ -0,0 +1,40 @@
+-- ============================================
+-- Make It Exist - Versioned pricing rules
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS pricing_rule_sets (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version         SERIAL UNIQUE,
+    name            VARCHAR(255) NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'draft'
+                    CHECK (status IN ('draft', 'active', 'retired')),
+    rules           JSONB NOT NULL,
+    created_by      UUID REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    activated_at    TIMESTAMPTZ
+);
+
+-- At most one rule set is active at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pricing_rule_sets_active
+    ON pricing_rule_sets(status) WHERE status = 'active';
+
+-- Seed: launch price list (₹2,999 / ₹5,999 / ₹11,999, 1.5× mobile, ₹1,999 whitelabel)
+INSERT INTO pricing_rule_sets (name, status, rules, activated_at)
+SELECT 'Launch pricing', 'active', '{
+    "currency": "INR",
+    "free_websites": true,
+    "base_prices": {"basic": 2999, "standard": 5999, "advanced": 11999},
+    "type_multipliers": {"website": 1, "mobile_app": 1.5, "both": 1.5},
+    "hosting_surcharges": {"whitelabel": 1999},
+    "add_ons": []
+}'::jsonb, NOW()
+WHERE NOT EXISTS (SELECT 1 FROM pricing_rule_sets);
+
+-- Requests remember their add-ons and the rule version their estimate used
+ALTER TABLE build_requests ADD COLUMN IF NOT EXISTS add_ons TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE build_requests ADD COLUMN IF NOT EXISTS pricing_version INT REFERENCES pricing_rule_sets(version);
+
+-- Existing estimates were computed with the launch price list
+UPDATE build_requests
+SET pricing_version = (SELECT version FROM pricing_rule_sets WHERE status = 'active')
+WHERE pricing_version IS NULL;
@@ -0,0 +1,2 @@
+-- Rollback: Remove quotes
+DROP TABLE IF EXISTS quotes;
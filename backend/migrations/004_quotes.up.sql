@@ -0,0 +1,29 @@
+-- ============================================
+-- Make It Exist - Quotes for paid requests
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS quotes (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    revision        INT NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'draft'
+                    CHECK (status IN ('draft', 'sent', 'accepted', 'declined', 'superseded', 'expired')),
+    currency        VARCHAR(3) NOT NULL DEFAULT 'INR',
+    lines           JSONB NOT NULL DEFAULT '[]',
+    total           DECIMAL(10,2) NOT NULL,
+    valid_until     TIMESTAMPTZ NOT NULL,
+    notes           TEXT,
+    pricing_version INT REFERENCES pricing_rule_sets(version),
+    created_by      UUID NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ,
+    responded_at    TIMESTAMPTZ,
+    response_note   TEXT,
+    UNIQUE (request_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_request ON quotes(request_id);
+
+-- A request can have at most one accepted quote
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quotes_accepted
+    ON quotes(request_id) WHERE status = 'accepted';
@@ -0,0 +1,3 @@
+-- Rollback: Remove payments ledger
+DROP TABLE IF EXISTS payment_ledger;
+DROP TABLE IF EXISTS payment_intents;
@@ -0,0 +1,43 @@
+-- ============================================
+-- Make It Exist - Payments ledger
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS payment_intents (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id          UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    quote_id            UUID NOT NULL REFERENCES quotes(id),
+    provider            VARCHAR(30) NOT NULL,
+    provider_order_id   VARCHAR(255) NOT NULL,
+    amount              DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    currency            VARCHAR(3) NOT NULL DEFAULT 'INR',
+    amount_paid         DECIMAL(10,2) NOT NULL DEFAULT 0,
+    amount_refunded     DECIMAL(10,2) NOT NULL DEFAULT 0,
+    status              VARCHAR(20) NOT NULL DEFAULT 'created'
+                        CHECK (status IN ('created', 'partially_paid', 'paid', 'refunded', 'cancelled')),
+    created_by          UUID NOT NULL REFERENCES users(id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_intents_request ON payment_intents(request_id);
+
+-- Append-only: rows are never updated or deleted
+CREATE TABLE IF NOT EXISTS payment_ledger (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    intent_id       UUID NOT NULL REFERENCES payment_intents(id),
+    kind            VARCHAR(10) NOT NULL CHECK (kind IN ('payment', 'refund')),
+    amount          DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    currency        VARCHAR(3) NOT NULL DEFAULT 'INR',
+    provider        VARCHAR(30) NOT NULL,
+    provider_ref    VARCHAR(255) NOT NULL,
+    payment_ref     VARCHAR(255),
+    note            TEXT,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_ledger_request ON payment_ledger(request_id);
+CREATE INDEX IF NOT EXISTS idx_payment_ledger_intent ON payment_ledger(intent_id);
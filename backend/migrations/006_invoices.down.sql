@@ -0,0 +1,4 @@
+-- Rollback: Remove invoices and request artifacts
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_sequences;
+DROP TABLE IF EXISTS request_artifacts;
@@ -0,0 +1,54 @@
+-- ============================================
+-- Make It Exist - Invoices, receipts and request artifacts
+-- ============================================
+
+-- Generated files attached to a request
+CREATE TABLE IF NOT EXISTS request_artifacts (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    kind            VARCHAR(30) NOT NULL,
+    filename        VARCHAR(255) NOT NULL,
+    content_type    VARCHAR(100) NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    sha256          VARCHAR(64) NOT NULL,
+    content         BYTEA NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_request_artifacts_request ON request_artifacts(request_id);
+
+-- One counter row per series and financial year. Numbers are taken inside the
+-- issuing transaction, so a rolled-back issue never leaves a gap.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    kind            VARCHAR(10) NOT NULL,
+    financial_year  VARCHAR(7) NOT NULL,
+    last_number     INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (kind, financial_year)
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id),
+    kind            VARCHAR(10) NOT NULL CHECK (kind IN ('invoice', 'receipt')),
+    number          VARCHAR(50) NOT NULL UNIQUE,
+    financial_year  VARCHAR(7) NOT NULL,
+    sequence        INT NOT NULL,
+    source_ref      VARCHAR(255) NOT NULL,
+    issued_at       TIMESTAMPTZ NOT NULL,
+    currency        VARCHAR(3) NOT NULL DEFAULT 'INR',
+    buyer_name      VARCHAR(255) NOT NULL,
+    buyer_email     VARCHAR(255) NOT NULL,
+    place_of_supply VARCHAR(100) NOT NULL,
+    lines           JSONB NOT NULL,
+    taxable_amount  DECIMAL(10,2) NOT NULL,
+    cgst            DECIMAL(10,2) NOT NULL DEFAULT 0,
+    sgst            DECIMAL(10,2) NOT NULL DEFAULT 0,
+    igst            DECIMAL(10,2) NOT NULL DEFAULT 0,
+    total           DECIMAL(10,2) NOT NULL,
+    artifact_id     UUID NOT NULL REFERENCES request_artifacts(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (kind, financial_year, sequence),
+    UNIQUE (kind, source_ref)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_request ON invoices(request_id);
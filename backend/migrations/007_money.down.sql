@@ -0,0 +1,2 @@
+-- Rollback: Remove request currency (JSON amounts stay normalised)
+ALTER TABLE build_requests DROP COLUMN IF EXISTS currency;
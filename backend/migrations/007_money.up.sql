@@ -0,0 +1,46 @@
+-- ============================================
+-- Make It Exist - Exact money amounts
+-- ============================================
+-- Amount columns are already DECIMAL(10,2) and keep their values. Requests
+-- gain the currency their estimate is in, and amounts stored inside JSON
+-- (pricing rules, quote lines) are normalised to two decimal places, which is
+-- all the API now accepts.
+
+ALTER TABLE build_requests ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'INR';
+
+UPDATE build_requests br
+SET currency = prs.rules->>'currency'
+FROM pricing_rule_sets prs
+WHERE br.pricing_version = prs.version
+  AND COALESCE(prs.rules->>'currency', '') <> ''
+  AND br.currency <> prs.rules->>'currency';
+
+UPDATE pricing_rule_sets
+SET rules = jsonb_set(rules, '{base_prices}', (
+    SELECT COALESCE(jsonb_object_agg(k, ROUND(v::text::numeric, 2)), '{}'::jsonb)
+    FROM jsonb_each(rules->'base_prices') AS e(k, v)))
+WHERE jsonb_typeof(rules->'base_prices') = 'object';
+
+UPDATE pricing_rule_sets
+SET rules = jsonb_set(rules, '{hosting_surcharges}', (
+    SELECT COALESCE(jsonb_object_agg(k, ROUND(v::text::numeric, 2)), '{}'::jsonb)
+    FROM jsonb_each(rules->'hosting_surcharges') AS e(k, v)))
+WHERE jsonb_typeof(rules->'hosting_surcharges') = 'object';
+
+UPDATE pricing_rule_sets
+SET rules = jsonb_set(rules, '{add_ons}', (
+    SELECT COALESCE(jsonb_agg(
+        CASE WHEN a ? 'amount'
+             THEN jsonb_set(a, '{amount}', to_jsonb(ROUND((a->>'amount')::numeric, 2)))
+             ELSE a END ORDER BY i), '[]'::jsonb)
+    FROM jsonb_array_elements(rules->'add_ons') WITH ORDINALITY AS t(a, i)))
+WHERE jsonb_typeof(rules->'add_ons') = 'array';
+
+UPDATE quotes
+SET lines = (
+    SELECT COALESCE(jsonb_agg(
+        CASE WHEN l ? 'amount'
+             THEN jsonb_set(l, '{amount}', to_jsonb(ROUND((l->>'amount')::numeric, 2)))
+             ELSE l END ORDER BY i), '[]'::jsonb)
+    FROM jsonb_array_elements(lines) WITH ORDINALITY AS t(l, i))
+WHERE jsonb_typeof(lines) = 'array';
@@ -0,0 +1,3 @@
+-- Rollback: Remove promotions
+DROP TABLE IF EXISTS request_discounts;
+DROP TABLE IF EXISTS discount_codes;
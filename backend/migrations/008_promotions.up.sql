@@ -0,0 +1,50 @@
+-- ============================================
+-- Make It Exist - Discount codes, scholarships and fee waivers
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS discount_codes (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code                VARCHAR(40) NOT NULL UNIQUE, -- stored upper-case
+    description         TEXT,
+    kind                VARCHAR(10) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    percent             NUMERIC(5,2) NOT NULL DEFAULT 0,
+    amount              DECIMAL(10,2) NOT NULL DEFAULT 0,
+    currency            VARCHAR(3) NOT NULL DEFAULT 'INR',
+    max_uses            INT CHECK (max_uses > 0),
+    per_student_limit   INT CHECK (per_student_limit > 0),
+    used_count          INT NOT NULL DEFAULT 0,
+    request_types       TEXT[] NOT NULL DEFAULT '{}',
+    starts_at           TIMESTAMPTZ,
+    expires_at          TIMESTAMPTZ,
+    active              BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by          UUID NOT NULL REFERENCES users(id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'percent' AND percent > 0 AND percent <= 100) OR (kind = 'fixed' AND amount > 0))
+);
+
+-- Redeemed codes and staff waivers. Removed discounts are kept for the audit
+-- trail and excluded everywhere else.
+CREATE TABLE IF NOT EXISTS request_discounts (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id),
+    source          VARCHAR(10) NOT NULL CHECK (source IN ('code', 'waiver')),
+    code_id         UUID REFERENCES discount_codes(id),
+    code            VARCHAR(40),
+    label           VARCHAR(255) NOT NULL,
+    kind            VARCHAR(10) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    percent         NUMERIC(5,2) NOT NULL DEFAULT 0,
+    amount          DECIMAL(10,2) NOT NULL DEFAULT 0,
+    applied_amount  DECIMAL(10,2) NOT NULL DEFAULT 0,
+    currency        VARCHAR(3) NOT NULL DEFAULT 'INR',
+    reason          TEXT,
+    granted_by      UUID REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    removed_at      TIMESTAMPTZ
+);
+
+-- One code per request; waivers can stack
+CREATE UNIQUE INDEX IF NOT EXISTS idx_request_discounts_one_code
+    ON request_discounts(request_id) WHERE source = 'code' AND removed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_request_discounts_request ON request_discounts(request_id);
+CREATE INDEX IF NOT EXISTS idx_request_discounts_code_user ON request_discounts(code_id, user_id);
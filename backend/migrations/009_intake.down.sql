@@ -0,0 +1,3 @@
+-- Rollback: Remove request quotas
+DROP INDEX IF EXISTS idx_build_requests_user_created;
+DROP TABLE IF EXISTS quota_overrides;
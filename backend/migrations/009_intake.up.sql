@@ -0,0 +1,15 @@
+-- ============================================
+-- Make It Exist - Request quotas and duplicate detection
+-- ============================================
+
+-- Students exempted from the intake limits by an admin
+CREATE TABLE IF NOT EXISTS quota_overrides (
+    user_id     UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    reason      TEXT NOT NULL,
+    granted_by  UUID NOT NULL REFERENCES users(id),
+    expires_at  TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Quota counts and duplicate checks look at a student's recent requests
+CREATE INDEX IF NOT EXISTS idx_build_requests_user_created ON build_requests(user_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Rollback: Remove deliverables
+DROP TABLE IF EXISTS deliverables;
@@ -0,0 +1,24 @@
+-- ============================================
+-- Make It Exist - Deliverables
+-- ============================================
+
+-- Separately shipped parts of a request (website, Android app, iOS app, API).
+-- Once a request has deliverables its build status is derived from them.
+CREATE TABLE IF NOT EXISTS deliverables (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    kind            VARCHAR(10) NOT NULL CHECK (kind IN ('web', 'android', 'ios', 'api')),
+    label           VARCHAR(255) NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'planned'
+                    CHECK (status IN ('planned', 'building', 'review', 'deploying', 'delivered', 'dropped')),
+    delivery_url    TEXT,
+    repo_url        TEXT,
+    store_url       TEXT,
+    artifacts       JSONB NOT NULL DEFAULT '[]',
+    checklist       JSONB NOT NULL DEFAULT '[]',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_deliverables_request ON deliverables(request_id, created_at);
@@ -0,0 +1,3 @@
+-- Rollback: Remove delivery acceptance and ratings
+DROP TABLE IF EXISTS ratings;
+DROP TABLE IF EXISTS delivery_reviews;
@@ -0,0 +1,30 @@
+-- ============================================
+-- Make It Exist - Delivery acceptance, revisions and ratings
+-- ============================================
+
+-- Student sign-offs and revision requests, one row per review
+CREATE TABLE IF NOT EXISTS delivery_reviews (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    round           INT NOT NULL CHECK (round > 0),
+    outcome         VARCHAR(20) NOT NULL CHECK (outcome IN ('accepted', 'revision_requested')),
+    notes           TEXT,
+    reviewed_by     UUID NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (request_id, round)
+);
+
+-- One rating per request, editable by the student
+CREATE TABLE IF NOT EXISTS ratings (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL UNIQUE REFERENCES build_requests(id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users(id),
+    builder_id      UUID REFERENCES users(id),
+    builder_score   SMALLINT NOT NULL CHECK (builder_score BETWEEN 1 AND 5),
+    request_score   SMALLINT NOT NULL CHECK (request_score BETWEEN 1 AND 5),
+    feedback        TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ratings_builder ON ratings(builder_id);
@@ -0,0 +1,3 @@
+-- Rollback: Remove request labels
+DROP INDEX IF EXISTS idx_build_requests_labels;
+ALTER TABLE build_requests DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,9 @@
+-- ============================================
+-- Make It Exist - Request labels
+-- ============================================
+
+-- Free-form tags staff attach to requests (e.g. "hackathon", "club-fest")
+ALTER TABLE build_requests
+    ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_build_requests_labels ON build_requests USING GIN (labels);
@@ -0,0 +1,7 @@
+-- Rollback: Remove legacy CSV imports
+DROP INDEX IF EXISTS idx_users_email_lower;
+DROP INDEX IF EXISTS idx_build_requests_external_key;
+ALTER TABLE build_requests
+    DROP COLUMN IF EXISTS import_batch_id,
+    DROP COLUMN IF EXISTS external_key;
+DROP TABLE IF EXISTS import_batches;
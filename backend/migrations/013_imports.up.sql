@@ -0,0 +1,30 @@
+-- ============================================
+-- Make It Exist - Legacy CSV imports
+-- ============================================
+
+-- Uploaded CSVs are kept until committed so they can be re-mapped and
+-- re-validated without uploading again
+CREATE TABLE IF NOT EXISTS import_batches (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    filename        VARCHAR(255) NOT NULL,
+    content         TEXT NOT NULL,
+    headers         JSONB NOT NULL DEFAULT '[]',
+    mapping         JSONB NOT NULL DEFAULT '{}',
+    status          VARCHAR(20) NOT NULL DEFAULT 'uploaded'
+                    CHECK (status IN ('uploaded', 'validated', 'invalid', 'committed')),
+    row_count       INT NOT NULL DEFAULT 0,
+    report          JSONB,
+    created_by      UUID NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    committed_at    TIMESTAMPTZ
+);
+
+-- Row key from the source sheet; makes re-running an import a no-op
+ALTER TABLE build_requests
+    ADD COLUMN IF NOT EXISTS external_key VARCHAR(200),
+    ADD COLUMN IF NOT EXISTS import_batch_id UUID REFERENCES import_batches(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_build_requests_external_key
+    ON build_requests(external_key) WHERE external_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
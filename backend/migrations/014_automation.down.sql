@@ -0,0 +1,6 @@
+-- Rollback: Remove automation rules and notifications
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS automation_hits;
+DROP TABLE IF EXISTS automation_rules;
+DROP INDEX IF EXISTS idx_build_requests_status_changed;
+ALTER TABLE build_requests DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,75 @@
+-- ============================================
+-- Make It Exist - Automation rules and notifications
+-- ============================================
+
+-- When a request entered its current status, so rules can find idle ones
+ALTER TABLE build_requests
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+UPDATE build_requests
+    SET status_changed_at = COALESCE(completed_at, updated_at)
+    WHERE status_changed_at IS NULL;
+ALTER TABLE build_requests
+    ALTER COLUMN status_changed_at SET DEFAULT NOW(),
+    ALTER COLUMN status_changed_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_build_requests_status_changed ON build_requests(status, status_changed_at);
+
+-- "When status = X for N days [and conditions], then act"
+CREATE TABLE IF NOT EXISTS automation_rules (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name            VARCHAR(120) NOT NULL,
+    description     TEXT,
+    enabled         BOOLEAN NOT NULL DEFAULT FALSE,
+    status          VARCHAR(30) NOT NULL,
+    days            INT NOT NULL CHECK (days > 0),
+    conditions      JSONB NOT NULL DEFAULT '{}',
+    action          VARCHAR(20) NOT NULL CHECK (action IN ('transition', 'notify', 'add_label')),
+    target_status   VARCHAR(30),
+    label           VARCHAR(40),
+    recipient       VARCHAR(20) CHECK (recipient IN ('student', 'builder', 'admins')),
+    message         TEXT,
+    last_run_at     TIMESTAMPTZ,
+    created_by      UUID REFERENCES users(id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A rule acts on a request once per stay in a status: the hit is keyed by
+-- the time the request entered it
+CREATE TABLE IF NOT EXISTS automation_hits (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id             UUID NOT NULL REFERENCES automation_rules(id) ON DELETE CASCADE,
+    request_id          UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    status_changed_at   TIMESTAMPTZ NOT NULL,
+    ok                  BOOLEAN NOT NULL DEFAULT FALSE,
+    error               TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (rule_id, request_id, status_changed_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_automation_hits_rule ON automation_hits(rule_id, created_at DESC);
+
+-- In-app notifications
+CREATE TABLE IF NOT EXISTS notifications (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    request_id      UUID REFERENCES build_requests(id) ON DELETE CASCADE,
+    message         TEXT NOT NULL,
+    read_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at DESC);
+
+-- Typical rules, disabled until an admin reviews them
+INSERT INTO automation_rules (name, description, status, days, conditions, action, target_status, recipient, message)
+SELECT * FROM (VALUES
+    ('Cancel abandoned requests', 'Pending requests nobody has picked up in a month',
+     'pending', 30, '{}'::jsonb, 'transition', 'cancelled', NULL, NULL),
+    ('Queue free websites', 'Free website requests need no quote, so queue them after a day',
+     'pending', 1, '{"request_type": "website", "is_free": true}'::jsonb, 'transition', 'queued', NULL, NULL),
+    ('Remind students to review', 'Deliveries waiting on the student for 10 days',
+     'review', 10, '{}'::jsonb, 'notify', NULL, 'student',
+     '"{title}" has been waiting for your review for {days} days. Accept it or ask for a revision.')
+) AS seed(name, description, status, days, conditions, action, target_status, recipient, message)
+WHERE NOT EXISTS (SELECT 1 FROM automation_rules);
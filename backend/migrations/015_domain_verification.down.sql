@@ -0,0 +1,7 @@
+-- Rollback: Remove whitelabel domain verification
+ALTER TABLE build_requests
+    DROP COLUMN IF EXISTS domain_verified_at,
+    DROP COLUMN IF EXISTS domain_checked_at,
+    DROP COLUMN IF EXISTS domain_check_error,
+    DROP COLUMN IF EXISTS domain_token,
+    DROP COLUMN IF EXISTS domain_status;
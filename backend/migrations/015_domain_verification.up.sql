@@ -0,0 +1,12 @@
+-- ============================================
+-- Make It Exist - Whitelabel domain verification
+-- ============================================
+
+-- DNS TXT challenge proving the student controls the whitelabel domain
+ALTER TABLE build_requests
+    ADD COLUMN IF NOT EXISTS domain_status VARCHAR(20)
+        CHECK (domain_status IN ('pending', 'verified', 'failed')),
+    ADD COLUMN IF NOT EXISTS domain_token VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS domain_check_error TEXT,
+    ADD COLUMN IF NOT EXISTS domain_checked_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS domain_verified_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Rollback: Remove ACME certificates
+DROP TABLE IF EXISTS certificates;
+DROP TABLE IF EXISTS acme_challenges;
+DROP TABLE IF EXISTS acme_accounts;
@@ -0,0 +1,40 @@
+-- ============================================
+-- Make It Exist - ACME certificates for whitelabel domains
+-- ============================================
+
+-- One ACME account per directory (Let's Encrypt, a local Pebble, ...)
+CREATE TABLE IF NOT EXISTS acme_accounts (
+    directory_url   TEXT PRIMARY KEY,
+    key_pem         TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Pending HTTP-01 challenges, answered at /.well-known/acme-challenge/:token
+-- by whichever instance the ACME server reaches
+CREATE TABLE IF NOT EXISTS acme_challenges (
+    token           VARCHAR(255) PRIMARY KEY,
+    key_auth        TEXT NOT NULL,
+    domain          VARCHAR(253) NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The certificate of each whitelabel request; renewed in place
+CREATE TABLE IF NOT EXISTS certificates (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id      UUID NOT NULL UNIQUE REFERENCES build_requests(id) ON DELETE CASCADE,
+    domain          VARCHAR(253) NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending'
+                    CHECK (status IN ('pending', 'issued', 'failed')),
+    cert_pem        TEXT,
+    key_pem         TEXT,
+    issuer          VARCHAR(255),
+    not_before      TIMESTAMPTZ,
+    not_after       TIMESTAMPTZ,
+    renew_after     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_certificates_renew_after ON certificates(renew_after);
@@ -0,0 +1,3 @@
+-- Rollback: Remove optimistic concurrency versions
+ALTER TABLE weekend_slots DROP COLUMN IF EXISTS version;
+ALTER TABLE build_requests DROP COLUMN IF EXISTS version;
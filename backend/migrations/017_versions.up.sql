@@ -0,0 +1,8 @@
+-- ============================================
+-- Make It Exist - Optimistic concurrency versions
+-- ============================================
+
+-- Bumped on every write; clients send it back in If-Match so that two admins
+-- editing the same record cannot silently overwrite each other
+ALTER TABLE build_requests ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE weekend_slots ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Rollback: Remove idempotency keys
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,22 @@
+-- ============================================
+-- Make It Exist - Idempotency keys
+-- ============================================
+
+-- Responses to POSTs sent with an Idempotency-Key header, replayed when a
+-- client retries with the same key. status_code is NULL while the first
+-- attempt is still running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id         UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key             VARCHAR(255) NOT NULL,
+    method          VARCHAR(10) NOT NULL,
+    path            TEXT NOT NULL,
+    fingerprint     CHAR(64) NOT NULL, -- SHA-256 of method, path and body
+    status_code     INT,
+    content_type    VARCHAR(255),
+    response_body   BYTEA,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1,3 @@
+-- Rollback: Remove build queue ordering
+DROP INDEX IF EXISTS idx_build_requests_queued;
+ALTER TABLE users DROP COLUMN IF EXISTS graduation_year;
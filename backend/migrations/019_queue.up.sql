@@ -0,0 +1,8 @@
+-- ============================================
+-- Make It Exist - Build queue ordering
+-- ============================================
+
+-- Final-year students can be moved up the queue (QUEUE_FINAL_YEAR_BOOST)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS graduation_year INT;
+
+CREATE INDEX IF NOT EXISTS idx_build_requests_queued ON build_requests(status_changed_at) WHERE status = 'queued';
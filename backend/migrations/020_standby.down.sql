@@ -0,0 +1,2 @@
+-- Rollback: Remove slot waitlists
+DROP TABLE IF EXISTS standby_entries;
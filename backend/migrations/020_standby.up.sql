@@ -0,0 +1,22 @@
+-- ============================================
+-- Make It Exist - Slot waitlists
+-- ============================================
+
+-- Requests waiting for room in a full slot (slot_id) or in whichever slot
+-- frees up first (slot_id NULL); promoted oldest first
+CREATE TABLE IF NOT EXISTS standby_entries (
+    id               UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id       UUID NOT NULL REFERENCES build_requests(id) ON DELETE CASCADE,
+    slot_id          UUID REFERENCES weekend_slots(id) ON DELETE CASCADE,
+    estimated_hours  INT NOT NULL,
+    status           VARCHAR(20) NOT NULL DEFAULT 'waiting'
+                     CHECK (status IN ('waiting', 'promoted', 'cancelled')),
+    promoted_slot_id UUID REFERENCES weekend_slots(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    promoted_at      TIMESTAMPTZ
+);
+
+-- A request waits in one line at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_standby_entries_waiting_request ON standby_entries(request_id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_standby_entries_waiting ON standby_entries(created_at) WHERE status = 'waiting';
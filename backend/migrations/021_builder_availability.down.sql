@@ -0,0 +1,3 @@
+-- Rollback: Remove builder availability
+DROP TABLE IF EXISTS builder_availability_days;
+DROP TABLE IF EXISTS builder_availability_patterns;
@@ -0,0 +1,34 @@
+-- ============================================
+-- Make It Exist - Builder availability
+-- ============================================
+
+-- Weekly availability: every Saturday or Sunday from valid_from, until
+-- valid_until if set. from_time/until_time record a partial day.
+CREATE TABLE IF NOT EXISTS builder_availability_patterns (
+    id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    builder_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day_of_week VARCHAR(10) NOT NULL CHECK (day_of_week IN ('saturday', 'sunday')),
+    hours       INT NOT NULL CHECK (hours BETWEEN 1 AND 24),
+    from_time   VARCHAR(5),
+    until_time  VARCHAR(5),
+    valid_from  DATE NOT NULL,
+    valid_until DATE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_builder_availability_patterns_builder ON builder_availability_patterns(builder_id);
+
+-- One day's availability; overrides the weekly pattern. Zero hours marks the
+-- builder away.
+CREATE TABLE IF NOT EXISTS builder_availability_days (
+    builder_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date       DATE NOT NULL,
+    hours      INT NOT NULL CHECK (hours BETWEEN 0 AND 24),
+    from_time  VARCHAR(5),
+    until_time VARCHAR(5),
+    note       TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (builder_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_builder_availability_days_date ON builder_availability_days(date);
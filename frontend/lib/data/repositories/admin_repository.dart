@@ -9,11 +9,21 @@ class AdminRepository {
 
   AdminRepository({required this.apiClient});
 
+  /// Fetches every user, following next_cursor page by page
   Future<List<UserModel>> listUsers() async {
     try {
-      final response = await apiClient.get(ApiEndpoints.adminUsers);
-      final List data = response.data['data'] ?? [];
-      return data.map((json) => UserModel.fromJson(json)).toList();
+      final users = <UserModel>[];
+      String? cursor;
+      do {
+        final response = await apiClient.get(
+          ApiEndpoints.adminUsers,
+          queryParameters: {'limit': 100, if (cursor != null) 'cursor': cursor},
+        );
+        final List data = response.data['data'] ?? [];
+        users.addAll(data.map((json) => UserModel.fromJson(json)));
+        cursor = response.data['next_cursor'] as String?;
+      } while (cursor != null);
+      return users;
     } on DioException catch (e) {
       throw ApiException.fromDioError(e);
     }
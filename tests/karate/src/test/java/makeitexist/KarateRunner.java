@@ -29,6 +29,11 @@ class KarateRunner {
         return Karate.run("classpath:makeitexist/schedule").relativeTo(getClass());
     }
 
+    @Karate.Test
+    Karate testPricing() {
+        return Karate.run("classpath:makeitexist/pricing").relativeTo(getClass());
+    }
+
     @Karate.Test
     Karate testAdmin() {
         return Karate.run("classpath:makeitexist/admin").relativeTo(getClass());
@@ -32,6 +32,7 @@ Feature: Admin API
     When method GET
     Then status 200
     And match response.data == '#notnull'
+    And match response.data.build_hours == { total: '#number', booked: '#number' }
 
   # ─── Admin: List requests ───────────────────────────────────────────
 
@@ -57,6 +58,72 @@ Feature: Admin API
       | rejected    |
       | building    |
 
+  # ─── Data-driven: Search and richer filters ─────────────────────────
+
+  Scenario Outline: Every row matches the filter — <case>
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params <params>
+    When method GET
+    Then status 200
+    And match response.total == '#number'
+    * def mismatched = karate.filter(response.data, function(r){ return !(<check>) })
+    And match mismatched == []
+
+    Examples:
+      | case               | params                                                       | check                                                                                              |
+      | multiple statuses  | { status: 'pending,queued,scheduled' }                       | ['pending', 'queued', 'scheduled'].indexOf(r.status) >= 0                                          |
+      | hosting + free     | { hosting_type: 'vercel', is_free: 'true' }                  | r.hosting_type == 'vercel' && r.is_free                                                            |
+      | complexity         | { complexity: 'standard' }                                   | r.complexity == 'standard'                                                                         |
+      | created date range | { created_from: '2026-01-01', created_to: '2026-12-31' }      | r.created_at.substring(0, 10) >= '2026-01-01' && r.created_at.substring(0, 10) <= '2026-12-31'     |
+      | scheduled range    | { scheduled_from: '2026-03-01', scheduled_to: '2026-03-31' } | r.scheduled_weekend.substring(0, 10) >= '2026-03-01' && r.scheduled_weekend.substring(0, 10) <= '2026-03-31' |
+
+  Scenario: Free-text search finds matching requests, sorted by title
+    * def token = 'zebracrossing'
+    * call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Zebracrossing charlie', token: '#(adminToken)' }
+    * call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Zebracrossing alpha', token: '#(adminToken)' }
+    * call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Zebracrossing bravo', token: '#(adminToken)' }
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { q: '#(token)', sort: 'title', order: 'asc', limit: 100 }
+    When method GET
+    Then status 200
+    * def titles = karate.map(response.data, function(r){ return r.title })
+    * match each titles contains 'Zebracrossing'
+    * def sorted = karate.sort(titles, function(t){ return t })
+    * match titles == sorted
+    * match titles contains ['Zebracrossing alpha', 'Zebracrossing bravo', 'Zebracrossing charlie']
+
+    # Ranked by relevance, the same search returns the same requests
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { q: '#(token)', sort: 'relevance', limit: 100 }
+    When method GET
+    Then status 200
+    * def ranked = karate.map(response.data, function(r){ return r.title })
+    * match ranked contains only titles
+
+  Scenario Outline: Invalid admin request filter '<params>' returns 400
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params <params>
+    When method GET
+    Then status 400
+    And match response.error == 'invalid_filter'
+
+    Examples:
+      | params                        |
+      | { sort: 'password_hash' }     |
+      | { order: 'sideways' }         |
+      | { builder_id: 'not-a-uuid' }  |
+      | { is_free: 'maybe' }          |
+      | { created_from: '03/01/2026' } |
+      | { status: 'pending,archived' } |
+      | { type: 'desktop_app' }       |
+      | { hosting_type: 'netlify' }   |
+      | { complexity: 'extreme' }     |
+
   # ─── Admin: List users ──────────────────────────────────────────────
 
   Scenario: GET /admin/users returns user list
@@ -82,6 +149,92 @@ Feature: Admin API
       | 1    | 5     | 0      |
       | 1    | 50    | 0      |
 
+  # ─── Keyset (cursor) pagination ─────────────────────────────────────
+
+  Scenario: Cursor-paginated user list follows next_cursor
+    Given path '/admin/users'
+    And header Authorization = 'Bearer ' + adminToken
+    And param limit = 1
+    When method GET
+    Then status 200
+    And match response.limit == 1
+    And match response.next_cursor == '##string'
+    And match response !contains { total: '#notnull' }
+    * def cursor = response.next_cursor
+    * def firstId = response.data[0].id
+    * if (!cursor) karate.abort()
+    Given path '/admin/users'
+    And header Authorization = 'Bearer ' + adminToken
+    And param limit = 1
+    And param cursor = cursor
+    When method GET
+    Then status 200
+    And match each response.data[*].id != firstId
+
+  Scenario: The second page of requests continues from the first cursor
+    * def label = 'page-' + java.util.UUID.randomUUID().toString().substring(0, 8)
+    * def first = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Paged request 1', token: '#(adminToken)' }
+    * def second = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Paged request 2', token: '#(adminToken)' }
+    * def third = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Paged request 3', token: '#(adminToken)' }
+    * def fourth = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Paged request 4', token: '#(adminToken)' }
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(first.requestId)', '#(second.requestId)', '#(third.requestId)', '#(fourth.requestId)'], operation: 'add_label', label: '#(label)' }
+    When method POST
+    Then status 200
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { label: '#(label)', limit: 2 }
+    When method GET
+    Then status 200
+    * def page1 = karate.map(response.data, function(r){ return r.id })
+    * match page1 == ['#(fourth.requestId)', '#(third.requestId)']
+    * def cursor = response.next_cursor
+    * match cursor == '#string'
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { label: '#(label)', limit: 2, cursor: '#(cursor)' }
+    When method GET
+    Then status 200
+    * def page2 = karate.map(response.data, function(r){ return r.id })
+    * match page2 == ['#(second.requestId)', '#(first.requestId)']
+    * match page2 !contains any page1
+
+  Scenario: Cursor-paginated requests can include the total
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { limit: 5, include_total: 'true' }
+    When method GET
+    Then status 200
+    And match response.total == '#number'
+    And match response.next_cursor == '##string'
+
+  Scenario: Legacy offset pagination is flagged as deprecated
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And params { limit: 10, offset: 0 }
+    When method GET
+    Then status 200
+    And match response.total == '#number'
+    And match response.offset == 0
+    And match responseHeaders['Deprecation'][0] == 'true'
+
+  Scenario Outline: Invalid pagination '<params>' returns 400
+    Given path '<endpoint>'
+    And header Authorization = 'Bearer ' + adminToken
+    And params <params>
+    When method GET
+    Then status 400
+    And match response.error == 'invalid_pagination'
+
+    Examples:
+      | endpoint        | params                                          |
+      | /admin/users    | { cursor: 'not-a-cursor' }                      |
+      | /admin/requests | { offset: -5 }                                  |
+      | /admin/requests | { include_total: 'sometimes' }                  |
+
   # ─── Admin: Generate schedule slots ─────────────────────────────────
 
   Scenario: POST /admin/schedule/generate creates weekend slots
@@ -112,6 +265,7 @@ Feature: Admin API
   Scenario: PUT /admin/requests/:id with non-existent ID returns 404
     Given path '/admin/requests/00000000-0000-0000-0000-000000000000'
     And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = '"1"'
     And request { status: 'approved' }
     When method PUT
     Then status 404
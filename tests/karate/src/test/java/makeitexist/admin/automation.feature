@@ -0,0 +1,213 @@
+Feature: Automation rules
+  "When status = X for N days [and condition], then act" rules with dry-run previews
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def run = java.util.UUID.randomUUID().toString().substring(0, 8)
+    * def tag = 'stale-' + run
+
+  Scenario: Automation endpoints require authentication
+    Given path '/admin/automation/rules'
+    When method GET
+    Then status 401
+
+    Given path '/notifications'
+    When method GET
+    Then status 401
+
+  Scenario: Typical rules are seeded disabled
+    Given path '/admin/automation/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data == '#array'
+    * def seeded = karate.filter(response.data, function(r){ return r.name == 'Cancel abandoned requests' })
+    And match each seeded contains { status: 'pending', days: 30, action: 'transition', target_status: 'cancelled' }
+
+  Scenario: Preview, run and history of rules on idle requests
+    # Import two requests that have been pending since 2024
+    * def csv = 'external_key,title,request_type,student_email,status,created_at,labels\n' + 'A-' + run + '-1,Idle portfolio,website,idle-' + run + '@example.com,pending,2024-01-10,' + tag + '\n' + 'A-' + run + '-2,Idle club app,mobile_app,idle-' + run + '@example.com,pending,2024-02-10,' + tag + '\n'
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(csv)', filename: 'idle.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+    * def importId = response.data.id
+
+    Given path '/admin/imports', importId, 'dry-run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'validated'
+
+    Given path '/admin/imports', importId, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+
+    # Cancel idle mobile apps carrying the tag
+    Given path '/admin/automation/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Cancel idle apps', status: 'pending', days: 30, conditions: { request_type: 'mobile_app', label: '#(tag)' }, action: 'transition', target_status: 'cancelled' }
+    When method POST
+    Then status 201
+    And match response.data contains { enabled: false, action: 'transition', target_status: 'cancelled' }
+    * def cancelRuleId = response.data.id
+
+    Given path '/admin/automation/rules', cancelRuleId, 'preview'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data contains { dry_run: true, matched: 1, succeeded: 0 }
+    And match response.data.items[0] contains { title: 'Idle club app', status: 'pending' }
+    And assert response.data.items[0].idle_days > 30
+    * def appId = response.data.items[0].request_id
+
+    # The preview changed nothing
+    Given path '/requests', appId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'pending'
+
+    Given path '/admin/automation/rules', cancelRuleId, 'run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data contains { dry_run: false, matched: 1, succeeded: 1, failed: 0 }
+
+    Given path '/requests', appId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'cancelled'
+
+    # A second run finds nothing left to do
+    Given path '/admin/automation/rules', cancelRuleId, 'run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.matched == 0
+
+    Given path '/admin/automation/rules', cancelRuleId, 'history'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data == '#[1]'
+    And match response.data[0] contains { request_id: '#(appId)', ok: true }
+
+    # Notify admins about the other idle request, once per stay in the status
+    Given path '/admin/automation/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Flag idle websites', status: 'pending', days: 30, conditions: { label: '#(tag)' }, action: 'notify', recipient: 'admins', message: '{title} has been {status} for {days} days' }
+    When method POST
+    Then status 201
+    * def notifyRuleId = response.data.id
+
+    Given path '/admin/automation/rules', notifyRuleId, 'run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data contains { matched: 1, succeeded: 1 }
+
+    Given path '/admin/automation/rules', notifyRuleId, 'run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.matched == 0
+
+    Given path '/notifications'
+    And header Authorization = 'Bearer ' + adminToken
+    And param unread = 'true'
+    When method GET
+    Then status 200
+    * def mine = karate.filter(response.data, function(n){ return n.message.startsWith('Idle portfolio has been pending for ') })
+    And match mine == '#[1]'
+
+    Given path '/notifications', mine[0].id, 'read'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/admin/automation/rules', cancelRuleId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+    Given path '/admin/automation/rules', notifyRuleId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+  Scenario: Enable and update a rule
+    Given path '/admin/automation/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Tag stale reviews', status: 'review', days: 10, action: 'add_label', label: 'Waiting-On-Student' }
+    When method POST
+    Then status 201
+    And match response.data.label == 'waiting-on-student'
+    * def ruleId = response.data.id
+
+    Given path '/admin/automation/rules', ruleId
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Tag stale reviews', status: 'review', days: 14, action: 'add_label', label: 'waiting-on-student', enabled: true }
+    When method PUT
+    Then status 200
+    And match response.data contains { days: 14, enabled: true }
+
+    # Omitting enabled keeps it
+    Given path '/admin/automation/rules', ruleId
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Tag stale reviews', status: 'review', days: 14, action: 'add_label', label: 'waiting-on-student' }
+    When method PUT
+    Then status 200
+    And match response.data.enabled == true
+
+    Given path '/admin/automation/rules', ruleId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+    Given path '/admin/automation/rules', ruleId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 404
+
+  Scenario Outline: Invalid rule <name> returns 400
+    Given path '/admin/automation/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request <body>
+    When method POST
+    Then status 400
+
+    Examples:
+      | name                 | body                                                                                     |
+      | without days         | { name: 'x', status: 'pending', action: 'add_label', label: 'x' }                         |
+      | unknown action       | { name: 'x', status: 'pending', days: 3, action: 'delete' }                               |
+      | transition to itself | { name: 'x', status: 'pending', days: 3, action: 'transition', target_status: 'pending' } |
+      | transition no target | { name: 'x', status: 'pending', days: 3, action: 'transition' }                           |
+      | notify no message    | { name: 'x', status: 'review', days: 3, action: 'notify', recipient: 'student' }          |
+      | label missing        | { name: 'x', status: 'review', days: 3, action: 'add_label' }                             |
+
+  Scenario: Unknown rules and notifications return 404
+    Given path '/admin/automation/rules', java.util.UUID.randomUUID().toString(), 'preview'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 404
+
+    Given path '/notifications', java.util.UUID.randomUUID().toString(), 'read'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 404
@@ -0,0 +1,84 @@
+Feature: Admin exports
+  Streaming CSV and XLSX exports of requests, users and schedules
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+  Scenario: Export endpoints require authentication
+    Given path '/admin/exports/requests'
+    When method GET
+    Then status 401
+
+  Scenario Outline: List exportable columns for <dataset>
+    Given path '/admin/exports', '<dataset>', 'columns'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data == '#[_ > 0]'
+    And match each response.data == { key: '#string', header: '#string', type: '#regex (text|integer|money|boolean|date|datetime)' }
+
+    Examples:
+      | dataset   |
+      | requests  |
+      | users     |
+      | schedules |
+
+  Scenario: Export selected request columns as CSV with names joined in
+    * def title = 'Export Check ' + java.util.UUID.randomUUID().toString().substring(0, 8)
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: '#(title)', description: 'Row for the CSV export', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+
+    Given path '/admin/exports/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And param format = 'csv'
+    And param columns = 'title,status,student_name,estimated_cost,created_at'
+    And param q = title
+    When method GET
+    Then status 200
+    And match responseHeaders['Content-Type'][0] contains 'text/csv'
+    And match responseHeaders['Content-Disposition'][0] contains 'attachment; filename="requests-'
+    * def lines = karate.lowerCase(response).trim().split('\n')
+    And match lines[0] contains 'title,status,student,estimated cost,created'
+    And match lines[1] contains karate.lowerCase(title) + ',pending,'
+
+  Scenario: Export users as XLSX
+    Given path '/admin/exports/users'
+    And header Authorization = 'Bearer ' + adminToken
+    And param format = 'xlsx'
+    And param role = 'admin'
+    When method GET
+    Then status 200
+    And match responseHeaders['Content-Type'][0] contains 'spreadsheetml.sheet'
+    # XLSX files are zip archives
+    * def magic = new java.lang.String(java.util.Arrays.copyOf(responseBytes, 2))
+    And match magic == 'PK'
+
+  Scenario: Export schedules for a date range
+    Given path '/admin/exports/schedules'
+    And header Authorization = 'Bearer ' + adminToken
+    And param from = '2026-01-01'
+    And param to = '2026-12-31'
+    When method GET
+    Then status 200
+    And match response contains 'Date,Day,Request ID'
+
+  Scenario Outline: Invalid export '<params>' returns <expected>
+    Given path '/admin/exports/<dataset>'
+    And header Authorization = 'Bearer ' + adminToken
+    And params <params>
+    When method GET
+    Then status <expected>
+
+    Examples:
+      | dataset   | params                         | expected |
+      | requests  | { format: 'pdf' }              | 400      |
+      | requests  | { columns: 'title,password' }  | 400      |
+      | requests  | { status: 'pending', order: 'sideways' } | 400 |
+      | users     | { role: 'teacher' }            | 400      |
+      | schedules | { from: '01-01-2026' }         | 400      |
+      | invoices  | {}                             | 400      |
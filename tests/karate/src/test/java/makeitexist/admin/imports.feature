@@ -0,0 +1,157 @@
+Feature: Admin legacy CSV import
+  Upload the old spreadsheet, map its columns, dry run, then commit idempotently
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def run = java.util.UUID.randomUUID().toString().substring(0, 8)
+
+  Scenario: Import endpoints require authentication
+    Given path '/admin/imports'
+    When method POST
+    Then status 401
+
+  Scenario: Upload, dry run with errors, fix, commit and re-import
+    * def bad = 'Sheet ID,Title,Type,Student Email,Student Name,Status,Cost,Created At\n' + 'L-' + run + '-1,Canteen site,website,legacy-' + run + '@example.com,Old Student,completed,"₹2,500",15/03/2024\n' + 'L-' + run + '-2,Hostel app,spaceship,legacy-' + run + '@example.com,Old Student,completed,1500,16/03/2024\n'
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(bad)', filename: 'legacy.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+    And match response.data.status == 'uploaded'
+    And match response.data.row_count == 2
+    And match response.data.mapping contains { title: 'Title', student_email: 'Student Email', student_name: 'Student Name', status: 'Status', created_at: 'Created At' }
+    And match response.data.mapping !contains { external_key: '#string' }
+    * def badId = response.data.id
+
+    # Required fields must be mapped before a dry run
+    Given path '/admin/imports', badId, 'dry-run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 400
+
+    Given path '/admin/imports', badId, 'mapping'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { mapping: { external_key: 'Sheet ID', title: 'Title', request_type: 'Type', student_email: 'Student Email', student_name: 'Student Name', status: 'Status', estimated_cost: 'Cost', created_at: 'Created At' } }
+    When method PUT
+    Then status 200
+
+    Given path '/admin/imports', badId, 'dry-run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'invalid'
+    And match response.data.report.valid == 1
+    And match response.data.report.errors[0] contains { row: 3, field: 'request_type' }
+
+    # Committing an invalid batch is refused
+    Given path '/admin/imports', badId, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 409
+
+    # Fixed file with the expected headers maps itself
+    * def good = 'external_key,title,request_type,student_email,student_name,status,estimated_cost,created_at\n' + 'L-' + run + '-1,Canteen site,website,legacy-' + run + '@example.com,Old Student,completed,"₹2,500",15/03/2024\n' + 'L-' + run + '-2,Hostel app,mobile_app,LEGACY-' + run + '@example.com,Old Student,completed,1500,16/03/2024\n'
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(good)', filename: 'legacy-fixed.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+    * def goodId = response.data.id
+
+    Given path '/admin/imports', goodId, 'dry-run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'validated'
+    And match response.data.report contains { rows: 2, valid: 2, create: 2, skip: 0, new_students: 1, errors: [] }
+
+    Given path '/admin/imports', goodId, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'committed'
+    And match response.data.report contains { create: 2, skip: 0, new_students: 1 }
+
+    Given path '/admin/imports', goodId, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 409
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And param label = 'imported'
+    And param q = 'Canteen site'
+    When method GET
+    Then status 200
+    * def imported = karate.filter(response.data, function(r){ return r.status == 'completed' && r.estimated_cost == 2500 })
+    And match imported == '#[_ > 0]'
+
+    # Uploading the same rows again skips everything
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(good)', filename: 'legacy-fixed.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+    * def againId = response.data.id
+
+    Given path '/admin/imports', againId, 'dry-run'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.report contains { create: 0, skip: 2, new_students: 0 }
+
+    Given path '/admin/imports', againId, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.report contains { create: 0, skip: 2, new_students: 0 }
+
+  Scenario: Commit before a dry run is refused
+    * def csv = 'external_key,title,request_type,student_email\nL-' + run + '-x,Quick site,website,quick-' + run + '@example.com\n'
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(csv)', filename: 'quick.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+
+    Given path '/admin/imports', response.data.id, 'commit'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 409
+
+  Scenario Outline: Invalid import upload '<name>' returns 400
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '<csv>', filename: '<name>', contentType: 'text/csv' }
+    When method POST
+    Then status 400
+
+    Examples:
+      | name       | csv                   |
+      | empty.csv  |                       |
+      | dupes.csv  | title,title\na,b      |
+
+  Scenario: Mapping to an unknown column returns 400
+    * def csv = 'external_key,title,request_type,student_email\nL-' + run + '-m,Map site,website,map-' + run + '@example.com\n'
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + adminToken
+    And multipart file file = { value: '#(csv)', filename: 'map.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+
+    Given path '/admin/imports', response.data.id, 'mapping'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { mapping: { title: 'Project Name' } }
+    When method PUT
+    Then status 400
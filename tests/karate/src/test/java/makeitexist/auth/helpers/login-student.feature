@@ -0,0 +1,56 @@
+Feature: Login Student Helper
+  Reusable helper to obtain a JWT for a fresh student. Students sign in with
+  SSO, so the student is created by importing an old request for them and
+  given a password by an admin. Returns token and userId.
+
+  Background:
+    * url baseUrl
+
+  Scenario: Obtain student token
+    * def admin = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def run = java.util.UUID.randomUUID().toString().substring(0, 8)
+    * def email = 'student-' + run + '@example.com'
+    * def password = 'student-' + run
+    * def title = 'Seed request ' + run
+    # Completed and from an old semester, so it counts against no quota
+    * def csv = 'external_key,title,request_type,student_email,student_name,status,created_at\n' + 'S-' + run + ',' + title + ',website,' + email + ',Test Student,completed,15/03/2024\n'
+
+    Given path '/admin/imports'
+    And header Authorization = 'Bearer ' + admin.token
+    And multipart file file = { value: '#(csv)', filename: 'student.csv', contentType: 'text/csv' }
+    When method POST
+    Then status 201
+    * def importId = response.data.id
+
+    Given path '/admin/imports', importId, 'dry-run'
+    And header Authorization = 'Bearer ' + admin.token
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.report.new_students == 1
+
+    Given path '/admin/imports', importId, 'commit'
+    And header Authorization = 'Bearer ' + admin.token
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + admin.token
+    And param q = title
+    When method GET
+    Then status 200
+    * def userId = response.data[0].user_id
+
+    Given path '/admin/users', userId, 'reset-password'
+    And header Authorization = 'Bearer ' + admin.token
+    And request { new_password: '#(password)' }
+    When method PUT
+    Then status 200
+
+    Given path '/auth/login'
+    And request { email: '#(email)', password: '#(password)' }
+    When method POST
+    Then status 200
+    And match response.data.user.role == 'student'
+    * def token = response.data.token
@@ -0,0 +1,121 @@
+Feature: Pricing API
+  Tests for /api/v1/pricing and /api/v1/admin/pricing endpoints
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+  # ─── Auth guard ─────────────────────────────────────────────────────
+
+  Scenario Outline: Pricing endpoints reject unauthenticated calls
+    Given path '<endpoint>'
+    When method GET
+    Then status 401
+
+    Examples:
+      | endpoint              |
+      | /pricing              |
+      | /admin/pricing/rules  |
+
+  # ─── Active rules & estimates ───────────────────────────────────────
+
+  Scenario: GET /pricing returns the active rule set
+    Given path '/pricing'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'active'
+    And match response.data.rules.base_prices == '#object'
+
+  Scenario Outline: Estimate <request_type> / <complexity> / <hosting_type>
+    Given path '/pricing/estimate'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_type: '<request_type>', complexity: '<complexity>', hosting_type: '<hosting_type>' }
+    When method POST
+    Then status 200
+    And match response.data.lines == '#[_ > 0]'
+    And match response.data.total == <total>
+    And match response.data.free == <free>
+
+    Examples:
+      | request_type | complexity | hosting_type | total   | free  |
+      | website      | basic      | vercel       | 0       | true  |
+      | website      | standard   | whitelabel   | 7998    | false |
+      | mobile_app   | basic      | replit       | 4498.5  | false |
+      | both         | advanced   | whitelabel   | 19997.5 | false |
+
+  Scenario: Estimate with an unknown add-on returns 400
+    Given path '/pricing/estimate'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_type: 'mobile_app', complexity: 'basic', hosting_type: 'vercel', add_ons: ['time_machine'] }
+    When method POST
+    Then status 400
+
+  # ─── Admin: versioned rule sets ─────────────────────────────────────
+
+  Scenario: Draft a rule set and preview its impact
+    Given path '/admin/pricing/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request
+      """
+      {
+        "name": "Karate draft",
+        "rules": {
+          "currency": "INR",
+          "free_websites": true,
+          "base_prices": { "basic": 3499, "standard": 6499, "advanced": 12999 },
+          "type_multipliers": { "website": 1, "mobile_app": 1.5, "both": 1.75 },
+          "hosting_surcharges": { "whitelabel": 2499 },
+          "add_ons": [ { "code": "seo", "label": "SEO setup", "amount": 999 } ]
+        }
+      }
+      """
+    When method POST
+    Then status 201
+    And match response.data.status == 'draft'
+    * def version = response.data.version
+
+    Given path '/admin/pricing/rules', version, 'impact'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.new_version == version
+    And match response.data.items == '#[]'
+
+  Scenario: Draft with missing base price returns 400
+    Given path '/admin/pricing/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Broken', rules: { currency: 'INR', base_prices: { basic: 100 } } }
+    When method POST
+    Then status 400
+
+  Scenario: Draft with fractional paise returns 400
+    Given path '/admin/pricing/rules'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { name: 'Too precise', rules: { currency: 'INR', base_prices: { basic: 100.005, standard: 200, advanced: 300 } } }
+    When method POST
+    Then status 400
+
+  Scenario: Multiplied prices are exact to the paisa
+    Given path '/pricing/estimate'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_type: 'mobile_app', complexity: 'standard', hosting_type: 'replit' }
+    When method POST
+    Then status 200
+    And match response.data.total == 8998.5
+    And match response.data.currency == 'INR'
+    * def sumPaise = function(lines){ var t = 0; for (var i = 0; i < lines.length; i++) t += Math.round(lines[i].amount * 100); return t }
+    * def lineSum = sumPaise(response.data.lines)
+    And match lineSum == 899850
+
+  Scenario Outline: Unknown rule set version '<version>' returns <expected>
+    Given path '/admin/pricing/rules/<version>'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status <expected>
+
+    Examples:
+      | version | expected |
+      | 99999   | 404      |
+      | abc     | 400      |
@@ -0,0 +1,157 @@
+Feature: Delivery acceptance
+  A request in review is completed only when the student accepts it. They
+  can instead ask for a revision, and rate the build once accepted.
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+
+    # A free website request, ready for review
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Portfolio Site', description: 'Personal portfolio with blog', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+  Scenario: Revise, accept and rate a delivery
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests', requestId, 'acceptance'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.accepted == false
+    And match response.data.revisions_used == 0
+    And match response.data.reviews == '#[0]'
+
+    Given path '/requests', requestId, 'revisions'
+    And header Authorization = 'Bearer ' + authToken
+    And request { notes: 'Blog posts need dates' }
+    When method POST
+    Then status 200
+    And match response.data.status == 'building'
+    And match response.data.revisions_used == 1
+    And match response.data.revisions_left == response.data.included_revisions - 1
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests', requestId, 'acceptance'
+    And header Authorization = 'Bearer ' + authToken
+    And request { notes: 'Looks great' }
+    When method POST
+    Then status 200
+    And match response.data.status == 'completed'
+    And match response.data.accepted == true
+    And match response.data.reviews == '#[2]'
+    And match response.data.reviews[1].round == 2
+    And match response.data.reviews[1].outcome == 'accepted'
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'completed'
+    And match response.data.completed_at == '#string'
+
+    Given path '/requests', requestId, 'rating'
+    And header Authorization = 'Bearer ' + authToken
+    And request { builder_score: 5, request_score: 4, feedback: 'Quick turnaround' }
+    When method PUT
+    Then status 200
+    And match response.data.builder_score == 5
+    And match response.data.request_score == 4
+
+    # Rating again updates it
+    Given path '/requests', requestId, 'rating'
+    And header Authorization = 'Bearer ' + authToken
+    And request { builder_score: 4, request_score: 4 }
+    When method PUT
+    Then status 200
+    And match response.data.builder_score == 4
+
+    Given path '/requests', requestId, 'acceptance'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.rating.builder_score == 4
+
+  Scenario Outline: Rating with scores <builder>/<request> returns 400
+    Given path '/requests', requestId, 'rating'
+    And header Authorization = 'Bearer ' + authToken
+    And request { builder_score: <builder>, request_score: <request> }
+    When method PUT
+    Then status 400
+
+    Examples:
+      | builder | request |
+      | 0       | 3       |
+      | 6       | 3       |
+      | 3       | 0       |
+
+  Scenario: Rating before acceptance returns 409
+    Given path '/requests', requestId, 'rating'
+    And header Authorization = 'Bearer ' + authToken
+    And request { builder_score: 5, request_score: 5 }
+    When method PUT
+    Then status 409
+
+  Scenario: Accepting a request that is not in review returns 409
+    Given path '/requests', requestId, 'acceptance'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 409
+
+  Scenario: A revision needs notes
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests', requestId, 'revisions'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 400
+
+  Scenario: Staff cannot complete a request the student has not accepted
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 200
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'completed' }
+    When method PUT
+    Then status 409
+    And match response.error == '#string'
+
+  Scenario: Builder quality report
+    Given path '/admin/builders/quality'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data == '#array'
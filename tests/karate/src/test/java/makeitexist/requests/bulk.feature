@@ -0,0 +1,194 @@
+Feature: Bulk admin operations
+  POST /admin/requests/bulk applies one operation to many requests, picked
+  by ID or by filter, either atomically or best-effort.
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def adminId = loginResult.user.id
+    * def runId = java.util.UUID.randomUUID().toString().substring(0, 8)
+
+    # Two free websites and one paid app, all pending
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Chess Club Site', description: 'Fixtures and results', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def freeA = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Photography Portfolio', description: 'Gallery of student work', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def freeB = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Canteen Ordering App', description: 'Pre-order lunch from class', request_type: 'mobile_app', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def paidId = response.data.id
+
+  Scenario: Queue several free websites at once
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(freeB)'], operation: 'set_status', status: 'queued' }
+    When method POST
+    Then status 200
+    And match response.data.applied == true
+    And match response.data.matched == 2
+    And match response.data.succeeded == 2
+    And match each response.data.items == { request_id: '#string', ok: true, status: 'queued' }
+
+    Given path '/requests', freeB
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'queued'
+
+  Scenario: An atomic run changes nothing when one request fails the lifecycle
+    # The paid app has no accepted quote, so it cannot be queued
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(paidId)'], operation: 'set_status', status: 'queued' }
+    When method POST
+    Then status 409
+    And match response.error == 'bulk_rejected'
+    And match response.data.applied == false
+    And match response.data.failed == 1
+    * def failed = karate.filter(response.data.items, function(x){ return !x.ok })
+    And match failed[0].request_id == paidId
+    And match failed[0].error == '#string'
+
+    Given path '/requests', freeA
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'pending'
+
+  Scenario: A best-effort run applies what it can
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(paidId)'], operation: 'set_status', status: 'queued', mode: 'best_effort' }
+    When method POST
+    Then status 200
+    And match response.data.applied == true
+    And match response.data.succeeded == 1
+    And match response.data.failed == 1
+
+    Given path '/requests', freeA
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'queued'
+
+  Scenario: Label requests, then select them by filter
+    * def label = 'fest-' + runId
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(paidId)'], operation: 'add_label', label: '#(label)' }
+    When method POST
+    Then status 200
+    And match response.data.succeeded == 2
+
+    Given path '/admin/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And param label = label
+    When method GET
+    Then status 200
+    And match response.data == '#[2]'
+    And match each response.data[*].labels contains label
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { filter: { label: '#(label)', request_type: 'mobile_app' }, operation: 'set_complexity', complexity: 'advanced' }
+    When method POST
+    Then status 200
+    And match response.data.matched == 1
+    And match response.data.items[0].request_id == paidId
+
+    Given path '/requests', paidId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.complexity == 'advanced'
+
+  Scenario: Assign a builder and cancel
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(freeB)'], operation: 'assign_builder', builder_id: '#(adminId)' }
+    When method POST
+    Then status 200
+
+    Given path '/requests', freeA
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.builder_id == adminId
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)'], operation: 'cancel' }
+    When method POST
+    Then status 200
+    And match response.data.items[0].status == 'cancelled'
+
+    # Cancelling again fails: the request is already closed
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(freeB)'], operation: 'cancel' }
+    When method POST
+    Then status 409
+    And match response.data.items[0].ok == false
+
+  Scenario: A bulk status change cannot reopen a closed request
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)'], operation: 'cancel' }
+    When method POST
+    Then status 200
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '#(freeB)'], operation: 'set_status', status: 'pending', mode: 'best_effort' }
+    When method POST
+    Then status 200
+    And match response.data.failed == 1
+    And match response.data.items[0].ok == false
+    And match response.data.items[0].error contains 'cancelled'
+    And match response.data.items[1].ok == true
+
+    Given path '/requests', freeA
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'cancelled'
+
+  Scenario: Unknown request IDs are reported per item
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request { ids: ['#(freeA)', '00000000-0000-0000-0000-000000000000'], operation: 'add_label', label: 'x', mode: 'best_effort' }
+    When method POST
+    Then status 200
+    And match response.data.succeeded == 1
+    And match response.data.items[1].error == 'request not found'
+
+  Scenario Outline: Invalid bulk request returns <expected> — <case>
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + authToken
+    And request <body>
+    When method POST
+    Then status <expected>
+
+    Examples:
+      | case                 | body                                                                   | expected |
+      | no selection         | { operation: 'cancel' }                                                | 400      |
+      | ids and filter       | { ids: ['#(freeA)'], filter: {}, operation: 'cancel' }                 | 400      |
+      | unknown operation    | { ids: ['#(freeA)'], operation: 'delete' }                             | 400      |
+      | status missing       | { ids: ['#(freeA)'], operation: 'set_status' }                         | 400      |
+      | bad status           | { ids: ['#(freeA)'], operation: 'set_status', status: 'done' }         | 400      |
+      | empty label          | { ids: ['#(freeA)'], operation: 'add_label', label: '  ' }             | 400      |
+      | bad filter date      | { filter: { created_from: '01/10/2026' }, operation: 'cancel' }        | 400      |
+      | builder not found    | { ids: ['#(freeA)'], operation: 'assign_builder', builder_id: '00000000-0000-0000-0000-000000000000' } | 404 |
@@ -0,0 +1,61 @@
+Feature: Whitelabel TLS certificates
+  Verified whitelabel domains get a certificate from an ACME CA, answered with HTTP-01 challenges
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def run = java.util.UUID.randomUUID().toString().substring(0, 8)
+    * def host = 'certs-' + run + '.example.com'
+    * def siteUrl = baseUrl.replace('/api/v1', '')
+
+  Scenario: Certificate endpoints require authentication
+    Given path '/requests', java.util.UUID.randomUUID() + '', 'certificate'
+    When method GET
+    Then status 401
+
+  Scenario: An unverified domain cannot get a certificate
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Secure Store', description: 'Store served over HTTPS', request_type: 'website', hosting_type: 'whitelabel', whitelabel_domain: '#(host)' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/requests', requestId, 'certificate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 404
+
+    # 409 until the TXT record is verified, or 503 when no ACME directory is configured
+    Given path '/admin/requests', requestId, 'certificate'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then assert responseStatus == 409 || responseStatus == 503
+    And match response.error == 'provision_failed'
+
+    Given path '/admin/requests', requestId, 'certificate', 'pem'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 404
+
+  Scenario: Requests without a custom domain cannot get a certificate
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Plain Site', description: 'Hosted on Vercel', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/admin/requests', requestId, 'certificate'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then assert responseStatus == 409 || responseStatus == 503
+
+  Scenario: Unknown ACME challenge tokens are not found
+    Given url siteUrl
+    And path '/.well-known/acme-challenge', 'unknown-' + run
+    When method GET
+    Then status 404
@@ -0,0 +1,76 @@
+Feature: Optimistic concurrency on request updates
+  Reads return an ETag; admin updates must send it back in If-Match so concurrent edits cannot overwrite each other
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Concurrent Edits', description: 'Two admins at once', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+  Scenario: A stale ETag is rejected with the current representation
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.version == 1
+    And match responseHeaders['ETag'][0] == '"1"'
+    * def etag = responseHeaders['ETag'][0]
+
+    # First admin saves
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = etag
+    And request { delivery_url: 'https://first.example.com' }
+    When method PUT
+    Then status 200
+    And match response.data.version == 2
+    And match responseHeaders['ETag'][0] == '"2"'
+
+    # Second admin still holds the old ETag
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = etag
+    And request { delivery_url: 'https://second.example.com' }
+    When method PUT
+    Then status 412
+    And match response.error == 'precondition_failed'
+    And match response.data.delivery_url == 'https://first.example.com'
+    And match response.data.version == 2
+    And match responseHeaders['ETag'][0] == '"2"'
+
+    # Retrying with the fresh ETag succeeds
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = '"2"'
+    And request { delivery_url: 'https://second.example.com' }
+    When method PUT
+    Then status 200
+    And match response.data.delivery_url == 'https://second.example.com'
+    And match response.data.version == 3
+
+  Scenario: Updates without If-Match are refused
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And request { delivery_url: 'https://blind.example.com' }
+    When method PUT
+    Then status 428
+    And match response.error == 'precondition_required'
+
+  Scenario Outline: Malformed If-Match <etag> returns 400
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = '<etag>'
+    And request { delivery_url: 'https://odd.example.com' }
+    When method PUT
+    Then status 400
+
+    Examples:
+      | etag   |
+      | 1      |
+      | "abc"  |
+      | *      |
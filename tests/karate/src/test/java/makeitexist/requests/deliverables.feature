@@ -0,0 +1,195 @@
+Feature: Deliverables
+  Requests ship as one or more deliverables (web, android, ios, api), each
+  tracked separately. Once work starts the request status follows them.
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def webChecklist =
+      """
+      [
+        { item: 'Production URL live', done: true },
+        { item: 'Domain and DNS documented', done: true },
+        { item: 'Admin credentials handed over', done: true },
+        { item: 'Source repository transferred', done: true }
+      ]
+      """
+
+    # A free website request with the default web deliverable and an API
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Club Website', description: 'Site with events API', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/admin/requests', requestId, 'deliverables'
+    And header Authorization = 'Bearer ' + authToken
+    When method POST
+    Then status 201
+    And match response.data == '#[1]'
+    And match response.data[0].kind == 'web'
+    And match response.data[0].status == 'planned'
+    And match response.data[0].checklist == '#[4]'
+    * def webId = response.data[0].id
+
+    Given path '/admin/requests', requestId, 'deliverables'
+    And header Authorization = 'Bearer ' + authToken
+    And request { kind: 'api', label: 'Events API' }
+    When method POST
+    Then status 201
+    * def apiId = response.data[0].id
+
+  Scenario: Work cannot start before the request is scheduled
+    Given path '/admin/requests', requestId, 'deliverables', webId
+    And header Authorization = 'Bearer ' + authToken
+    And request { status: 'building' }
+    When method PATCH
+    Then status 409
+
+  Scenario: Request status is derived from its deliverables
+    * def open = call read('classpath:makeitexist/schedule/helpers/open-slot.feature') { token: '#(authToken)', hours: 1 }
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + authToken
+    And request { request_id: '#(requestId)', slot_id: '#(open.slot.id)', estimated_hours: 1 }
+    When method POST
+    Then status 201
+
+    Given path '/admin/requests', requestId, 'deliverables', webId
+    And header Authorization = 'Bearer ' + authToken
+    And request { status: 'building', repo_url: 'https://github.com/example/club-site' }
+    When method PATCH
+    Then status 200
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'building'
+    And match response.data.repo_url == 'https://github.com/example/club-site'
+
+    # Derived statuses cannot be set by hand
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 409
+
+    # Delivery needs the handover checklist
+    Given path '/admin/requests', requestId, 'deliverables', webId
+    And header Authorization = 'Bearer ' + authToken
+    And request { status: 'delivered' }
+    When method PATCH
+    Then status 409
+
+    Given path '/admin/requests', requestId, 'deliverables', webId, 'artifacts'
+    And header Authorization = 'Bearer ' + authToken
+    And request { name: 'Static bundle', url: 'https://example.com/builds/club-site-1.0.zip', version: '1.0.0' }
+    When method POST
+    Then status 201
+    And match response.data.artifacts[0].version == '1.0.0'
+
+    Given path '/admin/requests', requestId, 'deliverables', webId
+    And header Authorization = 'Bearer ' + authToken
+    And request { status: 'delivered', delivery_url: 'https://club.example.com', checklist: '#(webChecklist)' }
+    When method PATCH
+    Then status 200
+    And match response.data.status == 'delivered'
+    And match each response.data.checklist contains { done: true }
+
+    # The API is still planned, so the request is still being built
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'building'
+
+    Given path '/admin/requests', requestId, 'deliverables', apiId
+    And header Authorization = 'Bearer ' + authToken
+    And request { status: 'dropped' }
+    When method PATCH
+    Then status 200
+
+    # Everything delivered: the student reviews it
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'review'
+    And match response.data.delivery_url == 'https://club.example.com'
+
+    # A revision puts the delivered work back into building
+    Given path '/requests', requestId, 'revisions'
+    And header Authorization = 'Bearer ' + authToken
+    And request { notes: 'Sponsor logos are blurry' }
+    When method POST
+    Then status 200
+    And match response.data.status == 'building'
+
+    Given path '/requests', requestId, 'deliverables'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    * def web = karate.filter(response.data, function(x){ return x.kind == 'web' })[0]
+    And match web.status == 'building'
+
+  Scenario: Artifacts added at the same time are all kept
+    * def attempts = 6
+    * def upload =
+      """
+      function() {
+        var HttpClient = Java.type('java.net.http.HttpClient');
+        var HttpRequest = Java.type('java.net.http.HttpRequest');
+        var BodyPublishers = Java.type('java.net.http.HttpRequest$BodyPublishers');
+        var BodyHandlers = Java.type('java.net.http.HttpResponse$BodyHandlers');
+        var URI = Java.type('java.net.URI');
+        var client = HttpClient.newHttpClient();
+        var url = baseUrl + '/admin/requests/' + requestId + '/deliverables/' + webId + '/artifacts';
+        var pending = [];
+        for (var i = 0; i < attempts; i++) {
+          var body = JSON.stringify({ name: 'Build ' + i, url: 'https://example.com/builds/' + i + '.zip', version: '1.0.' + i });
+          var req = HttpRequest.newBuilder(URI.create(url))
+            .header('Authorization', 'Bearer ' + authToken)
+            .header('Content-Type', 'application/json')
+            .POST(BodyPublishers.ofString(body))
+            .build();
+          pending.push(client.sendAsync(req, BodyHandlers.ofString()));
+        }
+        var statuses = [];
+        for (var j = 0; j < pending.length; j++) {
+          statuses.push(pending[j].join().statusCode());
+        }
+        return statuses;
+      }
+      """
+    * def statuses = upload()
+    * match each statuses == 201
+
+    Given path '/requests', requestId, 'deliverables'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    * def web = karate.filter(response.data, function(x){ return x.kind == 'web' })[0]
+    And match web.artifacts == '#[6]'
+
+  Scenario: Students see deliverables of their own requests
+    Given path '/requests', requestId, 'deliverables'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data[*].kind contains only ['web', 'api']
+
+  Scenario Outline: Invalid deliverable input is rejected - <description>
+    Given path '/admin/requests', requestId, 'deliverables', webId
+    And header Authorization = 'Bearer ' + authToken
+    And request <body>
+    When method PATCH
+    Then status 400
+
+    Examples:
+      | description    | body                    |
+      | unknown status | { status: 'shipped' }   |
+      | blank checklist item | { checklist: [ { done: true } ] } |
@@ -0,0 +1,88 @@
+Feature: Whitelabel domain verification
+  Students prove they control a custom domain with a DNS TXT record before the build is scheduled
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def run = java.util.UUID.randomUUID().toString().substring(0, 8)
+    * def host = 'shop-' + run + '.example.com'
+
+  Scenario: Issue a TXT challenge, check it and block scheduling until verified
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Branded Store', description: 'Store on our own domain', request_type: 'website', hosting_type: 'whitelabel', whitelabel_domain: '#("https://" + host.toUpperCase() + "/")' }
+    When method POST
+    Then status 201
+    And match response.data.whitelabel_domain == host
+    * def requestId = response.data.id
+
+    Given path '/requests', requestId, 'domain'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data contains { domain: '#(host)', status: 'pending', record_type: 'TXT', record_name: '#("_makeitexist-challenge." + host)' }
+    And match response.data.record_value == '#regex makeitexist-verify=[0-9a-f]{32}'
+    * def recordValue = response.data.record_value
+
+    # The token is stable across calls
+    Given path '/requests', requestId, 'domain'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.record_value == recordValue
+
+    # Nobody published the record, so the check fails
+    Given path '/requests', requestId, 'domain', 'verify'
+    And header Authorization = 'Bearer ' + adminToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'failed'
+    And match response.data.last_error == '#string'
+    And match response.data.checked_at == '#string'
+    And match response.data.verified_at == '#notpresent'
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.domain_status == 'failed'
+
+    * def open = call read('classpath:makeitexist/schedule/helpers/open-slot.feature') { token: '#(adminToken)', hours: 1 }
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(requestId)', slot_id: '#(open.slot.id)', estimated_hours: 1 }
+    When method POST
+    Then status 409
+    And match response.message contains 'domain has not been verified'
+
+  Scenario: Requests without a whitelabel domain have nothing to verify
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Plain Site', description: 'Hosted on vercel', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+
+    Given path '/requests', response.data.id, 'domain'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 409
+
+  Scenario Outline: Invalid whitelabel domain '<domain>' returns 400
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Bad Domain Store', description: 'Store with a typo', request_type: 'website', hosting_type: 'whitelabel', whitelabel_domain: '<domain>' }
+    When method POST
+    Then status 400
+
+    Examples:
+      | domain         |
+      | localhost      |
+      | my shop.com    |
+      | under_score.io |
+
+  Scenario: Domain endpoints require authentication
+    Given path '/requests', java.util.UUID.randomUUID().toString(), 'domain'
+    When method GET
+    Then status 401
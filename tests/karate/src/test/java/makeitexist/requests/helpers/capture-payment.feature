@@ -0,0 +1,32 @@
+Feature: Capture Payment Helper
+  Reusable helper that delivers a signed payment.captured webhook from the
+  fake provider. Expects orderId, paymentId and amount.
+
+  Background:
+    * url baseUrl
+
+  Scenario: Deliver captured payment
+    * def webhookSecret = 'dev-webhook-secret'
+    * def sign =
+      """
+      function(body) {
+        var Mac = Java.type('javax.crypto.Mac');
+        var SecretKeySpec = Java.type('javax.crypto.spec.SecretKeySpec');
+        var StandardCharsets = Java.type('java.nio.charset.StandardCharsets');
+        var mac = Mac.getInstance('HmacSHA256');
+        mac.init(new SecretKeySpec(webhookSecret.getBytes(StandardCharsets.UTF_8), 'HmacSHA256'));
+        var digest = mac.doFinal(body.getBytes(StandardCharsets.UTF_8));
+        var hex = '';
+        for (var i = 0; i < digest.length; i++) {
+          hex += ('0' + (digest[i] & 0xff).toString(16)).slice(-2);
+        }
+        return hex;
+      }
+      """
+    * def body = JSON.stringify({ event: 'payment.captured', payload: { payment: { entity: { id: paymentId, order_id: orderId, amount: Math.round(amount * 100), currency: 'INR', created_at: Math.floor(java.lang.System.currentTimeMillis() / 1000) } } } })
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
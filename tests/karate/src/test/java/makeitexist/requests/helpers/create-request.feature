@@ -0,0 +1,14 @@
+Feature: Create Request Helper
+  Reusable helper that creates a free website request. Expects title and
+  token; returns requestId.
+
+  Background:
+    * url baseUrl
+
+  Scenario: Create request
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + token
+    And request { title: '#(title)', description: 'Created by a test helper', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
@@ -0,0 +1,13 @@
+Feature: Request ETag Helper
+  Reusable helper that reads a request and returns its ETag, for the If-Match
+  header that admin updates require. Expects requestId and token.
+
+  Background:
+    * url baseUrl
+
+  Scenario: Read request ETag
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + token
+    When method GET
+    Then status 200
+    * def etag = responseHeaders['ETag'][0]
@@ -0,0 +1,76 @@
+Feature: Idempotency keys
+  Retried POSTs with the same Idempotency-Key replay the first response instead of creating duplicates
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def key = java.util.UUID.randomUUID().toString()
+    * def body = { title: 'Flaky Wi-Fi Site', description: 'Submitted twice by the app', request_type: 'website', hosting_type: 'vercel' }
+
+  Scenario: A retry with the same key returns the original request
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request body
+    When method POST
+    Then status 201
+    And match responseHeaders['Idempotent-Replayed'] == '#notpresent'
+    * def requestId = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request body
+    When method POST
+    Then status 201
+    And match responseHeaders['Idempotent-Replayed'][0] == 'true'
+    And match response.data.id == requestId
+
+  Scenario: Reusing a key with a different body is rejected
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request body
+    When method POST
+    Then status 201
+
+    * set body.title = 'Something else entirely'
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request body
+    When method POST
+    Then status 422
+    And match response.error == 'idempotency_key_reused'
+
+  Scenario: Client errors are replayed too
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request { title: 'No type' }
+    When method POST
+    Then status 400
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And header Idempotency-Key = key
+    And request { title: 'No type' }
+    When method POST
+    Then status 400
+    And match responseHeaders['Idempotent-Replayed'][0] == 'true'
+
+  Scenario: Requests without a key are not deduplicated
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request body
+    When method POST
+    Then status 201
+    * def firstId = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request body
+    When method POST
+    Then status 201
+    And match response.data.id != firstId
@@ -0,0 +1,162 @@
+Feature: Request quotas and duplicate detection
+  New requests are checked against per-student quotas and the student's
+  recent submissions. Staff are never limited but still see look-alikes.
+  The test stack runs with INTAKE_BLOCK_DUPLICATES on.
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def adminId = loginResult.user.id
+    * def suffix = java.util.UUID.randomUUID().toString()
+    * def title = 'Robotics club site ' + suffix
+    * def description = 'Event calendar, member gallery and sponsor page for the robotics club ' + suffix
+
+  Scenario: Resubmitting a near-identical request is flagged
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: '#(title)', description: '#(description)', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def firstId = response.data.id
+    And match response.data contains { possible_duplicates: '#notpresent' }
+
+    Given path '/requests/check'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: '#(title)', description: '#(description + ".")', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 200
+    And match response.data.allowed == true
+    And match response.data.quota.exempt == true
+    And match response.data.quota.semester == '#regex \\d{4}-S[12]'
+    And match response.data.possible_duplicates[0].request_id == firstId
+    And assert response.data.possible_duplicates[0].similarity >= 0.8
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: '#(title)', description: '#(description)', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    And match response.data.possible_duplicates[*].request_id contains firstId
+
+  Scenario: Unrelated requests are not flagged
+    Given path '/requests/check'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: '#("Canteen ordering app " + suffix)', description: 'Pre-order lunch and pay at pickup', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 200
+    And match response.data.possible_duplicates == []
+
+  Scenario: A student over the open free limit is refused
+    * def student = call read('classpath:makeitexist/auth/helpers/login-student.feature')
+    # Unrelated topics, so the duplicate check stays out of the way
+    * def topics = [['Chess ladder', 'Rankings and fixtures for the chess society'], ['Laundry rota', 'Washing machine bookings per hostel floor'], ['Mentor board', 'Match juniors with alumni volunteers'], ['Lost property', 'Report and claim items handed in at reception']]
+    * def free = function(i){ return { title: topics[i - 1][0], description: topics[i - 1][1], request_type: 'website', hosting_type: 'vercel' } }
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(1)
+    When method POST
+    Then status 201
+    And match response.data.is_free == true
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(2)
+    When method POST
+    Then status 201
+
+    Given path '/requests/check'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(3)
+    When method POST
+    Then status 200
+    And match response.data.allowed == false
+    And match response.data.quota contains { exempt: false, open_free: 2 }
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(3)
+    When method POST
+    Then status 403
+    And match response.error == 'quota_exceeded'
+
+    # An override lifts the limit
+    Given path '/admin/users', student.userId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    And request { reason: 'Runs the coding club' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(3)
+    When method POST
+    Then status 201
+
+    # Revoking it restores the limit
+    Given path '/admin/users', student.userId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    When method DELETE
+    Then status 200
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request free(4)
+    When method POST
+    Then status 403
+    And match response.error == 'quota_exceeded'
+
+  Scenario: A student resubmitting the same request is blocked
+    * def student = call read('classpath:makeitexist/auth/helpers/login-student.feature')
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request { title: '#(title)', description: '#(description)', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + student.token
+    And request { title: '#(title)', description: '#(description)', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 409
+    And match response.error == 'duplicate_request'
+    And match response.message contains title
+
+  Scenario: Admins grant and revoke quota overrides
+    Given path '/admin/users', adminId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    And request { reason: 'Hackathon organiser', expires_at: '#(java.time.OffsetDateTime.now().plusDays(30).toString())' }
+    When method PUT
+    Then status 200
+    And match response.data.reason == 'Hackathon organiser'
+
+    Given path '/admin/users', adminId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.user_id == adminId
+
+    Given path '/admin/users', adminId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    When method DELETE
+    Then status 200
+
+    Given path '/admin/users', adminId, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    When method DELETE
+    Then status 404
+
+  Scenario Outline: Invalid overrides are rejected - <description>
+    Given path '/admin/users', <userId>, 'quota-override'
+    And header Authorization = 'Bearer ' + authToken
+    And request <body>
+    When method PUT
+    Then status <expected>
+
+    Examples:
+      | description    | userId                                 | body                                                     | expected |
+      | missing reason | adminId                                | {}                                                       | 400      |
+      | expired        | adminId                                | { reason: 'late', expires_at: '2020-01-01T00:00:00Z' }   | 400      |
+      | unknown user   | '00000000-0000-0000-0000-000000000000' | { reason: 'ghost' }                                      | 404      |
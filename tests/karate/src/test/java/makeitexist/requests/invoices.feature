@@ -0,0 +1,112 @@
+Feature: GST invoices and receipts
+  Completed paid requests get a numbered tax invoice; every captured payment
+  gets a receipt voucher
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def validUntil = java.time.OffsetDateTime.now().plusDays(7).toString()
+
+  Scenario: Completing a paid request issues an invoice and receipt
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Invoiced Club Site', description: 'Club site with events', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true, lines: [ { code: 'custom', label: 'Club website', amount: 1180 } ] }
+    When method POST
+    Then status 201
+    * def quoteId = response.data.id
+
+    Given path '/requests', requestId, 'quotes', quoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    * def orderId = response.data.provider_order_id
+    * call read('classpath:makeitexist/requests/helpers/capture-payment.feature') { orderId: '#(orderId)', paymentId: '#("pay_inv_" + requestId.substring(0, 8))', amount: 1180 }
+
+    # Nothing is invoiced until completion
+    Given path '/requests', requestId, 'invoices'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data == []
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'review' }
+    When method PUT
+    Then status 200
+
+    # The student's sign-off completes the request
+    Given path '/requests', requestId, 'acceptance'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/requests', requestId, 'invoices'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data == '#[2]'
+    And match each response.data contains { number: '#regex ^MIER?/\\d{2}-\\d{2}/\\d{5}$' }
+    # GST caps document numbers at 16 characters
+    And match each response.data contains { number: '#? _.length <= 16' }
+    * def taxInvoice = karate.filter(response.data, function(x){ return x.kind == 'invoice' })[0]
+    And match taxInvoice.total == 1180
+    And match taxInvoice.taxable_amount == 1000
+    * def gst = taxInvoice.cgst + taxInvoice.sgst + taxInvoice.igst
+    And match gst == 180
+    # Supplied within the seller's state: CGST and SGST, no IGST
+    And match taxInvoice contains { cgst: 90, sgst: 90, igst: 0 }
+    # One payment covered the whole quote
+    * def receipt = karate.filter(response.data, function(x){ return x.kind == 'receipt' })[0]
+    And match receipt.lines[0].description == 'Payment in full: Invoiced Club Site'
+
+    # Reissuing is idempotent
+    Given path '/admin/requests', requestId, 'invoices'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data == '#[2]'
+
+    Given path '/requests', requestId, 'invoices', taxInvoice.id, 'pdf'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match header Content-Type contains 'application/pdf'
+    * def magic = new java.lang.String(responseBytes, 0, 5)
+    And match magic == '%PDF-'
+    * def pdfText = new java.lang.String(responseBytes, 'ISO-8859-1')
+    And match pdfText contains 'CGST @ 9% + SGST @ 9%'
+    And match pdfText !contains 'IGST @'
+
+  Scenario: Free requests are not invoiced
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Free Static Site', description: 'Simple website', request_type: 'website', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/admin/requests', requestId, 'invoices'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 400
@@ -0,0 +1,350 @@
+Feature: Payments ledger
+  Paid requests collect payment against the accepted quote; provider webhooks
+  are the source of truth for what has been paid
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def webhookSecret = 'dev-webhook-secret'
+    * def validUntil = java.time.OffsetDateTime.now().plusDays(7).toString()
+    * def sign =
+      """
+      function(body) {
+        var Mac = Java.type('javax.crypto.Mac');
+        var SecretKeySpec = Java.type('javax.crypto.spec.SecretKeySpec');
+        var StandardCharsets = Java.type('java.nio.charset.StandardCharsets');
+        var mac = Mac.getInstance('HmacSHA256');
+        mac.init(new SecretKeySpec(webhookSecret.getBytes(StandardCharsets.UTF_8), 'HmacSHA256'));
+        var digest = mac.doFinal(body.getBytes(StandardCharsets.UTF_8));
+        var hex = '';
+        for (var i = 0; i < digest.length; i++) {
+          hex += ('0' + (digest[i] & 0xff).toString(16)).slice(-2);
+        }
+        return hex;
+      }
+      """
+    * def capturedEvent =
+      """
+      function(orderId, paymentId, amount) {
+        var event = { event: 'payment.captured', payload: { payment: { entity: {
+          id: paymentId, order_id: orderId, amount: Math.round(amount * 100), currency: 'INR',
+          created_at: Math.floor(java.lang.System.currentTimeMillis() / 1000) } } } };
+        return JSON.stringify(event);
+      }
+      """
+    # A paid request with an accepted quote
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Paid Portfolio Site', description: 'Portfolio with CMS', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true, lines: [ { code: 'custom', label: 'Custom scope', amount: 2000 } ] }
+    When method POST
+    Then status 201
+    * def quoteId = response.data.id
+
+    Given path '/requests', requestId, 'quotes', quoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+
+  Scenario: Unpaid request has the full quote outstanding
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.standing == 'unpaid'
+    And match response.data.amount_due == 2000
+    And match response.data.balance == 2000
+
+  Scenario: A paid request quoted at zero is not paid without a waiver
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Zero Quote App', description: 'Quoted at nothing by mistake', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def zeroRequestId = response.data.id
+
+    Given path '/admin/requests', zeroRequestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true, lines: [ { code: 'custom', label: 'Custom scope', amount: 0 } ] }
+    When method POST
+    Then status 201
+    * def zeroQuoteId = response.data.id
+
+    Given path '/requests', zeroRequestId, 'quotes', zeroQuoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/requests', zeroRequestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.amount_due == 0
+    And match response.data.standing == 'unpaid'
+
+  Scenario Outline: Invalid payment amounts are rejected - <description>
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request { amount: <amount> }
+    When method POST
+    Then status 400
+
+    Examples:
+      | description      | amount |
+      | more than due    | 2500   |
+      | negative         | -10    |
+
+  Scenario: Partial then full payment via signed webhooks
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request { amount: 500 }
+    When method POST
+    Then status 201
+    And match response.data.status == 'created'
+    * def firstOrder = response.data.provider_order_id
+
+    * def body = capturedEvent(firstOrder, 'pay_karate_' + requestId.substring(0, 8) + '_1', 500)
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+    And match response.applied == 1
+
+    # Providers retry deliveries; replays are ignored
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+    And match response.applied == 0
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.standing == 'partially_paid'
+    And match response.data.balance == 1500
+
+    # Deploying is blocked until the balance is cleared
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'deploying' }
+    When method PUT
+    Then status 409
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    And match response.data.amount == 1500
+    * def secondOrder = response.data.provider_order_id
+
+    * def body = capturedEvent(secondOrder, 'pay_karate_' + requestId.substring(0, 8) + '_2', 1500)
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.standing == 'paid_in_full'
+    And match response.data.balance == 0
+    And match response.data.ledger == '#[2]'
+
+  Scenario: Refund reopens the balance
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    * def intentId = response.data.id
+    * def paymentId = 'pay_karate_' + requestId.substring(0, 8) + '_r'
+    * def body = capturedEvent(response.data.provider_order_id, paymentId, 2000)
+
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+
+    Given path '/admin/payments', intentId, 'refund'
+    And header Authorization = 'Bearer ' + authToken
+    And request { payment_ref: '#(paymentId)', amount: 300, reason: 'Feature dropped' }
+    When method POST
+    Then status 200
+    And match response.data.kind == 'refund'
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.amount_refunded == 300
+    And match response.data.balance == 300
+
+  Scenario: A refund is capped at what its own payment captured
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    * def intentId = response.data.id
+    * def orderId = response.data.provider_order_id
+    * def bigPayment = 'pay_karate_' + requestId.substring(0, 8) + '_big'
+    * def smallPayment = 'pay_karate_' + requestId.substring(0, 8) + '_small'
+
+    # One order settled by two captures
+    * def body = capturedEvent(orderId, bigPayment, 1200)
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+
+    * def body = capturedEvent(orderId, smallPayment, 800)
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+
+    # The intent holds 2000, but the smaller capture only took 800
+    Given path '/admin/payments', intentId, 'refund'
+    And header Authorization = 'Bearer ' + authToken
+    And request { payment_ref: '#(smallPayment)', amount: 1000, reason: 'Too much' }
+    When method POST
+    Then status 400
+    And match response.message contains '800.00'
+
+    Given path '/admin/payments', intentId, 'refund'
+    And header Authorization = 'Bearer ' + authToken
+    And request { payment_ref: '#(smallPayment)', amount: 500, reason: 'Feature dropped' }
+    When method POST
+    Then status 200
+
+    Given path '/admin/payments', intentId, 'refund'
+    And header Authorization = 'Bearer ' + authToken
+    And request { payment_ref: '#(smallPayment)', amount: 301, reason: 'Too much' }
+    When method POST
+    Then status 400
+    And match response.message contains '300.00'
+
+    Given path '/admin/payments', intentId, 'refund'
+    And header Authorization = 'Bearer ' + authToken
+    And request { payment_ref: '#(bigPayment)', amount: 1200, reason: 'Cancelled' }
+    When method POST
+    Then status 200
+
+  Scenario: Refunds sent at the same time cannot return more than was captured
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    * def intentId = response.data.id
+    * def paymentId = 'pay_karate_' + requestId.substring(0, 8) + '_rush'
+    * def body = capturedEvent(response.data.provider_order_id, paymentId, 2000)
+
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 200
+
+    # Four refunds of 600 against a 2000 capture: only three fit
+    * def refundAll =
+      """
+      function(count) {
+        var HttpClient = Java.type('java.net.http.HttpClient');
+        var HttpRequest = Java.type('java.net.http.HttpRequest');
+        var BodyPublishers = Java.type('java.net.http.HttpRequest$BodyPublishers');
+        var BodyHandlers = Java.type('java.net.http.HttpResponse$BodyHandlers');
+        var URI = Java.type('java.net.URI');
+        var client = HttpClient.newHttpClient();
+        var body = JSON.stringify({ payment_ref: paymentId, amount: 600, reason: 'Rush' });
+        var pending = [];
+        for (var i = 0; i < count; i++) {
+          var req = HttpRequest.newBuilder(URI.create(baseUrl + '/admin/payments/' + intentId + '/refund'))
+            .header('Authorization', 'Bearer ' + authToken)
+            .header('Content-Type', 'application/json')
+            .POST(BodyPublishers.ofString(body))
+            .build();
+          pending.push(client.sendAsync(req, BodyHandlers.ofString()));
+        }
+        var statuses = [];
+        for (var j = 0; j < pending.length; j++) {
+          statuses.push(pending[j].join().statusCode());
+        }
+        return statuses;
+      }
+      """
+    * def statuses = refundAll(4)
+    * def refunded = karate.filter(statuses, function(s){ return s == 200 })
+    * def refused = karate.filter(statuses, function(s){ return s == 400 })
+    * assert refunded.length == 3
+    * assert refused.length == 1
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.amount_refunded == 1800
+
+  Scenario: A payment in another currency is refused, not retried
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 201
+    * def orderId = response.data.provider_order_id
+    * def body = capturedEvent(orderId, 'pay_karate_' + requestId.substring(0, 8) + '_usd', 2000).replace('"INR"', '"USD"')
+
+    Given path '/webhooks/payments/fake'
+    And header X-Razorpay-Signature = sign(body)
+    And header Content-Type = 'application/json'
+    And request body
+    When method POST
+    Then status 422
+    And match response.message contains 'currency mismatch'
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.standing == 'unpaid'
+    And match response.data.ledger == '#[0]'
+
+  Scenario Outline: Webhooks are rejected - <description>
+    Given path '/webhooks/payments', '<provider>'
+    And header X-Razorpay-Signature = '<signature>'
+    And header Content-Type = 'application/json'
+    And request '{"event":"payment.captured","payload":{}}'
+    When method POST
+    Then status <status>
+
+    Examples:
+      | description      | provider | signature | status |
+      | bad signature    | fake     | deadbeef  | 401    |
+      | unknown provider | stripe   | deadbeef  | 404    |
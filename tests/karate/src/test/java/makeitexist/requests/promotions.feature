@@ -0,0 +1,190 @@
+Feature: Discount codes, scholarships and fee waivers
+  Students redeem codes on their own paid requests; staff grant waivers.
+  Discounts show as negative lines in the price breakdown.
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def suffix = java.util.UUID.randomUUID().toString().substring(0, 8).toUpperCase()
+    * def validUntil = java.time.OffsetDateTime.now().plusDays(7).toString()
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Discounted App', description: 'Club app', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+    * def fullPrice = response.data.estimated_cost
+
+  Scenario: Percent code takes a negative line off the breakdown
+    Given path '/admin/promotions/codes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("launch" + suffix)', kind: 'percent', percent: 10, max_uses: 5 }
+    When method POST
+    Then status 201
+    And match response.data.code == 'LAUNCH' + suffix
+    And match response.data.used_count == 0
+
+    Given path '/requests', requestId, 'discounts'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("launch" + suffix)' }
+    When method POST
+    Then status 201
+    And match response.data.source == 'code'
+    * def off = response.data.applied_amount
+    And assert off > 0
+
+    Given path '/requests', requestId, 'pricing'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.lines[*].code contains 'discount_launch' + suffix.toLowerCase()
+    And match response.data.subtotal == fullPrice
+    And assert Math.round(response.data.total * 100) == Math.round((fullPrice - off) * 100)
+
+    # Only one code per request
+    Given path '/requests', requestId, 'discounts'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("launch" + suffix)' }
+    When method POST
+    Then status 400
+
+  Scenario: Students only see the price breakdown of their own requests
+    * def owner = call read('classpath:makeitexist/auth/helpers/login-student.feature')
+    * def other = call read('classpath:makeitexist/auth/helpers/login-student.feature')
+    * def created = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Priced portfolio', token: '#(owner.token)' }
+
+    Given path '/requests', created.requestId, 'pricing'
+    And header Authorization = 'Bearer ' + owner.token
+    When method GET
+    Then status 200
+    And match response.data.lines == '#array'
+
+    Given path '/requests', created.requestId, 'pricing'
+    And header Authorization = 'Bearer ' + other.token
+    When method GET
+    Then status 403
+    And match response.error == 'forbidden'
+
+  Scenario: Unknown, exhausted and inactive codes are refused
+    Given path '/requests', requestId, 'discounts'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: 'NOPE-NOT-A-CODE' }
+    When method POST
+    Then status 404
+
+    Given path '/admin/promotions/codes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("off" + suffix)', kind: 'fixed', amount: 500 }
+    When method POST
+    Then status 201
+    * def codeId = response.data.id
+
+    Given path '/admin/promotions/codes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("off" + suffix)', kind: 'fixed', amount: 500 }
+    When method POST
+    Then status 409
+
+    Given path '/admin/promotions/codes', codeId
+    And header Authorization = 'Bearer ' + authToken
+    And request { active: false }
+    When method PATCH
+    Then status 200
+    And match response.data.active == false
+
+    Given path '/requests', requestId, 'discounts'
+    And header Authorization = 'Bearer ' + authToken
+    And request { code: '#("off" + suffix)' }
+    When method POST
+    Then status 400
+
+  Scenario Outline: Invalid codes are rejected - <description>
+    Given path '/admin/promotions/codes'
+    And header Authorization = 'Bearer ' + authToken
+    And request <body>
+    When method POST
+    Then status 400
+
+    Examples:
+      | description        | body                                                          |
+      | percent over 100   | { code: 'BAD1', kind: 'percent', percent: 150 }               |
+      | zero fixed amount  | { code: 'BAD2', kind: 'fixed', amount: 0 }                    |
+      | unknown kind       | { code: 'BAD3', kind: 'bogo' }                                |
+      | unknown type       | { code: 'BAD4', kind: 'fixed', amount: 1, request_types: ['kiosk'] } |
+
+  Scenario: Full waiver makes a paid request paid in full, and removing it restores the price
+    Given path '/admin/requests', requestId, 'waivers'
+    And header Authorization = 'Bearer ' + authToken
+    And request { kind: 'percent', percent: 100, label: 'Merit scholarship', reason: 'Hackathon winner' }
+    When method POST
+    Then status 201
+    And match response.data.source == 'waiver'
+    And match response.data.applied_amount == fullPrice
+    * def waiverId = response.data.id
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.estimated_cost == 0
+
+    Given path '/requests', requestId, 'payments'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.standing == 'paid_in_full'
+
+    Given path '/admin/requests', requestId, 'discounts', waiverId
+    And header Authorization = 'Bearer ' + authToken
+    When method DELETE
+    Then status 200
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data.estimated_cost == fullPrice
+
+  Scenario: Waivers need a reason
+    Given path '/admin/requests', requestId, 'waivers'
+    And header Authorization = 'Bearer ' + authToken
+    And request { kind: 'fixed', amount: 100 }
+    When method POST
+    Then status 400
+
+  Scenario: The price is locked once a quote is accepted
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true }
+    When method POST
+    Then status 201
+    * def quoteId = response.data.id
+
+    Given path '/requests', requestId, 'quotes', quoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+
+    Given path '/admin/requests', requestId, 'waivers'
+    And header Authorization = 'Bearer ' + authToken
+    And request { kind: 'fixed', amount: 100, reason: 'Late scholarship' }
+    When method POST
+    Then status 409
+
+  Scenario: Promotion report totals discounts given
+    Given path '/admin/promotions/report'
+    And header Authorization = 'Bearer ' + authToken
+    And param from = '2026-01-01'
+    When method GET
+    Then status 200
+    And match response.data.currency == 'INR'
+    And match response.data.items == '#array'
+
+    Given path '/admin/promotions/report'
+    And header Authorization = 'Bearer ' + authToken
+    And param from = 'yesterday'
+    When method GET
+    Then status 400
@@ -0,0 +1,102 @@
+Feature: Build queue position
+  Queued requests are ranked by the queue policy; students can see their position and estimated weekend
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def adminId = loginResult.user.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Queue First', description: 'Queued first', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def firstId = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Queue Second', description: 'Queued second', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def secondId = response.data.id
+
+  Scenario: Pending requests have no queue position
+    Given path '/requests', firstId, 'queue-position'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 409
+    And match response.error == 'not_queued'
+
+  Scenario: Queued requests report their position and estimated weekend
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(firstId)', '#(secondId)'], operation: 'set_status', status: 'queued' }
+    When method POST
+    Then status 200
+
+    Given path '/requests', firstId, 'queue-position'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data contains { request_id: '#(firstId)', position: '#number', queue_length: '#number', estimated_hours: '#number', deferred: '#boolean', final_year: false }
+    And assert response.data.position >= 1 && response.data.position <= response.data.queue_length
+    * def firstPosition = response.data.position
+
+    # The same student's next request waits behind the first
+    Given path '/requests', secondId, 'queue-position'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And assert response.data.position > firstPosition
+    And match response.data.deferred == true
+
+    Given path '/admin/queue'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def ids = karate.map(response.data, function(e){ return e.request_id })
+    And match ids contains firstId
+    And match ids contains secondId
+
+  Scenario: Final-year students get a boost
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(firstId)'], operation: 'set_status', status: 'queued' }
+    When method POST
+    Then status 200
+
+    * def thisYear = java.time.Year.now().getValue()
+    Given path '/admin/users', adminId, 'graduation-year'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { graduation_year: '#(thisYear)' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests', firstId, 'queue-position'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.final_year == true
+
+    Given path '/admin/users', adminId, 'graduation-year'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { graduation_year: null }
+    When method PUT
+    Then status 200
+
+  Scenario Outline: Queue endpoints reject <case>
+    Given path '/requests', '<id>', 'queue-position'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status <expected>
+
+    Examples:
+      | case           | id                                   | expected |
+      | a bad ID       | not-a-uuid                           | 400      |
+      | an unknown ID  | 00000000-0000-0000-0000-000000000000 | 404      |
+
+  Scenario: Queue position requires authentication
+    Given path '/requests', firstId, 'queue-position'
+    When method GET
+    Then status 401
@@ -0,0 +1,101 @@
+Feature: Quote-and-approve workflow
+  Paid requests need an accepted quote before they can be queued or scheduled
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def authToken = loginResult.token
+    * def validUntil = java.time.OffsetDateTime.now().plusDays(7).toString()
+    # A paid request owned by the logged-in user
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + authToken
+    And request { title: 'Quoted Events App', description: 'Campus events app', request_type: 'mobile_app', hosting_type: 'replit' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+  Scenario: Paid request cannot be queued without an accepted quote
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'queued' }
+    When method PUT
+    Then status 409
+
+  Scenario: Draft, send, accept, then queue
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', notes: 'Includes push notifications' }
+    When method POST
+    Then status 201
+    And match response.data.status == 'draft'
+    And match response.data.revision == 1
+    And match response.data.lines == '#[_ > 0]'
+    * def quoteId = response.data.id
+
+    # Students do not see drafts
+    Given path '/requests', requestId, 'quotes', quoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 404
+
+    Given path '/admin/quotes', quoteId, 'send'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 200
+    And match response.data.status == 'sent'
+
+    Given path '/requests', requestId, 'quotes', quoteId, 'accept'
+    And header Authorization = 'Bearer ' + authToken
+    And request { note: 'Looks good' }
+    When method POST
+    Then status 200
+    And match response.data.status == 'accepted'
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(authToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + authToken
+    And header If-Match = current.etag
+    And request { status: 'queued' }
+    When method PUT
+    Then status 200
+    And match response.data.status == 'queued'
+
+  Scenario: Revised quotes keep their history
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true }
+    When method POST
+    Then status 201
+    * def firstQuoteId = response.data.id
+
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '#(validUntil)', send: true, lines: [ { code: 'custom', label: 'Custom scope', amount: 3999 } ] }
+    When method POST
+    Then status 201
+    And match response.data.revision == 2
+    And match response.data.total == 3999
+
+    Given path '/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    When method GET
+    Then status 200
+    And match response.data == '#[2]'
+    And match response.data[1].status == 'superseded'
+
+    Given path '/requests', requestId, 'quotes', firstQuoteId, 'decline'
+    And header Authorization = 'Bearer ' + authToken
+    And request {}
+    When method POST
+    Then status 409
+
+  Scenario: Quotes with a past validity date are rejected
+    Given path '/admin/requests', requestId, 'quotes'
+    And header Authorization = 'Bearer ' + authToken
+    And request { valid_until: '2020-01-01T00:00:00Z' }
+    When method POST
+    Then status 400
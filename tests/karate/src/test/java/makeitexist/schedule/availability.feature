@@ -0,0 +1,209 @@
+Feature: Builder availability
+  Builders declare the weekend days they can build, as weekly patterns and
+  single-day entries; slot hours follow the declared hours and bookings
+  wait for the assigned builder to be available
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+    * def adminId = loginResult.user.id
+
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method POST
+    Then status 200
+
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def sundays = karate.filter(response.data, function(s){ return s.day_of_week == 'sunday' && s.booked_projects == 0 })
+    * assert sundays.length > 0
+    * def sunday = sundays[0]
+    * def date = sunday.date.substring(0, 10)
+
+  Scenario: Availability endpoints need a staff login
+    Given path '/admin/availability'
+    When method GET
+    Then status 401
+
+  Scenario: Declared hours set the slot's capacity
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    And request { hours: 3, note: 'Only the morning' }
+    When method PUT
+    Then status 200
+    And match response.data contains { hours: 3, note: 'Only the morning' }
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = date
+    When method GET
+    Then status 200
+    And match response.data.slot.total_hours == 3
+
+    # A partial day counts the hours between from and until
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    And request { from: '10:00', until: '16:00' }
+    When method PUT
+    Then status 200
+    And match response.data contains { hours: 6, from: '10:00', until: '16:00' }
+
+    Given path '/admin/availability'
+    And header Authorization = 'Bearer ' + adminToken
+    And param from = date
+    And param to = date
+    When method GET
+    Then status 200
+    And match response.data.dates == '#[1]'
+    And match response.data.dates[0] contains { day_of_week: 'sunday', hours: 6, declared: true }
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = date
+    When method GET
+    Then status 200
+    And match response.data.slot.total_hours == 6
+
+    # Without any declarations the slot keeps its default hours
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = date
+    When method GET
+    Then status 200
+    And match response.data.slot.total_hours == 8
+
+  Scenario: A weekly pattern applies to every day it covers
+    Given path '/admin/availability/patterns'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { day_of_week: 'sunday', hours: 5, valid_from: '#(date)', valid_until: '#(date)' }
+    When method POST
+    Then status 201
+    And match response.data contains { id: '#uuid', builder_id: '#(adminId)', day_of_week: 'sunday', hours: 5 }
+    * def patternId = response.data.id
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = date
+    When method GET
+    Then status 200
+    And match response.data.slot.total_hours == 5
+
+    Given path '/admin/availability/patterns', patternId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = date
+    When method GET
+    Then status 200
+    And match response.data.slot.total_hours == 8
+
+  Scenario: A request cannot be booked while its builder is away
+    * def created = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Builder Away', token: '#(adminToken)' }
+    * def requestId = created.requestId
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(requestId)'], operation: 'assign_builder', builder_id: '#(adminId)' }
+    When method POST
+    Then status 200
+
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    And request { hours: 0, note: 'Away' }
+    When method PUT
+    Then status 200
+
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(requestId)', slot_id: '#(sunday.id)', estimated_hours: 2 }
+    When method POST
+    Then status 409
+    And match response.message contains 'builder'
+
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+  Scenario: A booked request cannot move to a builder who is away
+    * def created = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Builder Swap', token: '#(adminToken)' }
+    * def requestId = created.requestId
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(requestId)', slot_id: '#(sunday.id)', estimated_hours: 2 }
+    When method POST
+    Then status 201
+    * def entryId = response.data.id
+
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    And request { hours: 1, note: 'Only an hour' }
+    When method PUT
+    Then status 200
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(adminToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { builder_id: '#(adminId)' }
+    When method PUT
+    Then status 409
+    And match response.message contains 'builder'
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(requestId)'], operation: 'assign_builder', builder_id: '#(adminId)' }
+    When method POST
+    Then status 409
+    And match response.data.items[0].error contains 'builder'
+
+    # With the hours back the booking moves to the builder
+    Given path '/admin/availability/days', date
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(requestId)'], operation: 'assign_builder', builder_id: '#(adminId)' }
+    When method POST
+    Then status 200
+
+    Given path '/admin/schedule/entries', entryId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+  Scenario: Unknown patterns return 404
+    Given path '/admin/availability/patterns', '00000000-0000-0000-0000-000000000001'
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 404
+
+  Scenario Outline: Declaring <body> for <day> returns <expected>
+    Given path '/admin/availability/days', <day>
+    And header Authorization = 'Bearer ' + adminToken
+    And request <body>
+    When method PUT
+    Then status <expected>
+
+    Examples:
+      | day          | body                                             | expected |
+      | date         | {}                                               | 400      |
+      | date         | { hours: 25 }                                    | 400      |
+      | date         | { hours: 3, from: '09:00', until: '12:00' }      | 400      |
+      | date         | { from: '12:00', until: '09:00' }                | 400      |
+      | date         | { from: '9am', until: '12:00' }                  | 400      |
+      | '2099-01-05' | { hours: 4 }                                     | 400      |
+      | '2020-01-04' | { hours: 4 }                                     | 400      |
+      | 'not-a-date' | { hours: 4 }                                     | 400      |
@@ -0,0 +1,88 @@
+Feature: Concurrent slot booking
+  Bookings lock the slot and run in one transaction, so simultaneous
+  bookings can never overbook it
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method POST
+    Then status 200
+
+  Scenario: Many admins booking one slot at once fill it exactly
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def empty = karate.filter(response.data, function(s){ return s.booked_projects == 0 })
+    * assert empty.length > 0
+    * def slot = empty[empty.length - 1]
+
+    # More one-hour requests than the slot has project places
+    * def attempts = slot.max_projects + 5
+    * def create = function(i){ return karate.call('classpath:makeitexist/requests/helpers/create-request.feature', { title: 'Slot Rush ' + i, token: adminToken }).requestId }
+    * def requestIds = karate.repeat(attempts, create)
+
+    * def book =
+      """
+      function(ids) {
+        var HttpClient = Java.type('java.net.http.HttpClient');
+        var HttpRequest = Java.type('java.net.http.HttpRequest');
+        var BodyPublishers = Java.type('java.net.http.HttpRequest$BodyPublishers');
+        var BodyHandlers = Java.type('java.net.http.HttpResponse$BodyHandlers');
+        var URI = Java.type('java.net.URI');
+        var client = HttpClient.newHttpClient();
+        var pending = [];
+        for (var i = 0; i < ids.length; i++) {
+          var body = JSON.stringify({ request_id: ids[i], slot_id: slot.id, estimated_hours: 1 });
+          var req = HttpRequest.newBuilder(URI.create(baseUrl + '/admin/schedule/entries'))
+            .header('Authorization', 'Bearer ' + adminToken)
+            .header('Content-Type', 'application/json')
+            .POST(BodyPublishers.ofString(body))
+            .build();
+          pending.push(client.sendAsync(req, BodyHandlers.ofString()));
+        }
+        var results = [];
+        for (var j = 0; j < pending.length; j++) {
+          var res = pending[j].join();
+          results.push({ status: res.statusCode(), body: JSON.parse(res.body()) });
+        }
+        return results;
+      }
+      """
+    * def results = book(requestIds)
+    * def booked = karate.filter(results, function(r){ return r.status == 201 })
+    * def refused = karate.filter(results, function(r){ return r.status == 409 })
+    * assert booked.length == slot.max_projects
+    * assert refused.length == 5
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = slot.date.substring(0, 10)
+    When method GET
+    Then status 200
+    And match response.data.slot contains { booked_hours: '#(slot.max_projects)', booked_projects: '#(slot.max_projects)', status: 'full' }
+    * def active = karate.filter(response.data.entries, function(e){ return e.status == 'scheduled' })
+    * assert active.length == slot.max_projects
+
+    # Release the slot again
+    * def entryIds = karate.map(booked, function(r){ return r.body.data.id })
+    * def unschedule =
+      """
+      function(id) {
+        var HttpClient = Java.type('java.net.http.HttpClient');
+        var HttpRequest = Java.type('java.net.http.HttpRequest');
+        var BodyHandlers = Java.type('java.net.http.HttpResponse$BodyHandlers');
+        var URI = Java.type('java.net.URI');
+        var req = HttpRequest.newBuilder(URI.create(baseUrl + '/admin/schedule/entries/' + id))
+          .header('Authorization', 'Bearer ' + adminToken)
+          .DELETE()
+          .build();
+        return HttpClient.newHttpClient().send(req, BodyHandlers.ofString()).statusCode();
+      }
+      """
+    * def statuses = karate.map(entryIds, unschedule)
+    And match each statuses == 200
@@ -0,0 +1,186 @@
+Feature: Admin schedule entries
+  Booking, moving and releasing requests in weekend slots keeps slot
+  counters, entries and request status in step
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method POST
+    Then status 200
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Schedule Entry', description: 'Booked by an admin', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def requestId = response.data.id
+
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def empty = karate.filter(response.data, function(s){ return s.booked_projects == 0 })
+    * assert empty.length > 1
+    * def firstSlot = empty[empty.length - 1]
+    * def secondSlot = empty[empty.length - 2]
+
+  Scenario: Schedule endpoints are admin only
+    Given path '/admin/schedule/entries'
+    And request { request_id: '#(requestId)', slot_id: '#(firstSlot.id)', estimated_hours: 3 }
+    When method POST
+    Then status 401
+
+  Scenario: Schedule, move and unschedule a request
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(requestId)', slot_id: '#(firstSlot.id)', estimated_hours: 3 }
+    When method POST
+    Then status 201
+    And match response.data contains { request_id: '#(requestId)', slot_id: '#(firstSlot.id)', estimated_hours: 3, status: 'scheduled' }
+    * def entryId = response.data.id
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = firstSlot.date.substring(0, 10)
+    When method GET
+    Then status 200
+    And match response.data.slot contains { booked_hours: 3, booked_projects: 1, status: 'booked' }
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'scheduled'
+    And match response.data.scheduled_weekend contains firstSlot.date.substring(0, 10)
+
+    # Move to another slot: the first gets its hours back
+    Given path '/admin/schedule/entries', entryId
+    And header Authorization = 'Bearer ' + adminToken
+    And request { slot_id: '#(secondSlot.id)' }
+    When method PUT
+    Then status 200
+    And match response.data contains { slot_id: '#(secondSlot.id)', estimated_hours: 3, status: 'scheduled' }
+    * def movedId = response.data.id
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = firstSlot.date.substring(0, 10)
+    When method GET
+    Then status 200
+    And match response.data.slot contains { booked_hours: 0, booked_projects: 0, status: 'available' }
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = secondSlot.date.substring(0, 10)
+    When method GET
+    Then status 200
+    And match response.data.slot contains { booked_hours: 3, booked_projects: 1 }
+
+    # The old entry no longer holds a booking
+    Given path '/admin/schedule/entries', entryId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 409
+
+    Given path '/admin/schedule/entries', movedId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+    And match response.data.status == 'cancelled'
+
+    Given path '/schedule'
+    And header Authorization = 'Bearer ' + adminToken
+    And param date = secondSlot.date.substring(0, 10)
+    When method GET
+    Then status 200
+    And match response.data.slot contains { booked_hours: 0, booked_projects: 0, status: 'available' }
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'queued'
+
+  Scenario: The scheduled weekend cannot be set by hand
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(adminToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { scheduled_weekend: '#(firstSlot.date)' }
+    When method PUT
+    Then status 409
+    And match response.message contains 'schedule endpoints'
+
+  Scenario: Requests cannot be marked scheduled without a booking
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(requestId)', token: '#(adminToken)' }
+    Given path '/admin/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { status: 'scheduled' }
+    When method PUT
+    Then status 409
+    And match response.message contains '/admin/schedule/entries'
+
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(requestId)'], operation: 'set_status', status: 'scheduled' }
+    When method POST
+    Then status 409
+    And match response.error == 'bulk_rejected'
+    And match response.data.items[0].error contains '/admin/schedule/entries'
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'pending'
+
+  Scenario: Booking more hours than the slot has left returns 409
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(requestId)', slot_id: '#(firstSlot.id)', estimated_hours: 8 }
+    When method POST
+    Then status 201
+    * def entryId = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Schedule Overflow', description: 'Does not fit', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def otherId = response.data.id
+
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(otherId)', slot_id: '#(firstSlot.id)', estimated_hours: 1 }
+    When method POST
+    Then status 409
+
+    Given path '/admin/schedule/entries', entryId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+  Scenario Outline: Invalid schedule request '<body>' returns <expected>
+    Given path '/admin/schedule/entries'
+    And header Authorization = 'Bearer ' + adminToken
+    And request <body>
+    When method POST
+    Then status <expected>
+
+    Examples:
+      | body                                                                                              | expected |
+      | { slot_id: '#(firstSlot.id)', estimated_hours: 2 }                                                | 400      |
+      | { request_id: '#(requestId)', slot_id: '#(firstSlot.id)', estimated_hours: 0 }                    | 400      |
+      | { request_id: '00000000-0000-0000-0000-000000000001', slot_id: '#(firstSlot.id)', estimated_hours: 2 } | 404  |
+      | { request_id: '#(requestId)', slot_id: '00000000-0000-0000-0000-000000000001', estimated_hours: 2 } | 404    |
+
+  Scenario: Unknown entries return 404
+    Given path '/admin/schedule/entries', '00000000-0000-0000-0000-000000000001'
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 404
@@ -0,0 +1,20 @@
+Feature: Open Slot Helper
+  Reusable helper that finds an upcoming slot with room for a booking.
+  Expects token and hours; returns slot.
+
+  Background:
+    * url baseUrl
+
+  Scenario: Find an open slot
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + token
+    When method POST
+    Then status 200
+
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + token
+    When method GET
+    Then status 200
+    * def open = karate.filter(response.data, function(s){ return s.booked_projects < s.max_projects && s.booked_hours + hours <= s.total_hours })
+    * assert open.length > 0
+    * def slot = open[open.length - 1]
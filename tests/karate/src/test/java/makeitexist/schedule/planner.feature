@@ -0,0 +1,111 @@
+Feature: Weekend planner
+  Proposes which queued requests go into a weekend's slots, as a diff for
+  admins to review, and applies a reviewed plan in one transaction
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method POST
+    Then status 200
+
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def saturdays = karate.filter(response.data, function(s){ return s.day_of_week == 'saturday' && s.booked_projects == 0 })
+    * assert saturdays.length > 0
+    * def saturday = saturdays[saturdays.length - 1]
+    * def weekend = saturday.date.substring(0, 10)
+
+    * def created = call read('classpath:makeitexist/requests/helpers/create-request.feature') { title: 'Planner Candidate', token: '#(adminToken)' }
+    * def requestId = created.requestId
+    Given path '/admin/requests/bulk'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { ids: ['#(requestId)'], operation: 'set_status', status: 'queued' }
+    When method POST
+    Then status 200
+
+  Scenario: Planner endpoints are admin only
+    Given path '/admin/schedule/plan'
+    When method GET
+    Then status 401
+
+  Scenario: Propose a plan as a per-slot diff
+    Given path '/admin/schedule/plan'
+    And header Authorization = 'Bearer ' + adminToken
+    And param weekend = weekend
+    When method GET
+    Then status 200
+    And match response.data.weekend contains weekend
+    And match each response.data.slots contains { slot_id: '#uuid', version: '#number', before: { booked_hours: '#number', booked_projects: '#number' }, after: { booked_hours: '#number', booked_projects: '#number' }, add: '#array' }
+    And match each response.data.skipped contains { request_id: '#uuid', reason: '#string' }
+    And match each response.data.slots contains { max_projects: '#number', total_hours: '#number' }
+    * def slots = response.data.slots
+    * assert karate.filter(slots, function(s){ return s.after.booked_hours > s.total_hours || s.after.booked_projects > s.max_projects }).length == 0
+
+    # The queued request is either planned or skipped with a reason
+    * def planned = karate.filter(karate.jsonPath(slots, '$[*].add[*]'), function(a){ return a.request_id == requestId })
+    * def skipped = karate.filter(response.data.skipped, function(s){ return s.request_id == requestId })
+    * assert planned.length + skipped.length == 1
+
+  Scenario: Apply a reviewed plan, then refuse it once it is stale
+    Given path '/admin/schedule/plan'
+    And header Authorization = 'Bearer ' + adminToken
+    And param weekend = weekend
+    When method GET
+    Then status 200
+    * def slot = karate.filter(response.data.slots, function(s){ return s.slot_id == saturday.id })[0]
+
+    # The admin keeps only one assignment
+    * def plan = { slots: [{ slot_id: '#(slot.slot_id)', version: '#(slot.version)', add: [{ request_id: '#(requestId)', estimated_hours: 2 }] }] }
+    Given path '/admin/schedule/plan/apply'
+    And header Authorization = 'Bearer ' + adminToken
+    And request plan
+    When method POST
+    Then status 200
+    And match response.data == '#[1]'
+    And match response.data[0] contains { request_id: '#(requestId)', slot_id: '#(slot.slot_id)', estimated_hours: 2, status: 'scheduled' }
+    * def entryId = response.data[0].id
+
+    Given path '/requests', requestId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'scheduled'
+
+    # The slot has moved on, so the same plan is stale
+    Given path '/admin/schedule/plan/apply'
+    And header Authorization = 'Bearer ' + adminToken
+    And request plan
+    When method POST
+    Then status 409
+    And match response.message contains 'propose it again'
+
+    Given path '/admin/schedule/entries', entryId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 200
+
+  Scenario: A plan with no assignments is rejected
+    Given path '/admin/schedule/plan/apply'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { slots: [{ slot_id: '#(saturday.id)', version: '#(saturday.version)', add: [] }] }
+    When method POST
+    Then status 400
+
+  Scenario Outline: Proposing for '<weekend>' returns <expected>
+    Given path '/admin/schedule/plan'
+    And header Authorization = 'Bearer ' + adminToken
+    And param weekend = '<weekend>'
+    When method GET
+    Then status <expected>
+
+    Examples:
+      | weekend    | expected |
+      | 2026-03-06 | 400      |
+      | not-a-date | 400      |
+      | 2020-01-04 | 404      |
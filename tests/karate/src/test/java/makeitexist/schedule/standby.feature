@@ -0,0 +1,152 @@
+Feature: Slot standby
+  Requests wait on standby for a full slot, or for the next available one,
+  and are promoted automatically when capacity frees up
+
+  Background:
+    * url baseUrl
+    * def loginResult = call read('classpath:makeitexist/auth/helpers/login-admin.feature')
+    * def adminToken = loginResult.token
+
+    Given path '/admin/schedule/generate'
+    And header Authorization = 'Bearer ' + adminToken
+    When method POST
+    Then status 200
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Standby First', description: 'Fills the slot', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def firstId = response.data.id
+
+    Given path '/requests'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { title: 'Standby Second', description: 'Waits for the slot', request_type: 'website', hosting_type: 'vercel' }
+    When method POST
+    Then status 201
+    * def secondId = response.data.id
+
+  Scenario: Standby endpoints are admin only
+    Given path '/admin/schedule/standby'
+    When method GET
+    Then status 401
+
+  Scenario: A request with room available is booked straight away
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(firstId)', estimated_hours: 1 }
+    When method POST
+    Then status 201
+    And match response.data.status == 'promoted'
+    And match response.data.promoted_slot_id == '#uuid'
+
+    Given path '/requests', firstId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'scheduled'
+
+    # Free the slot for other scenarios
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(firstId)', token: '#(adminToken)' }
+    Given path '/admin/requests', firstId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { status: 'cancelled' }
+    When method PUT
+    Then status 200
+
+  Scenario: Cancelling a booking promotes the first standby for its slot
+    Given path '/schedule/slots'
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    * def empty = karate.filter(response.data, function(s){ return s.booked_projects == 0 })
+    * assert empty.length > 0
+    * def slotId = empty[empty.length - 1].id
+
+    # The first request takes the whole day
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(firstId)', slot_id: '#(slotId)', estimated_hours: 8 }
+    When method POST
+    Then status 201
+    And match response.data.status == 'promoted'
+
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(secondId)', slot_id: '#(slotId)', estimated_hours: 4 }
+    When method POST
+    Then status 201
+    And match response.data.status == 'waiting'
+    * def standbyId = response.data.id
+
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And param slot_id = slotId
+    When method GET
+    Then status 200
+    * def ids = karate.map(response.data, function(e){ return e.id })
+    And match ids contains standbyId
+
+    # A request can only wait in one place
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And request { request_id: '#(secondId)', estimated_hours: 4 }
+    When method POST
+    Then status 409
+
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(firstId)', token: '#(adminToken)' }
+    Given path '/admin/requests', firstId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { status: 'cancelled' }
+    When method PUT
+    Then status 200
+
+    Given path '/requests', secondId
+    And header Authorization = 'Bearer ' + adminToken
+    When method GET
+    Then status 200
+    And match response.data.status == 'scheduled'
+
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And param slot_id = slotId
+    When method GET
+    Then status 200
+    * def ids = karate.map(response.data, function(e){ return e.id })
+    And match ids !contains standbyId
+
+    Given path '/admin/schedule/standby', standbyId
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 409
+
+    # Free the slot again
+    * def current = call read('classpath:makeitexist/requests/helpers/request-etag.feature') { requestId: '#(secondId)', token: '#(adminToken)' }
+    Given path '/admin/requests', secondId
+    And header Authorization = 'Bearer ' + adminToken
+    And header If-Match = current.etag
+    And request { status: 'cancelled' }
+    When method PUT
+    Then status 200
+
+  Scenario Outline: Invalid standby '<body>' returns <expected>
+    Given path '/admin/schedule/standby'
+    And header Authorization = 'Bearer ' + adminToken
+    And request <body>
+    When method POST
+    Then status <expected>
+
+    Examples:
+      | body                                                                                       | expected |
+      | { estimated_hours: 2 }                                                                     | 400      |
+      | { request_id: '#(firstId)', estimated_hours: 9 }                                           | 400      |
+      | { request_id: '00000000-0000-0000-0000-000000000001', estimated_hours: 2 }                 | 404      |
+      | { request_id: '#(firstId)', slot_id: '00000000-0000-0000-0000-000000000001', estimated_hours: 2 } | 404 |
+
+  Scenario: Cancelling an unknown standby entry returns 404
+    Given path '/admin/schedule/standby', '00000000-0000-0000-0000-000000000001'
+    And header Authorization = 'Bearer ' + adminToken
+    When method DELETE
+    Then status 404